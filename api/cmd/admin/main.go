@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/database"
+	"github.com/sockify/sockify/services/admin"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+	"github.com/sockify/sockify/utils/auth"
+	"golang.org/x/term"
+)
+
+const usage = `Manages admin accounts directly in the database, e.g. to create the first admin.
+
+Usage:
+  go run ./cmd/admin create [-firstname NAME] [-lastname NAME] [-email EMAIL] [-username USERNAME] [-role ROLE]
+  go run ./cmd/admin reset-password [-username USERNAME]
+  go run ./cmd/admin list [-limit N] [-offset N]
+
+Missing values are prompted for. The password is read from the ADMIN_PASSWORD env variable, or prompted for.`
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	connStr := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable TimeZone=UTC connect_timeout=10",
+		config.Envs.DBUser, config.Envs.DBPassword, config.Envs.DBName, config.Envs.DBHost, config.Envs.DBPort,
+	)
+	db, err := database.NewPostgreSQLStorage(connStr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Unable to connect to the database at %v:%v: %v", config.Envs.DBHost, config.Envs.DBPort, err)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "create":
+		err = createAdmin(admin.NewStore(db), args)
+	case "reset-password":
+		err = resetPassword(db, args)
+	case "list":
+		err = listAdmins(admin.NewStore(db), args)
+	default:
+		err = fmt.Errorf("unknown command: %s\n\n%s", command, usage)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// createAdmin creates an admin with the same validation as `POST /admins/register`. Admins are owners by default.
+func createAdmin(store types.AdminStore, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	firstName := flags.String("firstname", "", "first name")
+	lastName := flags.String("lastname", "", "last name")
+	email := flags.String("email", "", "email")
+	username := flags.String("username", "", "username")
+	role := flags.String("role", string(types.RoleOwner), "owner, inventory_manager, fulfillment or read_only")
+	flags.Parse(args)
+
+	payload := types.RegisterAdminRequest{
+		FirstName: promptIfEmpty(*firstName, "First name"),
+		LastName:  promptIfEmpty(*lastName, "Last name"),
+		Email:     utils.Normalize(promptIfEmpty(*email, "Email")),
+		UserName:  utils.Normalize(promptIfEmpty(*username, "Username")),
+		Password:  readNewPassword(),
+		Role:      types.AdminRole(*role),
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		return fmt.Errorf("invalid admin: %v", err)
+	}
+
+	if err := auth.ValidatePassword(payload.Password); err != nil {
+		return fmt.Errorf("password does not meet the minimum requirements: %v", err)
+	}
+
+	if existing, _ := store.GetAdminByUsername(payload.UserName); existing != nil {
+		return fmt.Errorf("username already exists")
+	}
+	if existing, _ := store.GetAdminByEmail(payload.Email); existing != nil {
+		return fmt.Errorf("email already exists")
+	}
+
+	passwordHash, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		return fmt.Errorf("unable to hash password: %v", err)
+	}
+
+	err = store.CreateAdmin(
+		utils.TitleCase(payload.FirstName),
+		utils.TitleCase(payload.LastName),
+		payload.Email,
+		payload.UserName,
+		passwordHash,
+		payload.Role,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create the admin: %v", err)
+	}
+
+	log.Printf("Created admin '%v' with role '%v'", payload.UserName, payload.Role)
+	return nil
+}
+
+// resetPassword sets a new password for an admin, signs them out of every session and lifts any login lockout.
+func resetPassword(db *sql.DB, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	username := flags.String("username", "", "username of the admin")
+	flags.Parse(args)
+
+	store := admin.NewStore(db)
+	name := utils.Normalize(promptIfEmpty(*username, "Username"))
+	existing, err := store.GetAdminByUsername(name)
+	if err != nil || existing == nil {
+		return fmt.Errorf("admin '%v' does not exist", name)
+	}
+
+	password := readNewPassword()
+	if err := auth.ValidatePassword(password); err != nil {
+		return fmt.Errorf("password does not meet the minimum requirements: %v", err)
+	}
+
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("unable to hash password: %v", err)
+	}
+
+	if err := store.UpdateAdminPassword(existing.ID, passwordHash); err != nil {
+		return fmt.Errorf("unable to update the password: %v", err)
+	}
+
+	throttler := admin.NewLoginThrottler(
+		admin.NewLoginThrottleStore(db),
+		int(config.Envs.LoginMaxFailuresPerUsername),
+		int(config.Envs.LoginMaxFailuresPerIP),
+		time.Duration(config.Envs.LoginLockoutInSeconds)*time.Second,
+	)
+	if err := throttler.Unlock(name); err != nil {
+		log.Printf("Password updated, but unable to lift the login lockout: %v", err)
+	}
+
+	if !existing.IsActive {
+		log.Printf("Note: admin '%v' is deactivated and still can't log in until reactivated", name)
+	}
+
+	log.Printf("Reset the password of admin '%v'", name)
+	return nil
+}
+
+func listAdmins(store types.AdminStore, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	limit := flags.Int("limit", 50, "maximum number of admins to list")
+	offset := flags.Int("offset", 0, "number of admins to skip")
+	flags.Parse(args)
+
+	admins, total, err := store.GetAdmins(*limit, *offset)
+	if err != nil {
+		return fmt.Errorf("unable to list admins: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tNAME\tEMAIL\tROLE\tACTIVE\tMFA\tCREATED")
+	for _, a := range admins {
+		fmt.Fprintf(w, "%d\t%s\t%s %s\t%s\t%s\t%t\t%t\t%s\n",
+			a.ID, a.Username, a.FirstName, a.LastName, a.Email, a.Role, a.IsActive, a.MFAEnabled, a.CreatedAt.Format(time.DateOnly),
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\nShowing %d of %d admins\n", len(admins), total)
+	return nil
+}
+
+func promptIfEmpty(value string, label string) string {
+	for strings.TrimSpace(value) == "" {
+		fmt.Printf("%s: ", label)
+		value = readLine()
+	}
+	return strings.TrimSpace(value)
+}
+
+// readNewPassword returns `ADMIN_PASSWORD` if set, otherwise prompts for the password twice.
+func readNewPassword() string {
+	if password, ok := os.LookupEnv("ADMIN_PASSWORD"); ok {
+		return password
+	}
+
+	for {
+		password := readHidden("Password: ")
+		if password == readHidden("Confirm password: ") {
+			return password
+		}
+		fmt.Println("Passwords do not match, try again.")
+	}
+}
+
+// readHidden reads a line without echoing it, when stdin is a terminal.
+func readHidden(prompt string) string {
+	fmt.Print(prompt)
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return readLine()
+	}
+
+	password, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		log.Fatalf("Unable to read the password: %v", err)
+	}
+	return string(password)
+}
+
+func readLine() string {
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatal("Unable to read input: ", err)
+	}
+	return strings.TrimRight(line, "\r\n")
+}
@@ -10,6 +10,7 @@ import (
 	_ "github.com/sockify/sockify/docs"
 	"github.com/sockify/sockify/middleware"
 	"github.com/sockify/sockify/routes"
+	"github.com/sockify/sockify/utils"
 	"github.com/sockify/sockify/utils/logging"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -44,8 +45,9 @@ func (s *Server) Run() error {
 	corsHandler := handlers.CORS(
 		handlers.AllowedOrigins([]string{config.Envs.WebClientURL, config.Envs.APIURL}),
 		handlers.AllowedMethods([]string{"GET", "POST", "PUT", "DELETE", "PATCH"}),
-		handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
-	)(loggedRouter)
+		handlers.AllowedHeaders([]string{"Content-Type", "Authorization", "If-Match", utils.RequestIDHeader}),
+		handlers.ExposedHeaders([]string{"ETag", "Retry-After", utils.RequestIDHeader}),
+	)(middleware.WithRequestID(loggedRouter))
 
 	log.Println("Server listening on port", s.addr)
 	return http.ListenAndServe(s.addr, corsHandler)
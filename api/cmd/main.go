@@ -1,17 +1,22 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"time"
 
 	"github.com/sockify/sockify/cmd/api"
 	"github.com/sockify/sockify/config"
 	"github.com/sockify/sockify/database"
+	"github.com/sockify/sockify/services/admin"
+	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/services/inventory"
+	"github.com/sockify/sockify/services/orders"
 	"github.com/sockify/sockify/utils/logging"
-	"github.com/stripe/stripe-go/v80"
 )
 
 // @title           Sockify API
@@ -27,8 +32,6 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token. Example: "Bearer XXX"
 func main() {
-	stripe.Key = config.Envs.StripeAPIKey
-
 	connStr := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=disable TimeZone=UTC connect_timeout=10",
 		config.Envs.DBUser, config.Envs.DBPassword, config.Envs.DBName, config.Envs.DBHost, config.Envs.DBPort,
 	)
@@ -42,6 +45,14 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 
+	mailer, err := email.NewMailer()
+	if err != nil {
+		log.Fatalf("Unable to configure the mailer: %v", err)
+	}
+
+	ctx, stopWorkers := context.WithCancel(context.Background())
+	startWorkers(ctx, db, mailer)
+
 	server := api.NewServer(":"+config.Envs.APIPort, db, httpLogger)
 	go func() {
 		if err = server.Run(); err != nil {
@@ -51,11 +62,39 @@ func main() {
 
 	<-quit
 	log.Println("Shutting down server...")
+	stopWorkers()
 	httpLogger.Close()
 
 	log.Println("Server gracefully stopped.")
 }
 
+// startWorkers starts all background workers. They stop once the context is canceled.
+func startWorkers(ctx context.Context, db *sql.DB, mailer email.Mailer) {
+	sockStore := inventory.NewSockStore(db)
+	orderStore := orders.NewOrderStore(db, sockStore)
+	reaper := orders.NewPendingOrderReaper(
+		orderStore,
+		time.Duration(config.Envs.OrderReaperIntervalInSeconds)*time.Second,
+		time.Duration(config.Envs.CheckoutExpirationInSeconds)*time.Second,
+	)
+	go reaper.Run(ctx)
+
+	emailService := email.NewService(mailer)
+	dispatcher := email.NewOutboxDispatcher(
+		email.NewOutboxStore(db),
+		&emailService,
+		time.Duration(config.Envs.EmailOutboxIntervalInSeconds)*time.Second,
+	)
+	go dispatcher.Run(ctx)
+
+	lowStockMonitor := inventory.NewLowStockMonitor(
+		sockStore,
+		admin.NewStore(db),
+		time.Duration(config.Envs.LowStockCheckIntervalInSeconds)*time.Second,
+	)
+	go lowStockMonitor.Run(ctx)
+}
+
 func initStorage(db *sql.DB) {
 	err := db.Ping()
 	if err != nil {
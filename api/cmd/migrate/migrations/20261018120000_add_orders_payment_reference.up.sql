@@ -0,0 +1 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_reference VARCHAR(255);
\ No newline at end of file
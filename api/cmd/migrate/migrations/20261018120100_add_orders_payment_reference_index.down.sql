@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_payment_reference_idx;
\ No newline at end of file
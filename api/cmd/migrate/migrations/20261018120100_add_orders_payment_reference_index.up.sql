@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS orders_payment_reference_idx ON orders(payment_reference);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DELETE FROM order_updates WHERE admin_id IS NULL;
+ALTER TABLE order_updates ALTER COLUMN admin_id SET NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE order_updates ALTER COLUMN admin_id DROP NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS order_refunds;
\ No newline at end of file
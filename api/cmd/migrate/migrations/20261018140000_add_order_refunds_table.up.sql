@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS order_refunds (
+    order_refund_id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    -- NULL when issued by the system
+    admin_id INTEGER,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount >= 0.01),
+    reason TEXT NOT NULL,
+    restocked BOOLEAN NOT NULL DEFAULT false,
+    -- Refund ID within the payment provider
+    provider_refund_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(order_id) ON DELETE CASCADE,
+    FOREIGN KEY (admin_id) REFERENCES admins(admin_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS order_refund_items;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS order_refund_items (
+    order_refund_item_id SERIAL PRIMARY KEY,
+    order_refund_id INTEGER NOT NULL,
+    order_item_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity >= 1),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount >= 0.01),
+    FOREIGN KEY (order_refund_id) REFERENCES order_refunds(order_refund_id) ON DELETE CASCADE,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(order_item_id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS order_refunds_order_id_idx;
\ No newline at end of file
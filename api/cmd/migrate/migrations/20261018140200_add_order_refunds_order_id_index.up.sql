@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS order_refunds_order_id_idx ON order_refunds(order_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS order_refund_items_order_item_id_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS order_refund_items_order_item_id_idx ON order_refund_items(order_item_id);
\ No newline at end of file
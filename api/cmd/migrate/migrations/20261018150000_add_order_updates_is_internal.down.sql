@@ -0,0 +1 @@
+ALTER TABLE order_updates DROP COLUMN IF EXISTS is_internal;
\ No newline at end of file
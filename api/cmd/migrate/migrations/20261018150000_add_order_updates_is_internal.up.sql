@@ -0,0 +1 @@
+ALTER TABLE order_updates ADD COLUMN IF NOT EXISTS is_internal BOOLEAN NOT NULL DEFAULT true;
\ No newline at end of file
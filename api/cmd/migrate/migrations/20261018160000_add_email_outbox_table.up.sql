@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS email_outbox (
+    email_outbox_id SERIAL PRIMARY KEY,
+    to_name VARCHAR(255) NOT NULL,
+    to_email VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    plain_text TEXT NOT NULL,
+    html_content TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL until the email has been delivered to the email provider
+    sent_at TIMESTAMP,
+    -- Set once all delivery attempts are exhausted
+    failed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS email_outbox_pending_idx;
\ No newline at end of file
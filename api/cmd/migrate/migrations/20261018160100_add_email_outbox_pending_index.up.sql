@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS email_outbox_pending_idx ON email_outbox(next_attempt_at) WHERE sent_at IS NULL AND failed_at IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE socks DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
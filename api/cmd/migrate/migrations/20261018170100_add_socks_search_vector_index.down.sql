@@ -0,0 +1 @@
+DROP INDEX IF EXISTS socks_search_vector_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS socks_search_vector_idx ON socks USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_status_idx;
\ No newline at end of file
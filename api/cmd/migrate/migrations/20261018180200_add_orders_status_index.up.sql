@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS orders_status_idx ON orders(status);
\ No newline at end of file
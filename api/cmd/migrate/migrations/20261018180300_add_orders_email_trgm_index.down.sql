@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_email_trgm_idx;
\ No newline at end of file
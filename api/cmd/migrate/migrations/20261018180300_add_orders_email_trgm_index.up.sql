@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS orders_email_trgm_idx ON orders USING GIN (email gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS orders_name_trgm_idx ON orders USING GIN ((firstname || ' ' || lastname) gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_invoice_number_pattern_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS orders_invoice_number_pattern_idx ON orders(invoice_number text_pattern_ops);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS customers;
\ No newline at end of file
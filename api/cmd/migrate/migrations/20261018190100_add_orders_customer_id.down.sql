@@ -0,0 +1 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_id;
\ No newline at end of file
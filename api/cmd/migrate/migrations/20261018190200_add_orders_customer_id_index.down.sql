@@ -0,0 +1 @@
+DROP INDEX IF EXISTS orders_customer_id_idx;
\ No newline at end of file
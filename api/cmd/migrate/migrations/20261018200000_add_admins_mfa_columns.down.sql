@@ -0,0 +1,4 @@
+ALTER TABLE admins
+    DROP COLUMN IF EXISTS mfa_enabled,
+    DROP COLUMN IF EXISTS mfa_secret,
+    DROP COLUMN IF EXISTS mfa_last_used_step;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE admins
+    ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT false,
+    -- Base32 TOTP secret, set during enrollment
+    ADD COLUMN IF NOT EXISTS mfa_secret VARCHAR(64),
+    -- Last accepted TOTP time step, so a code can not be replayed
+    ADD COLUMN IF NOT EXISTS mfa_last_used_step BIGINT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS admin_recovery_codes (
+    admin_recovery_code_id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL,
+    -- SHA-256 hash of the recovery code
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (admin_id, code_hash),
+    FOREIGN KEY (admin_id) REFERENCES admins(admin_id) ON DELETE CASCADE
+);
\ No newline at end of file
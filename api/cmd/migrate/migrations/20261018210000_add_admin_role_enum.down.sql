@@ -0,0 +1 @@
+DROP TYPE IF EXISTS admin_role;
\ No newline at end of file
@@ -0,0 +1,13 @@
+DO $$ BEGIN IF NOT EXISTS (
+    SELECT 1
+    FROM pg_type
+    WHERE typname = 'admin_role'
+) THEN CREATE TYPE admin_role AS ENUM (
+    -- Full access, including managing admins
+    'owner',
+    'inventory_manager',
+    'fulfillment',
+    'read_only'
+);
+END IF;
+END $$;
\ No newline at end of file
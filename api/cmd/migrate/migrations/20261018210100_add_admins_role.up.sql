@@ -0,0 +1,2 @@
+-- Existing admins keep full access
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS role admin_role NOT NULL DEFAULT 'owner';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE admins ALTER COLUMN role SET DEFAULT 'owner';
\ No newline at end of file
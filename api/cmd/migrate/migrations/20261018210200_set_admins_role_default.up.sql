@@ -0,0 +1 @@
+ALTER TABLE admins ALTER COLUMN role SET DEFAULT 'read_only';
\ No newline at end of file
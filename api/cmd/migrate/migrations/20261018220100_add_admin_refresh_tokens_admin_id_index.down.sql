@@ -0,0 +1 @@
+DROP INDEX IF EXISTS admin_refresh_tokens_admin_id_idx;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    -- JWT ID (`jti` claim) of the revoked access token
+    jti VARCHAR(36) PRIMARY KEY,
+    -- Matches the token expiration, the row is useless afterwards
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE admins DROP COLUMN IF EXISTS is_active;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
\ No newline at end of file
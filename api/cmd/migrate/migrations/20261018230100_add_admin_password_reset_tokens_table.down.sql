@@ -0,0 +1 @@
+DROP TABLE IF EXISTS admin_password_reset_tokens;
\ No newline at end of file
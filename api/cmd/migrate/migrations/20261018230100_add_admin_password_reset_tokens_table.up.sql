@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS admin_password_reset_tokens (
+    admin_password_reset_token_id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL,
+    -- SHA-256 hash of the reset token, the token itself is only sent by email
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (admin_id) REFERENCES admins(admin_id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS admin_password_reset_tokens_admin_id_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS admin_password_reset_tokens_admin_id_idx ON admin_password_reset_tokens(admin_id);
\ No newline at end of file
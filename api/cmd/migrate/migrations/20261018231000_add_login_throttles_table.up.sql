@@ -0,0 +1,7 @@
+-- Failed login attempts per key ("username:<name>" or "ip:<address>"), shared by all API replicas
+CREATE TABLE IF NOT EXISTS login_throttles (
+    throttle_key VARCHAR(255) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS failed_admin_logins;
\ No newline at end of file
@@ -0,0 +1,8 @@
+-- Audit trail of failed admin logins
+CREATE TABLE IF NOT EXISTS failed_admin_logins (
+    failed_admin_login_id SERIAL PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    reason VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS failed_admin_logins_username_created_at_idx ON failed_admin_logins(username, created_at);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE socks DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants DROP COLUMN IF EXISTS is_deleted;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD COLUMN IF NOT EXISTS is_deleted BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sock_images;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS sock_images (
+    sock_image_id SERIAL PRIMARY KEY,
+    sock_id INTEGER NOT NULL,
+    image_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (sock_id) REFERENCES socks(sock_id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS sock_images_sock_id_position_idx;
\ No newline at end of file
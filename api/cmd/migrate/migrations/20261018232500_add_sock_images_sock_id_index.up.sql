@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS sock_images_sock_id_position_idx ON sock_images(sock_id, position);
\ No newline at end of file
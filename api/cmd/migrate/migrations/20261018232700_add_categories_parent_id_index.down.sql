@@ -0,0 +1 @@
+DROP INDEX IF EXISTS categories_parent_id_idx;
\ No newline at end of file
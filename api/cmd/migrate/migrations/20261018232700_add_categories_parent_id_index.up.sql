@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS categories_parent_id_idx ON categories(parent_id);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE socks ADD COLUMN IF NOT EXISTS category_id INTEGER REFERENCES categories(category_id) ON DELETE SET NULL;
\ No newline at end of file
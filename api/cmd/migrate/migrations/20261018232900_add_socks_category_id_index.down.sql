@@ -0,0 +1 @@
+DROP INDEX IF EXISTS socks_category_id_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS socks_category_id_idx ON socks(category_id);
\ No newline at end of file
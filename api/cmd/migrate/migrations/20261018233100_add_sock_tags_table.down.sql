@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sock_tags;
\ No newline at end of file
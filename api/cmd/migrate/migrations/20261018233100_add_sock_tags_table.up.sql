@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS sock_tags (
+    sock_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (sock_id, tag_id),
+    FOREIGN KEY (sock_id) REFERENCES socks(sock_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+);
\ No newline at end of file
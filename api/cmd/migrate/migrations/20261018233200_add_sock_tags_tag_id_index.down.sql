@@ -0,0 +1 @@
+DROP INDEX IF EXISTS sock_tags_tag_id_idx;
\ No newline at end of file
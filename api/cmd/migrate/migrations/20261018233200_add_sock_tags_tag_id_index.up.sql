@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS sock_tags_tag_id_idx ON sock_tags(tag_id);
\ No newline at end of file
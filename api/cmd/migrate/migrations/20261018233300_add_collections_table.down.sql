@@ -0,0 +1 @@
+DROP TABLE IF EXISTS collections;
\ No newline at end of file
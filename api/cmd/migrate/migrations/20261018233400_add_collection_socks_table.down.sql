@@ -0,0 +1 @@
+DROP TABLE IF EXISTS collection_socks;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS collection_socks (
+    collection_id INTEGER NOT NULL,
+    sock_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (collection_id, sock_id),
+    FOREIGN KEY (collection_id) REFERENCES collections(collection_id) ON DELETE CASCADE,
+    FOREIGN KEY (sock_id) REFERENCES socks(sock_id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS collection_socks_sock_id_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS collection_socks_sock_id_idx ON collection_socks(sock_id);
\ No newline at end of file
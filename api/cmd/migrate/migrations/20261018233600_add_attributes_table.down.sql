@@ -0,0 +1 @@
+DROP TABLE IF EXISTS attributes;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS attribute_values;
\ No newline at end of file
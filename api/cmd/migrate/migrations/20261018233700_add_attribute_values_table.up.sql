@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS attribute_values (
+    attribute_value_id SERIAL PRIMARY KEY,
+    attribute_id INTEGER NOT NULL,
+    value VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (attribute_id) REFERENCES attributes(attribute_id) ON DELETE CASCADE
+);
\ No newline at end of file
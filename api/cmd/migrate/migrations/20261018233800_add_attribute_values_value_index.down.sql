@@ -0,0 +1 @@
+DROP INDEX IF EXISTS attribute_values_attribute_id_value_idx;
\ No newline at end of file
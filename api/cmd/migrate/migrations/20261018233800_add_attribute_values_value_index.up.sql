@@ -0,0 +1 @@
+CREATE UNIQUE INDEX IF NOT EXISTS attribute_values_attribute_id_value_idx ON attribute_values(attribute_id, lower(value));
\ No newline at end of file
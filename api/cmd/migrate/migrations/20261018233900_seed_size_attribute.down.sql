@@ -0,0 +1 @@
+DELETE FROM attributes WHERE code = 'size';
\ No newline at end of file
@@ -0,0 +1 @@
+INSERT INTO attributes (code, name, position) VALUES ('size', 'Size', 0) ON CONFLICT (code) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM attribute_values av USING attributes a WHERE a.attribute_id = av.attribute_id AND a.code = 'size' AND av.value IN ('S', 'M', 'LG', 'XL');
\ No newline at end of file
@@ -0,0 +1,6 @@
+INSERT INTO attribute_values (attribute_id, value, position)
+SELECT a.attribute_id, v.value, v.position
+FROM attributes a
+CROSS JOIN (VALUES ('S', 0), ('M', 1), ('LG', 2), ('XL', 3)) AS v(value, position)
+WHERE a.code = 'size'
+ON CONFLICT (attribute_id, lower(value)) DO NOTHING;
\ No newline at end of file
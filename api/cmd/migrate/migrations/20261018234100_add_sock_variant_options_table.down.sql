@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sock_variant_options;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS sock_variant_options (
+    sock_variant_id INTEGER NOT NULL,
+    attribute_value_id INTEGER NOT NULL,
+    PRIMARY KEY (sock_variant_id, attribute_value_id),
+    FOREIGN KEY (sock_variant_id) REFERENCES sock_variants(sock_variant_id) ON DELETE CASCADE,
+    FOREIGN KEY (attribute_value_id) REFERENCES attribute_values(attribute_value_id) ON DELETE RESTRICT
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS sock_variant_options_attribute_value_id_idx;
\ No newline at end of file
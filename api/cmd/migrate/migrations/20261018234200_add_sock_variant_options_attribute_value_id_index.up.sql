@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS sock_variant_options_attribute_value_id_idx ON sock_variant_options(attribute_value_id);
\ No newline at end of file
@@ -0,0 +1,6 @@
+UPDATE sock_variants sv
+SET size = av.value
+FROM sock_variant_options o
+JOIN attribute_values av ON av.attribute_value_id = o.attribute_value_id
+JOIN attributes a ON a.attribute_id = av.attribute_id AND a.code = 'size'
+WHERE o.sock_variant_id = sv.sock_variant_id;
\ No newline at end of file
@@ -0,0 +1,6 @@
+INSERT INTO sock_variant_options (sock_variant_id, attribute_value_id)
+SELECT sv.sock_variant_id, av.attribute_value_id
+FROM sock_variants sv
+JOIN attribute_values av ON av.value = sv.size
+JOIN attributes a ON a.attribute_id = av.attribute_id AND a.code = 'size'
+ON CONFLICT DO NOTHING;
\ No newline at end of file
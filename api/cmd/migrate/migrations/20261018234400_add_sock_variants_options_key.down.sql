@@ -0,0 +1 @@
+ALTER TABLE sock_variants DROP COLUMN IF EXISTS options_key;
\ No newline at end of file
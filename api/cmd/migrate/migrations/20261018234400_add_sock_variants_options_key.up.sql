@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD COLUMN IF NOT EXISTS options_key VARCHAR(255) NOT NULL DEFAULT '';
\ No newline at end of file
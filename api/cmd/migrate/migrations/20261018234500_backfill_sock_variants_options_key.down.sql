@@ -0,0 +1 @@
+UPDATE sock_variants SET options_key = '';
\ No newline at end of file
@@ -0,0 +1,6 @@
+UPDATE sock_variants sv
+SET options_key = COALESCE((
+    SELECT string_agg(o.attribute_value_id::text, ',' ORDER BY o.attribute_value_id)
+    FROM sock_variant_options o
+    WHERE o.sock_variant_id = sv.sock_variant_id
+), '');
\ No newline at end of file
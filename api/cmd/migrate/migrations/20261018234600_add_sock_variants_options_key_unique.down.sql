@@ -0,0 +1 @@
+ALTER TABLE sock_variants DROP CONSTRAINT IF EXISTS sock_variants_sock_id_options_key_key;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD CONSTRAINT sock_variants_sock_id_options_key_key UNIQUE (sock_id, options_key);
\ No newline at end of file
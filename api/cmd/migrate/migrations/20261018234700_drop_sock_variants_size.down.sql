@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD COLUMN IF NOT EXISTS size VARCHAR(50);
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    inventory_movement_id SERIAL PRIMARY KEY,
+    sock_variant_id INTEGER NOT NULL,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    quantity_after INTEGER NOT NULL CHECK (quantity_after >= 0),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('sale', 'canceled', 'return', 'restock', 'shrinkage', 'correction')),
+    admin_id INTEGER,
+    order_id INTEGER,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (sock_variant_id) REFERENCES sock_variants(sock_variant_id),
+    FOREIGN KEY (admin_id) REFERENCES admins(admin_id),
+    FOREIGN KEY (order_id) REFERENCES orders(order_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS inventory_movements_sock_variant_id_idx;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS inventory_movements_sock_variant_id_idx ON inventory_movements(sock_variant_id, inventory_movement_id);
\ No newline at end of file
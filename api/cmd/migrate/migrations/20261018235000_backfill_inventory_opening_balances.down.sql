@@ -0,0 +1 @@
+DELETE FROM inventory_movements WHERE reason = 'correction' AND note = 'Opening balance' AND admin_id IS NULL;
\ No newline at end of file
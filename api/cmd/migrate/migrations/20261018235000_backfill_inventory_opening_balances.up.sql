@@ -0,0 +1,4 @@
+INSERT INTO inventory_movements (sock_variant_id, delta, quantity_after, reason, note)
+SELECT sock_variant_id, quantity, quantity, 'correction', 'Opening balance'
+FROM sock_variants
+WHERE quantity > 0;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants DROP COLUMN IF EXISTS reorder_threshold;
\ No newline at end of file
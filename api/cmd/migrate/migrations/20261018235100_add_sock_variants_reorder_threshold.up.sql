@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD COLUMN IF NOT EXISTS reorder_threshold INTEGER NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants DROP COLUMN IF EXISTS low_stock_alerted_at;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sock_variants ADD COLUMN IF NOT EXISTS low_stock_alerted_at TIMESTAMP;
\ No newline at end of file
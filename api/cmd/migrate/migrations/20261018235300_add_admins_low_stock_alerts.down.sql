@@ -0,0 +1 @@
+ALTER TABLE admins DROP COLUMN IF EXISTS low_stock_alerts;
\ No newline at end of file
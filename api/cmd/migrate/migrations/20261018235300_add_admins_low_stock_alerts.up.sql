@@ -0,0 +1 @@
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS low_stock_alerts BOOLEAN NOT NULL DEFAULT false;
\ No newline at end of file
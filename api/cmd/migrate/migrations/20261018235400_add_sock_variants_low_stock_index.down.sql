@@ -0,0 +1 @@
+DROP INDEX IF EXISTS sock_variants_low_stock_idx;
\ No newline at end of file
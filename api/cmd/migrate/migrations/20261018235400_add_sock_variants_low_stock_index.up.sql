@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS sock_variants_low_stock_idx ON sock_variants(sock_variant_id) WHERE quantity < reorder_threshold AND is_deleted = false;
\ No newline at end of file
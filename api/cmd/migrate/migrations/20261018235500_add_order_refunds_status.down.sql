@@ -0,0 +1 @@
+ALTER TABLE order_refunds DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE order_refunds ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'completed' CHECK (status IN ('pending', 'completed', 'failed'));
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_description TEXT NOT NULL DEFAULT '';
\ No newline at end of file
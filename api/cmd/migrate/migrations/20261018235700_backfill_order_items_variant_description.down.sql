@@ -0,0 +1 @@
+UPDATE order_items SET variant_description = '';
\ No newline at end of file
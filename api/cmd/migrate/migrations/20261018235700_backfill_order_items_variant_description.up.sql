@@ -0,0 +1,10 @@
+UPDATE order_items oi
+SET variant_description = d.description
+FROM (
+    SELECT o.sock_variant_id, string_agg(a.name || ': ' || av.value, ', ' ORDER BY a.position ASC, a.attribute_id ASC) AS description
+    FROM sock_variant_options o
+    JOIN attribute_values av ON av.attribute_value_id = o.attribute_value_id
+    JOIN attributes a ON a.attribute_id = av.attribute_id
+    GROUP BY o.sock_variant_id
+) d
+WHERE d.sock_variant_id = oi.sock_variant_id AND oi.variant_description = '';
\ No newline at end of file
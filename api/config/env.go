@@ -6,21 +6,59 @@ import (
 )
 
 const FOUR_HOURS_IN_SECONDS int64 = 3600 * 4
+const THIRTY_MINUTES_IN_SECONDS int64 = 60 * 30
+const FIVE_MINUTES_IN_SECONDS int64 = 60 * 5
+const FIFTEEN_MINUTES_IN_SECONDS int64 = 60 * 15
+const SEVEN_DAYS_IN_SECONDS int64 = 3600 * 24 * 7
 
 type Config struct {
-	WebClientURL           string
-	APIPort                string
-	APIURL                 string
-	DBName                 string
-	DBUser                 string
-	DBPassword             string
-	DBHost                 string
-	DBPort                 string
-	JWTSecret              string
-	JWTExpirationInSeconds int64
-	DisableAuth            bool
-	StripeAPIKey           string
-	SendGridAPIKey         string
+	WebClientURL                         string
+	APIPort                              string
+	APIURL                               string
+	DBName                               string
+	DBUser                               string
+	DBPassword                           string
+	DBHost                               string
+	DBPort                               string
+	JWTSecret                            string
+	JWTExpirationInSeconds               int64
+	AdminAccessTokenExpirationInSeconds  int64
+	AdminRefreshTokenExpirationInSeconds int64
+	DisableAuth                          bool
+	MFAIssuer                            string
+	MFATokenExpirationInSeconds          int64
+	PasswordResetExpirationInSeconds     int64
+	LoginThrottleStore                   string
+	LoginMaxFailuresPerUsername          int64
+	LoginMaxFailuresPerIP                int64
+	LoginLockoutInSeconds                int64
+	PaymentProvider                      string
+	StripeAPIKey                         string
+	StripeWebhookSecret                  string
+	CheckoutExpirationInSeconds          int64
+	OrderReaperIntervalInSeconds         int64
+	EmailTransport                       string
+	SendGridAPIKey                       string
+	SMTPHost                             string
+	SMTPPort                             string
+	SMTPUsername                         string
+	SMTPPassword                         string
+	EmailOutputDir                       string
+	EmailOutboxIntervalInSeconds         int64
+	LowStockCheckIntervalInSeconds       int64
+	TrustedProxyHops                     int64
+	TrackingRateLimitPerMinute           int64
+	BlobStore                            string
+	BlobStoreLocalDir                    string
+	S3Endpoint                           string
+	S3Region                             string
+	S3Bucket                             string
+	S3AccessKeyID                        string
+	S3SecretAccessKey                    string
+	S3ForcePathStyle                     bool
+	ImagesBaseURL                        string
+	MaxImageUploadSizeInBytes            int64
+	ThumbnailSizeInPixels                int64
 }
 
 // Envs is the global configuration for the application.
@@ -28,19 +66,53 @@ var Envs = initConfig()
 
 func initConfig() Config {
 	return Config{
-		WebClientURL:           getEnv("WEB_CLIENT_URL", "http://localhost:5173"),
-		APIPort:                getEnv("API_PORT", "8080"),
-		APIURL:                 getEnv("API_URL", "http://localhost"), // No port
-		DBName:                 getEnv("DB_NAME", "sockify"),
-		DBUser:                 getEnv("DB_USER", "postgres"),
-		DBPassword:             getEnv("DB_PASSWORD", "password"),
-		DBHost:                 getEnv("DB_HOST", "host.docker.internal"), // Analogous to "localhost"
-		DBPort:                 getEnv("DB_PORT", "5432"),
-		JWTSecret:              getEnv("JWT_SECRET", "c3VwZXIgc2VjcmV0IEpXVCB0b2tlbiE="),
-		JWTExpirationInSeconds: getEnvInt("JWT_EXPIRATION_IN_SECONDS", FOUR_HOURS_IN_SECONDS),
-		DisableAuth:            getEnvBool("DISABLE_AUTH", false),
-		StripeAPIKey:           getEnv("STRIPE_API_KEY", "FIXME"),
-		SendGridAPIKey:         getEnv("SENDGRID_API_KEY", "FIXME"),
+		WebClientURL:                         getEnv("WEB_CLIENT_URL", "http://localhost:5173"),
+		APIPort:                              getEnv("API_PORT", "8080"),
+		APIURL:                               getEnv("API_URL", "http://localhost"), // No port
+		DBName:                               getEnv("DB_NAME", "sockify"),
+		DBUser:                               getEnv("DB_USER", "postgres"),
+		DBPassword:                           getEnv("DB_PASSWORD", "password"),
+		DBHost:                               getEnv("DB_HOST", "host.docker.internal"), // Analogous to "localhost"
+		DBPort:                               getEnv("DB_PORT", "5432"),
+		JWTSecret:                            getEnv("JWT_SECRET", "c3VwZXIgc2VjcmV0IEpXVCB0b2tlbiE="),
+		JWTExpirationInSeconds:               getEnvInt("JWT_EXPIRATION_IN_SECONDS", FOUR_HOURS_IN_SECONDS), // Customer tokens
+		AdminAccessTokenExpirationInSeconds:  getEnvInt("ADMIN_ACCESS_TOKEN_EXPIRATION_IN_SECONDS", FIFTEEN_MINUTES_IN_SECONDS),
+		AdminRefreshTokenExpirationInSeconds: getEnvInt("ADMIN_REFRESH_TOKEN_EXPIRATION_IN_SECONDS", SEVEN_DAYS_IN_SECONDS),
+		DisableAuth:                          getEnvBool("DISABLE_AUTH", false),
+		MFAIssuer:                            getEnv("MFA_ISSUER", "Sockify"), // Name shown in authenticator apps
+		MFATokenExpirationInSeconds:          getEnvInt("MFA_TOKEN_EXPIRATION_IN_SECONDS", FIVE_MINUTES_IN_SECONDS),
+		PasswordResetExpirationInSeconds:     getEnvInt("PASSWORD_RESET_EXPIRATION_IN_SECONDS", THIRTY_MINUTES_IN_SECONDS),
+		LoginThrottleStore:                   getEnv("LOGIN_THROTTLE_STORE", "postgres"), // "postgres" or "memory" (single replica only)
+		LoginMaxFailuresPerUsername:          getEnvInt("LOGIN_MAX_FAILURES_PER_USERNAME", 5),
+		LoginMaxFailuresPerIP:                getEnvInt("LOGIN_MAX_FAILURES_PER_IP", 20),
+		LoginLockoutInSeconds:                getEnvInt("LOGIN_LOCKOUT_IN_SECONDS", FIFTEEN_MINUTES_IN_SECONDS),
+		PaymentProvider:                      getEnv("PAYMENT_PROVIDER", "stripe"), // "stripe" or "fake"
+		StripeAPIKey:                         getEnv("STRIPE_API_KEY", "FIXME"),
+		StripeWebhookSecret:                  getEnv("STRIPE_WEBHOOK_SECRET", ""),                                    // Webhooks are rejected until it is set
+		CheckoutExpirationInSeconds:          getEnvInt("CHECKOUT_EXPIRATION_IN_SECONDS", THIRTY_MINUTES_IN_SECONDS), // Stripe requires at least 30 minutes
+		OrderReaperIntervalInSeconds:         getEnvInt("ORDER_REAPER_INTERVAL_IN_SECONDS", FIVE_MINUTES_IN_SECONDS),
+		EmailTransport:                       getEnv("EMAIL_TRANSPORT", "sendgrid"), // "sendgrid", "smtp", "file" or "memory"
+		SendGridAPIKey:                       getEnv("SENDGRID_API_KEY", "FIXME"),
+		SMTPHost:                             getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                             getEnv("SMTP_PORT", "1025"), // Mailpit/MailHog default
+		SMTPUsername:                         getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:                         getEnv("SMTP_PASSWORD", ""),
+		EmailOutputDir:                       getEnv("EMAIL_OUTPUT_DIR", "./tmp/emails"),
+		EmailOutboxIntervalInSeconds:         getEnvInt("EMAIL_OUTBOX_INTERVAL_IN_SECONDS", 30),
+		LowStockCheckIntervalInSeconds:       getEnvInt("LOW_STOCK_CHECK_INTERVAL_IN_SECONDS", FIFTEEN_MINUTES_IN_SECONDS),
+		TrustedProxyHops:                     getEnvInt("TRUSTED_PROXY_HOPS", 0), // Number of reverse proxies in front of the API that append to X-Forwarded-For
+		TrackingRateLimitPerMinute:           getEnvInt("TRACKING_RATE_LIMIT_PER_MINUTE", 10),
+		BlobStore:                            getEnv("BLOB_STORE", "local"), // "local" or "s3" (any S3-compatible service, e.g. MinIO)
+		BlobStoreLocalDir:                    getEnv("BLOB_STORE_LOCAL_DIR", "./tmp/uploads"),
+		S3Endpoint:                           getEnv("S3_ENDPOINT", "https://s3.us-east-1.amazonaws.com"),
+		S3Region:                             getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:                             getEnv("S3_BUCKET", "sockify"),
+		S3AccessKeyID:                        getEnv("S3_ACCESS_KEY_ID", "FIXME"),
+		S3SecretAccessKey:                    getEnv("S3_SECRET_ACCESS_KEY", "FIXME"),
+		S3ForcePathStyle:                     getEnvBool("S3_FORCE_PATH_STYLE", false),                         // Required by MinIO and most S3-compatible services
+		ImagesBaseURL:                        getEnv("IMAGES_BASE_URL", "http://localhost:8080/api/v1/images"), // Public URL images are served from
+		MaxImageUploadSizeInBytes:            getEnvInt("MAX_IMAGE_UPLOAD_SIZE_IN_BYTES", 5<<20),
+		ThumbnailSizeInPixels:                getEnvInt("THUMBNAIL_SIZE_IN_PIXELS", 400),
 	}
 }
 
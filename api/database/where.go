@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhereBuilder incrementally builds a SQL `WHERE` clause along with its positional (`$n`) arguments.
+type WhereBuilder struct {
+	conditions []string
+	args       []any
+}
+
+// Arg registers a query argument and returns its placeholder.
+func (b *WhereBuilder) Arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// Where adds a condition. Conditions are joined with `AND`.
+func (b *WhereBuilder) Where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// Clause returns the `WHERE` clause, or an empty string when there are no conditions.
+func (b *WhereBuilder) Clause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// Args returns the arguments in placeholder order.
+func (b *WhereBuilder) Args() []any {
+	return b.args
+}
+
+// EscapeLike escapes the `LIKE` wildcards in a user provided value, so it is matched literally.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -62,7 +62,7 @@ const docTemplate = `{
         },
         "/admins/login": {
             "post": {
-                "description": "Logs in an admin using username and password credentials.",
+                "description": "Logs in an admin using username and password credentials. Returns a short-lived access token and a refresh token. Repeated failures are throttled with a 429 and a \"Retry-After\" header. When the admin has MFA enabled, no token is returned; instead \"mfaRequired\" is set along with an \"mfaToken\" to complete the login at ` + "`" + `/admins/login/mfa` + "`" + `.",
                 "consumes": [
                     "application/json"
                 ],
@@ -94,14 +94,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admins/register": {
+        "/admins/login/mfa": {
             "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Creates a new set of admin credentials.",
+                "description": "Exchanges the \"mfaToken\" from ` + "`" + `/admins/login` + "`" + ` and a TOTP code (or a recovery code) for an access token and a refresh token.",
                 "consumes": [
                     "application/json"
                 ],
@@ -111,94 +106,113 @@ const docTemplate = `{
                 "tags": [
                     "Admins"
                 ],
-                "summary": "Registers new admin credentials.",
+                "summary": "Completes an admin login with MFA",
                 "parameters": [
                     {
-                        "description": "Register credentials",
+                        "description": "MFA token and code",
                         "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.RegisterAdminRequest"
+                            "$ref": "#/definitions/types.LoginAdminMFARequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Message"
+                            "$ref": "#/definitions/types.LoginAdminResponse"
                         }
                     }
                 }
             }
         },
-        "/admins/{admin_id}": {
-            "get": {
+        "/admins/logout": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves all of an admins relevant information.",
+                "description": "Revokes the access token used to make the request. If a refresh token is given, it is revoked as well.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Admins"
                 ],
-                "summary": "Get details of a specific admin",
+                "summary": "Logs out an admin",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Admin ID",
-                        "name": "admin_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh token to revoke",
+                        "name": "Body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.LogoutAdminRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Admin"
+                            "$ref": "#/definitions/types.Message"
                         }
                     }
                 }
             }
         },
-        "/cart/checkout/stripe-confirmation/{session_id}": {
-            "get": {
-                "description": "Confirms the Stripe checkout status from the session ID. Retrieves the \"orderId\" from the session metadata and updates the order status.",
+        "/admins/me/mfa": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Disables MFA for the logged in admin. Requires a valid TOTP code or recovery code.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admins"
                 ],
-                "summary": "Confirms a Stripe checkout session and retrieves the order",
+                "summary": "Disables MFA",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Stripe checkout session ID",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
+                        "description": "TOTP code or recovery code",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.MFACodeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.OrderConfirmation"
+                            "$ref": "#/definitions/types.Message"
                         }
                     }
                 }
             }
         },
-        "/cart/checkout/stripe-session": {
+        "/admins/me/mfa/confirm": {
             "post": {
-                "description": "Creates a new Stripe checkout session after creating a \"pending\" order in the database. The \"orderId\" is attached within the metadata.",
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Enables MFA once a valid TOTP code for the enrolled secret is provided. Returns one-time recovery codes, which are only shown once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -206,17 +220,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Cart"
+                    "Admins"
                 ],
-                "summary": "Creates a Stripe checkout session",
+                "summary": "Confirms MFA enrollment",
                 "parameters": [
                     {
-                        "description": "Order to checkout",
-                        "name": "payload",
+                        "description": "TOTP code",
+                        "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CheckoutOrderRequest"
+                            "$ref": "#/definitions/types.MFACodeRequest"
                         }
                     }
                 ],
@@ -224,43 +238,45 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.StripeCheckoutResponse"
+                            "$ref": "#/definitions/types.MFARecoveryCodesResponse"
                         }
                     }
                 }
             }
         },
-        "/newsletter/emails": {
-            "get": {
+        "/admins/me/mfa/enroll": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves a list of all newsletter participants.",
+                "description": "Generates a new TOTP secret for the logged in admin. Add it to an authenticator app (e.g. by rendering the provisioning URI as a QR code), then confirm it at ` + "`" + `/admins/me/mfa/confirm` + "`" + `.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Admins"
                 ],
-                "summary": "Get all newsletter entries",
+                "summary": "Starts MFA enrollment",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.NewsletterEntry"
-                            }
+                            "$ref": "#/definitions/types.MFAEnrollmentResponse"
                         }
                     }
                 }
             }
         },
-        "/newsletter/subscribe": {
-            "post": {
-                "description": "Adds (opts-in) a new email entry to the newsletter.",
+        "/admins/me/password": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Changes the password of the logged in admin after checking the current one. All refresh tokens of the admin are revoked, so other sessions have to log in again.",
                 "consumes": [
                     "application/json"
                 ],
@@ -268,17 +284,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Admins"
                 ],
-                "summary": "Subscribes an email to the newsletter",
+                "summary": "Changes the password of the logged in admin",
                 "parameters": [
                     {
-                        "description": "entry",
-                        "name": "payload",
+                        "description": "Current and new password",
+                        "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.NewsletterSubscribeRequest"
+                            "$ref": "#/definitions/types.ChangeAdminPasswordRequest"
                         }
                     }
                 ],
@@ -292,9 +308,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/newsletter/unsubscribe": {
+        "/admins/password/forgot": {
             "post": {
-                "description": "Removes (opts-out) a new email entry from the newsletter.",
+                "description": "Emails a single-use password reset link to the admin with the given email. The response is the same whether or not the email belongs to an admin.",
                 "consumes": [
                     "application/json"
                 ],
@@ -302,23 +318,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Newsletter"
+                    "Admins"
                 ],
-                "summary": "Unsubscribes an email from the newsletter",
+                "summary": "Requests an admin password reset",
                 "parameters": [
                     {
-                        "description": "entry",
-                        "name": "payload",
+                        "description": "Admin email",
+                        "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.NewsletterUnsubscribeRequest"
+                            "$ref": "#/definitions/types.ForgotAdminPasswordRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/types.Message"
                         }
@@ -326,107 +342,133 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders": {
-            "get": {
-                "security": [
+        "/admins/password/reset": {
+            "post": {
+                "description": "Sets a new password using the token from a password reset email. All refresh tokens of the admin are revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admins"
+                ],
+                "summary": "Resets an admin password",
+                "parameters": [
                     {
-                        "Bearer": []
+                        "description": "Reset token and new password",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ResetAdminPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
                     }
+                }
+            }
+        },
+        "/admins/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access token and a new refresh token. Refresh tokens can only be used once; reusing one revokes every refresh token of the admin.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieves all orders from the database with optional filters. Results are returned oldest to newest by created date.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admins"
                 ],
-                "summary": "Retrieve all orders",
+                "summary": "Refreshes an admin access token",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Limit the number of results",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Status of the order",
-                        "name": "status",
-                        "in": "query"
+                        "description": "Refresh token",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RefreshAdminTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.OrdersPaginatedResponse"
+                            "$ref": "#/definitions/types.LoginAdminResponse"
                         }
                     }
                 }
             }
         },
-        "/orders/invoice/{invoice_number}": {
-            "get": {
+        "/admins/register": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves order details and item list by invoice number.",
+                "description": "Creates a new set of admin credentials. The role defaults to \"read_only\". Only owners can register admins.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admins"
                 ],
-                "summary": "Retrieve order details by invoice number",
+                "summary": "Registers new admin credentials.",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Invoice Number",
-                        "name": "invoice_number",
-                        "in": "path",
-                        "required": true
+                        "description": "Register credentials",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RegisterAdminRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/types.Order"
+                            "$ref": "#/definitions/types.Message"
                         }
                     }
                 }
             }
         },
-        "/orders/{order_id}": {
+        "/admins/{admin_id}": {
             "get": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Retrieves all the details for a particular order.",
+                "description": "Retrieves all of an admins relevant information.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admins"
                 ],
-                "summary": "Retrieve details for an order",
+                "summary": "Get details of a specific admin",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Admin ID",
+                        "name": "admin_id",
                         "in": "path",
                         "required": true
                     }
@@ -435,20 +477,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Order"
+                            "$ref": "#/definitions/types.Admin"
                         }
                     }
                 }
-            }
-        },
-        "/orders/{order_id}/address": {
+            },
             "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the address for a specific order by ID",
+                "description": "Updates the profile of an admin. Admins can update their own profile, owners can update anyone's. Only owners can (de)activate admins; deactivated admins are signed out and can not log in. Active admins with ` + "`" + `lowStockAlerts` + "`" + ` receive a digest email when variants drop below their reorder threshold.",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,24 +496,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admins"
                 ],
-                "summary": "Update the address of an existing order",
+                "summary": "Updates an admin",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Admin ID",
+                        "name": "admin_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New Address Data",
-                        "name": "address",
+                        "description": "Fields to update",
+                        "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateAddressRequest"
+                            "$ref": "#/definitions/types.UpdateAdminRequest"
                         }
                     }
                 ],
@@ -481,20 +521,20 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Message"
+                            "$ref": "#/definitions/types.Admin"
                         }
                     }
                 }
             }
         },
-        "/orders/{order_id}/contact": {
+        "/admins/{admin_id}/role": {
             "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates the contact information (name, email, phone) for a specific order by ID",
+                "description": "Changes the role of an admin. Only owners can change roles, and the last owner can not be demoted. The admin has to log in again for the new role to apply.",
                 "consumes": [
                     "application/json"
                 ],
@@ -502,24 +542,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Admins"
                 ],
-                "summary": "Update the contact information of an existing order",
+                "summary": "Updates the role of an admin",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Admin ID",
+                        "name": "admin_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New Contact Information",
-                        "name": "contact",
+                        "description": "New role",
+                        "name": "Body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateContactRequest"
+                            "$ref": "#/definitions/types.UpdateAdminRoleRequest"
                         }
                     }
                 ],
@@ -533,72 +573,80 @@ const docTemplate = `{
                 }
             }
         },
-        "/orders/{order_id}/status": {
-            "patch": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Updates the status for a specific order by ID",
-                "consumes": [
+        "/attributes": {
+            "get": {
+                "description": "Returns the variant attributes, such as size or color, with their allowed values. Attributes and values are sorted by position.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get all attributes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Attribute"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a variant attribute without values. The code is the key used in variant options and filters, e.g. \"color\".",
+                "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Catalog"
                 ],
-                "summary": "Update the status of an existing order",
+                "summary": "Create an attribute",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New order status",
-                        "name": "statusUpdate",
+                        "description": "Attribute",
+                        "name": "attribute",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateOrderStatusRequest"
+                            "$ref": "#/definitions/types.AttributeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/types.Message"
+                            "$ref": "#/definitions/types.Attribute"
                         }
                     }
                 }
             }
         },
-        "/orders/{order_id}/updates": {
+        "/attributes/{attribute_id}": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "Retrieves all order updates for a particular order. Results are sorted descending by createdAt.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Catalog"
                 ],
-                "summary": "Retrieve updates for an order",
+                "summary": "Get an attribute",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
                         "in": "path",
                         "required": true
                     }
@@ -607,47 +655,32 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.OrderUpdate"
-                            }
+                            "$ref": "#/definitions/types.Attribute"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Creates a new update for an existing order.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Deletes an attribute and its values. Returns 409 if a sock variant, including an archived one, uses one of its values.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Catalog"
                 ],
-                "summary": "Creates an update for an order",
+                "summary": "Delete an attribute",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order ID",
-                        "name": "order_id",
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "New order update",
-                        "name": "address",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.CreateOrderUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -658,50 +691,60 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/socks": {
-            "get": {
-                "description": "Returns a list of paginated socks sorted in descending order by created date",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Renames or moves an attribute. Changing the code changes the key of the option in every variant.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Catalog"
                 ],
-                "summary": "Get all socks",
+                "summary": "Update an attribute",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 50,
-                        "description": "Limit the number of results",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset for pagination",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Attribute",
+                        "name": "attribute",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AttributeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.SocksPaginatedResponse"
+                            "$ref": "#/definitions/types.Attribute"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/attributes/{attribute_id}/values": {
             "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Adds a new sock to the store with its variants",
+                "description": "Allows a new value for the attribute. Values are unique per attribute, ignoring case.",
                 "consumes": [
                     "application/json"
                 ],
@@ -709,17 +752,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Catalog"
                 ],
-                "summary": "Create a new sock",
+                "summary": "Add an attribute value",
                 "parameters": [
                     {
-                        "description": "Sock Data",
-                        "name": "sock",
+                        "type": "integer",
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Value",
+                        "name": "value",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateSockRequest"
+                            "$ref": "#/definitions/types.AttributeValueRequest"
                         }
                     }
                 ],
@@ -727,27 +777,39 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateSockResponse"
+                            "$ref": "#/definitions/types.Attribute"
                         }
                     }
                 }
             }
         },
-        "/socks/{sock_id}": {
-            "get": {
-                "description": "Retrieve the details of a sock by its ID",
+        "/attributes/{attribute_id}/values/{value_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a value. Returns 409 if a sock variant, including an archived one, uses it.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Catalog"
                 ],
-                "summary": "Get details of a specific sock",
+                "summary": "Delete an attribute value",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Sock ID",
-                        "name": "sock_id",
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Value ID",
+                        "name": "value_id",
                         "in": "path",
                         "required": true
                     }
@@ -756,47 +818,100 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Sock"
+                            "$ref": "#/definitions/types.Message"
                         }
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Deletes a sock from the store by its ID",
+                "description": "Renames or moves a value. Sock variants using the value show the new name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Inventory"
+                    "Catalog"
                 ],
-                "summary": "Delete a sock",
+                "summary": "Update an attribute value",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Sock ID",
-                        "name": "sock_id",
+                        "description": "Attribute ID",
+                        "name": "attribute_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Value ID",
+                        "name": "value_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Value",
+                        "name": "value",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AttributeValueRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Message"
+                            "$ref": "#/definitions/types.Attribute"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/cart/checkout/stripe-confirmation/{session_id}": {
+            "get": {
+                "description": "Confirms the Stripe checkout status from the session ID. Retrieves the \"orderId\" from the session metadata and updates the order status.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Confirms a Stripe checkout session and retrieves the order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stripe checkout session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.OrderConfirmation"
+                        }
+                    }
+                }
+            }
+        },
+        "/cart/checkout/stripe-session": {
+            "post": {
                 "security": [
                     {
                         "Bearer": []
                     }
                 ],
-                "description": "Updates all of the details for a sock given a sock ID",
+                "description": "Creates a new Stripe checkout session after creating a \"pending\" order in the database. The \"orderId\" is attached within the metadata.\nGuests can checkout anonymously. When a customer token is provided, the order is linked to the customer account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -804,24 +919,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Inventory"
+                    "Cart"
                 ],
-                "summary": "Updates the details of a sock",
+                "summary": "Creates a Stripe checkout session",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Sock ID",
-                        "name": "sock_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated sock details",
-                        "name": "details",
+                        "description": "Order to checkout",
+                        "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateSockRequest"
+                            "$ref": "#/definitions/types.CheckoutOrderRequest"
                         }
                     }
                 ],
@@ -829,96 +937,2394 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Message"
+                            "$ref": "#/definitions/types.StripeCheckoutResponse"
                         }
                     }
                 }
             }
         },
-        "/socks/{sock_id}/similar-socks": {
-            "get": {
-                "description": "For now, it will retrieve the top 6 products that are not matching the sock_id passed in.",
+        "/cart/checkout/stripe-webhook": {
+            "post": {
+                "description": "Handles the payment lifecycle events sent by Stripe (\"checkout.session.completed\", \"checkout.session.expired\", \"charge.refunded\"). Requests must be signed with the ` + "`" + `Stripe-Signature` + "`" + ` header. Processing is idempotent, so Stripe can safely retry deliveries. Returns a 503 when the webhook secret is not configured.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Inventory"
+                    "Cart"
                 ],
-                "summary": "Retrieves the related products for a particular sock",
+                "summary": "Receives Stripe webhook events",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Sock ID",
-                        "name": "sock_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "Stripe webhook signature",
+                        "name": "Stripe-Signature",
+                        "in": "header",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Returns every category sorted by name. Categories are hierarchical: subcategories reference their parent through \"parentId\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get all categories",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/types.SimilarSock"
+                                "$ref": "#/definitions/types.Category"
                             }
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "types.Address": {
-            "type": "object",
-            "properties": {
-                "aptUnit": {
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a category, optionally under a parent category. The slug is generated from the name when omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Create a category",
+                "parameters": [
+                    {
+                        "description": "Category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.Category"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Category"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a category without subcategories. Its socks are left without a category.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Renames or moves a category. A category cannot be moved under itself or one of its subcategories.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Category"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections": {
+            "get": {
+                "description": "Returns every collection sorted by name. Use ` + "`" + `GET /socks?collection_id=` + "`" + ` to list the socks of a collection.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get all collections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Collection"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates an empty collection. The slug is generated from the name when omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Create a collection",
+                "parameters": [
+                    {
+                        "description": "Collection",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.Collection"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{collection_id}": {
+            "get": {
+                "description": "Returns a collection with the IDs of its socks in display order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Collection"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a collection. Its socks are not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Update a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection",
+                        "name": "collection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Collection"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{collection_id}/socks": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Replaces the socks of a collection. Socks are displayed in the given order. Returns 400 if a sock does not exist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Set the socks of a collection",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Collection ID",
+                        "name": "collection_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sock IDs",
+                        "name": "socks",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.SetCollectionSocksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Collection"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/login": {
+            "post": {
+                "description": "Logs in a customer using email and password credentials. The returned token can only be used on customer endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Logs in a customer",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.LoginCustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.LoginCustomerResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/me": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the account details of the logged in customer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Get the logged in customer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Customer"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/me/orders": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves the orders placed by the logged in customer, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Get the order history of the logged in customer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.OrdersPaginatedResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/register": {
+            "post": {
+                "description": "Creates a new customer account and logs the customer in. The returned token can only be used on customer endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Registers a customer account",
+                "parameters": [
+                    {
+                        "description": "Customer details",
+                        "name": "Body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RegisterCustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.LoginCustomerResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{key}": {
+            "get": {
+                "description": "Serves an uploaded image (e.g. a sock image or its thumbnail) by key. Keys are never reused, so images can be cached forever.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "Images"
+                ],
+                "summary": "Serves an uploaded image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Image key, e.g. socks/1/image.jpg",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/inventory/low-stock": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the active sock variants whose quantity is below their reorder threshold, the emptiest first. ` + "`" + `alertedAt` + "`" + ` is set once subscribed admins were emailed about the variant, and reset when it is restocked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get the low-stock report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.LowStockVariant"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/emails": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves a list of all newsletter participants.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Get all newsletter entries",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.NewsletterEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/subscribe": {
+            "post": {
+                "description": "Adds (opts-in) a new email entry to the newsletter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Subscribes an email to the newsletter",
+                "parameters": [
+                    {
+                        "description": "entry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.NewsletterSubscribeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/newsletter/unsubscribe": {
+            "post": {
+                "description": "Removes (opts-out) a new email entry from the newsletter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Newsletter"
+                ],
+                "summary": "Unsubscribes an email from the newsletter",
+                "parameters": [
+                    {
+                        "description": "entry",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.NewsletterUnsubscribeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all orders from the database with optional filters. Results are returned oldest to newest by created date unless ` + "`" + `sort=desc` + "`" + `.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Retrieve all orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Statuses of the order (comma separated or repeated)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partial customer name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partial customer email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partial customer phone",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice number prefix",
+                        "name": "invoice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date (YYYY-MM-DD or RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date (YYYY-MM-DD, inclusive) or before this time (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum order total",
+                        "name": "min_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum order total",
+                        "name": "max_total",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Sort direction by created date",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.OrdersPaginatedResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/invoice/{invoice_number}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves order details and item list by invoice number.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Retrieve order details by invoice number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invoice Number",
+                        "name": "invoice_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/tracking": {
+            "post": {
+                "description": "Retrieves a customer-safe view of an order using the invoice number and the contact email on the order. Internal order updates are omitted. Requests are rate limited per IP.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Track an order",
+                "parameters": [
+                    {
+                        "description": "Invoice number and contact email",
+                        "name": "tracking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.TrackOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.OrderTracking"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all the details for a particular order. The \"ETag\" header identifies the current version of the order, to be sent back in \"If-Match\" when editing it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Retrieve details for an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Order"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/address": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the address for a specific order by ID. The \"If-Match\" header must be set to the \"ETag\" returned when the order was fetched; the update is rejected with a 412 if the order changed since, and with a 428 if the header is missing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Update the address of an existing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order being edited",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New Address Data",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/contact": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the contact information (name, email, phone) for a specific order by ID. The \"If-Match\" header must be set to the \"ETag\" returned when the order was fetched; the update is rejected with a 412 if the order changed since, and with a 428 if the header is missing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Update the contact information of an existing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the order being edited",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "New Contact Information",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateContactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/refunds": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all refunds issued for a particular order. Results are sorted descending by createdAt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Retrieve refunds for an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.OrderRefund"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Refunds an entire order (no items provided) or specific items through the payment provider. Refunded items can optionally be returned to stock. Fully refunded orders are moved to \"canceled\" (if not shipped yet) or \"returned\" (if delivered). The refund is recorded as pending before it is issued, so its items can not be refunded twice (409); it is marked as failed when the payment provider declines it (502).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Refunds an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refund details",
+                        "name": "refund",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateRefundRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.OrderRefund"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates the status for a specific order by ID. The customer is emailed when the order is shipped, delivered, canceled or returned. Canceled orders return their items to stock. Rejected with a 409 when the status was changed by someone else in the meantime.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Update the status of an existing order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New order status",
+                        "name": "statusUpdate",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/updates": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Retrieves all order updates for a particular order. Results are sorted descending by createdAt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Retrieve updates for an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.OrderUpdate"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Creates a new update for an existing order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Creates an update for an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New order update",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateOrderUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/socks": {
+            "get": {
+                "description": "Returns a list of paginated socks matching the filters. Sorted in descending order by created date by default, or by relevance when searching.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get all socks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit the number of results",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search the sock name and description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only socks with a variant having every one of these options, formatted as code:value, e.g. color:Red",
+                        "name": "option",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shorthand for option=size:\u003cvalue\u003e",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only socks with a variant priced at or above this amount",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only socks with a variant priced at or below this amount",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only socks with a variant in stock",
+                        "name": "in_stock",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only socks of this category or one of its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only socks with every one of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only socks of this collection, in the collection order unless another sort is requested",
+                        "name": "collection_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "newest",
+                            "price_asc",
+                            "price_desc",
+                            "name_asc",
+                            "name_desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.SocksPaginatedResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Adds a new sock to the store with its variants. Variant options must be allowed values of existing attributes (see ` + "`" + `GET /attributes` + "`" + `), and each combination of options can only be used once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Create a new sock",
+                "parameters": [
+                    {
+                        "description": "Sock Data",
+                        "name": "sock",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateSockResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/socks/{sock_id}": {
+            "get": {
+                "description": "Retrieve the details of a sock by its ID. The \"ETag\" header identifies the current version of the sock and its variants, to be sent back in \"If-Match\" when updating it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get details of a specific sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Sock"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a sock from the store by its ID",
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Delete a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Updates all of the details for a sock given a sock ID. Variants are matched by options and replace the current ones: variants left out are removed, or archived when they were already ordered. The \"If-Match\" header must be set to the \"ETag\" returned when the sock was fetched; the update is rejected with a 412 if the sock or its stock changed since, and with a 428 if the header is missing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Updates the details of a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the sock being edited",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated sock details",
+                        "name": "details",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateSockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/socks/{sock_id}/images": {
+            "get": {
+                "description": "Retrieves the gallery images of a sock in display order. The first image is the sock preview.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get the images of a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SockImage"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Uploads a JPEG or PNG image and adds it at the end of the gallery of a sock, along with a resized thumbnail. The first image of the gallery is used as the sock preview. Images larger than the configured maximum size are rejected with a 413 and other file types with a 415.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Upload an image for a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG or PNG image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.SockImage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Sets the display order of the gallery of a sock. Every image of the sock must be listed exactly once. The first image becomes the sock preview.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Reorder the images of a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in display order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ReorderSockImagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SockImage"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/socks/{sock_id}/images/{image_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Removes an image and its thumbnail from the gallery of a sock. When it was the preview, the next image takes its place.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Delete an image of a sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            }
+        },
+        "/socks/{sock_id}/similar-socks": {
+            "get": {
+                "description": "Retrieves up to 6 in-stock socks ranked by the number of tags they share with the sock, then by category: the same category first, then a parent, child or sibling category. Newer socks break ties.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Retrieves the related products for a particular sock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.SimilarSock"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/socks/{sock_id}/variants/{variant_id}/movements": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Returns the stock ledger of a sock variant, newest first. Every change of quantity is recorded along with its reason (` + "`" + `sale` + "`" + `, ` + "`" + `canceled` + "`" + `, ` + "`" + `return` + "`" + `, ` + "`" + `restock` + "`" + `, ` + "`" + `shrinkage` + "`" + ` or ` + "`" + `correction` + "`" + `), the admin who made it and the related order, if any.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Get the stock movements of a sock variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sock variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of movements to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.InventoryMovementsPaginatedResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Adds (positive delta) or removes (negative delta) stock from a sock variant and records the movement in its ledger. Manual adjustments must be a ` + "`" + `restock` + "`" + `, ` + "`" + `shrinkage` + "`" + ` or ` + "`" + `correction` + "`" + `. Adjustments that would make the quantity negative are rejected with a 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Inventory"
+                ],
+                "summary": "Adjust the stock of a sock variant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Sock ID",
+                        "name": "sock_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Sock variant ID",
+                        "name": "variant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock adjustment",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AdjustStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.InventoryMovement"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Returns every tag sorted by name, with the number of socks using it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Get all tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Tag"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Deletes a tag and removes it from every sock.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Delete a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Message"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Renames a tag on every sock using it. Tag names are stored lowercase. Returns 409 if another tag already has the name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Catalog"
+                ],
+                "summary": "Rename a tag",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tag ID",
+                        "name": "tag_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RenameTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Tag"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "types.Address": {
+            "type": "object",
+            "properties": {
+                "aptUnit": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "zipcode": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AdjustStockRequest": {
+            "type": "object",
+            "required": [
+                "delta",
+                "reason"
+            ],
+            "properties": {
+                "delta": {
+                    "description": "Positive to add stock, negative to remove it",
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": -100000
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "shrinkage",
+                        "correction"
+                    ]
+                }
+            }
+        },
+        "types.Admin": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isActive": {
+                    "type": "boolean"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "lowStockAlerts": {
+                    "description": "Subscribed to the low-stock digest emails",
+                    "type": "boolean"
+                },
+                "mfaEnabled": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "$ref": "#/definitions/types.AdminRole"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AdminRole": {
+            "type": "string",
+            "enum": [
+                "owner",
+                "inventory_manager",
+                "fulfillment",
+                "read_only"
+            ],
+            "x-enum-varnames": [
+                "RoleOwner",
+                "RoleInventoryManager",
+                "RoleFulfillment",
+                "RoleReadOnly"
+            ]
+        },
+        "types.AdminsPaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Admin"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.Attribute": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Key used in variant options, e.g. \"size\"",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Attributes and values are listed by ascending position",
+                    "type": "integer"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.AttributeValue"
+                    }
+                }
+            }
+        },
+        "types.AttributeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "description": "Lowercase letters, digits and underscores, e.g. \"shoe_size\"",
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                },
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.AttributeValue": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.AttributeValueRequest": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "value": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "types.Category": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "NULL for top-level categories",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "parentId": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "description": "Optional, generated from the name when empty",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "types.ChangeAdminPasswordRequest": {
+            "type": "object",
+            "required": [
+                "currentPassword",
+                "newPassword"
+            ],
+            "properties": {
+                "currentPassword": {
+                    "type": "string"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 8
+                }
+            }
+        },
+        "types.CheckoutItem": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sockVariantId"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "sockVariantId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CheckoutOrderRequest": {
+            "type": "object",
+            "required": [
+                "contact",
+                "items"
+            ],
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "contact": {
+                    "$ref": "#/definitions/types.Contact"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.CheckoutItem"
+                    }
+                }
+            }
+        },
+        "types.Collection": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "sockIds": {
+                    "description": "Socks of the collection in display order, only set for a single collection",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.CollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "slug": {
+                    "description": "Optional, generated from the name when empty",
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "types.Contact": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string"
+                },
+                "lastname": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateOrderUpdateRequest": {
+            "type": "object",
+            "required": [
+                "message"
+            ],
+            "properties": {
+                "isInternal": {
+                    "description": "Internal updates are not shown to customers. Defaults to true.",
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateRefundRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "items": {
+                    "description": "Items to refund. Leave empty to refund everything that has not been refunded yet.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.RefundItemRequest"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "restock": {
+                    "description": "Whether the refunded items should be returned to stock",
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.CreateSockRequest": {
+            "type": "object",
+            "required": [
+                "sock",
+                "variants"
+            ],
+            "properties": {
+                "sock": {
+                    "$ref": "#/definitions/types.SockDTO"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.SockVariantDTO"
+                    }
+                }
+            }
+        },
+        "types.CreateSockResponse": {
+            "type": "object",
+            "properties": {
+                "sockId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.Customer": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
                     "type": "string"
                 },
-                "city": {
+                "email": {
                     "type": "string"
                 },
-                "state": {
+                "firstname": {
                     "type": "string"
                 },
-                "street": {
+                "id": {
+                    "type": "integer"
+                },
+                "lastname": {
                     "type": "string"
                 },
-                "zipcode": {
+                "phone": {
                     "type": "string"
                 }
             }
         },
-        "types.Admin": {
+        "types.ForgotAdminPasswordRequest": {
             "type": "object",
+            "required": [
+                "email"
+            ],
             "properties": {
-                "createdAt": {
+                "email": {
                     "type": "string"
+                }
+            }
+        },
+        "types.InventoryMovement": {
+            "type": "object",
+            "properties": {
+                "adminId": {
+                    "description": "NULL for movements made by the system, e.g. a checkout",
+                    "type": "integer"
                 },
-                "email": {
+                "adminUsername": {
                     "type": "string"
                 },
-                "firstname": {
+                "createdAt": {
                     "type": "string"
                 },
+                "delta": {
+                    "description": "Positive when stock is added, negative when it is removed",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "lastname": {
+                "note": {
                     "type": "string"
                 },
-                "username": {
+                "orderId": {
+                    "type": "integer"
+                },
+                "quantityAfter": {
+                    "type": "integer"
+                },
+                "reason": {
                     "type": "string"
+                },
+                "sockVariantId": {
+                    "type": "integer"
                 }
             }
         },
-        "types.AdminsPaginatedResponse": {
+        "types.InventoryMovementsPaginatedResponse": {
             "type": "object",
             "properties": {
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.Admin"
+                        "$ref": "#/definitions/types.InventoryMovement"
                     }
                 },
                 "limit": {
@@ -932,117 +3338,150 @@ const docTemplate = `{
                 }
             }
         },
-        "types.CheckoutItem": {
+        "types.LoginAdminMFARequest": {
             "type": "object",
             "required": [
-                "quantity",
-                "sockVariantId"
+                "code",
+                "mfaToken"
             ],
             "properties": {
-                "quantity": {
-                    "type": "integer",
-                    "minimum": 1
+                "code": {
+                    "description": "TOTP code from the authenticator app, or a recovery code",
+                    "type": "string"
                 },
-                "sockVariantId": {
-                    "type": "integer"
+                "mfaToken": {
+                    "type": "string"
                 }
             }
         },
-        "types.CheckoutOrderRequest": {
+        "types.LoginAdminRequest": {
             "type": "object",
             "required": [
-                "contact",
-                "items"
+                "password",
+                "username"
             ],
             "properties": {
-                "address": {
-                    "$ref": "#/definitions/types.Address"
-                },
-                "contact": {
-                    "$ref": "#/definitions/types.Contact"
+                "password": {
+                    "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.CheckoutItem"
-                    }
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "types.Contact": {
+        "types.LoginAdminResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
+                "mfaRequired": {
+                    "type": "boolean"
                 },
-                "firstname": {
+                "mfaToken": {
+                    "description": "Short-lived token used to complete the login at ` + "`" + `/admins/login/mfa` + "`" + `",
                     "type": "string"
                 },
-                "lastname": {
+                "refreshToken": {
+                    "description": "Long-lived token used to get a new access token at ` + "`" + `/admins/refresh` + "`" + `. Empty when MFA is required",
                     "type": "string"
                 },
-                "phone": {
+                "token": {
+                    "description": "Short-lived access token. Empty when MFA is required",
                     "type": "string"
                 }
             }
         },
-        "types.CreateOrderUpdateRequest": {
+        "types.LoginCustomerRequest": {
             "type": "object",
             "required": [
-                "message"
+                "email",
+                "password"
             ],
             "properties": {
-                "message": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "types.CreateSockRequest": {
+        "types.LoginCustomerResponse": {
             "type": "object",
-            "required": [
-                "sock",
-                "variants"
-            ],
             "properties": {
-                "sock": {
-                    "$ref": "#/definitions/types.SockDTO"
+                "customer": {
+                    "$ref": "#/definitions/types.Customer"
                 },
-                "variants": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/types.SockVariantDTO"
-                    }
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "types.CreateSockResponse": {
+        "types.LogoutAdminRequest": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "description": "Optional, also revokes the refresh token so it can't be used to get new access tokens",
+                    "type": "string"
+                }
+            }
+        },
+        "types.LowStockVariant": {
             "type": "object",
             "properties": {
+                "alertedAt": {
+                    "description": "When subscribed admins were alerted, NULL until the next low-stock digest",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reorderThreshold": {
+                    "type": "integer"
+                },
                 "sockId": {
                     "type": "integer"
+                },
+                "sockName": {
+                    "type": "string"
+                },
+                "sockVariantId": {
+                    "type": "integer"
                 }
             }
         },
-        "types.LoginAdminRequest": {
+        "types.MFACodeRequest": {
             "type": "object",
             "required": [
-                "password",
-                "username"
+                "code"
             ],
             "properties": {
-                "password": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.MFAEnrollmentResponse": {
+            "type": "object",
+            "properties": {
+                "provisioningUri": {
                     "type": "string"
                 },
-                "username": {
+                "secret": {
                     "type": "string"
                 }
             }
         },
-        "types.LoginAdminResponse": {
+        "types.MFARecoveryCodesResponse": {
             "type": "object",
             "properties": {
-                "token": {
-                    "type": "string"
+                "recoveryCodes": {
+                    "description": "Shown only once, each code can be used a single time instead of a TOTP code",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -1096,27 +3535,143 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
-                "invoiceNumber": {
-                    "type": "string"
+                "customerId": {
+                    "description": "NULL for guest checkouts",
+                    "type": "integer"
+                },
+                "invoiceNumber": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderItem"
+                    }
+                },
+                "orderId": {
+                    "type": "integer"
+                },
+                "paymentReference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.OrderConfirmation": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/types.Address"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "invoiceNumber": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderItem"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "types.OrderItem": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "orderItemId": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "refundedQuantity": {
+                    "type": "integer"
+                },
+                "sockVariantId": {
+                    "type": "integer"
+                },
+                "variantDescription": {
+                    "description": "Options of the variant, e.g. \"Size: M, Color: Red\"",
+                    "type": "string"
+                }
+            }
+        },
+        "types.OrderRefund": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "$ref": "#/definitions/types.OrderUpdateCreator"
                 },
                 "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.OrderItem"
+                        "$ref": "#/definitions/types.OrderRefundItem"
                     }
                 },
-                "orderId": {
+                "providerRefundId": {
+                    "description": "Refund ID within the payment provider, empty until the refund is completed",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "refundId": {
                     "type": "integer"
                 },
+                "restocked": {
+                    "type": "boolean"
+                },
                 "status": {
                     "type": "string"
-                },
-                "total": {
+                }
+            }
+        },
+        "types.OrderRefundItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "number"
+                },
+                "orderItemId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sockVariantId": {
+                    "type": "integer"
                 }
             }
         },
-        "types.OrderConfirmation": {
+        "types.OrderTracking": {
             "type": "object",
             "properties": {
                 "address": {
@@ -1139,26 +3694,23 @@ const docTemplate = `{
                 },
                 "total": {
                     "type": "number"
+                },
+                "updates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.OrderTrackingUpdate"
+                    }
                 }
             }
         },
-        "types.OrderItem": {
+        "types.OrderTrackingUpdate": {
             "type": "object",
             "properties": {
-                "name": {
+                "createdAt": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "size": {
+                "message": {
                     "type": "string"
-                },
-                "sockVariantId": {
-                    "type": "integer"
                 }
             }
         },
@@ -1174,6 +3726,10 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "isInternal": {
+                    "description": "Internal updates are only visible to admins",
+                    "type": "boolean"
+                },
                 "message": {
                     "type": "string"
                 }
@@ -1213,6 +3769,33 @@ const docTemplate = `{
                 }
             }
         },
+        "types.RefreshAdminTokenRequest": {
+            "type": "object",
+            "required": [
+                "refreshToken"
+            ],
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.RefundItemRequest": {
+            "type": "object",
+            "required": [
+                "orderItemId",
+                "quantity"
+            ],
+            "properties": {
+                "orderItemId": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
         "types.RegisterAdminRequest": {
             "type": "object",
             "required": [
@@ -1241,6 +3824,20 @@ const docTemplate = `{
                     "maxLength": 16,
                     "minLength": 8
                 },
+                "role": {
+                    "description": "Defaults to \"read_only\"",
+                    "enum": [
+                        "owner",
+                        "inventory_manager",
+                        "fulfillment",
+                        "read_only"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AdminRole"
+                        }
+                    ]
+                },
                 "username": {
                     "type": "string",
                     "maxLength": 16,
@@ -1248,6 +3845,103 @@ const docTemplate = `{
                 }
             }
         },
+        "types.RegisterCustomerRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "firstname",
+                "lastname",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "firstname": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 1
+                },
+                "lastname": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 1
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 8
+                },
+                "phone": {
+                    "type": "string",
+                    "maxLength": 16
+                }
+            }
+        },
+        "types.RenameTagRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "types.ReorderSockImagesRequest": {
+            "type": "object",
+            "required": [
+                "imageIds"
+            ],
+            "properties": {
+                "imageIds": {
+                    "description": "Every image ID of the sock, in the new display order",
+                    "type": "array",
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.ResetAdminPasswordRequest": {
+            "type": "object",
+            "required": [
+                "newPassword",
+                "token"
+            ],
+            "properties": {
+                "newPassword": {
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 8
+                },
+                "token": {
+                    "description": "Token from the password reset email",
+                    "type": "string"
+                }
+            }
+        },
+        "types.SetCollectionSocksRequest": {
+            "type": "object",
+            "required": [
+                "sockIds"
+            ],
+            "properties": {
+                "sockIds": {
+                    "description": "Every sock of the collection, in display order",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "types.SimilarSock": {
             "type": "object",
             "properties": {
@@ -1271,6 +3965,9 @@ const docTemplate = `{
         "types.Sock": {
             "type": "object",
             "properties": {
+                "categoryId": {
+                    "type": "integer"
+                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -1280,17 +3977,33 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "images": {
+                    "description": "Gallery images in display order, only set for a single sock",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.SockImage"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
                 "previewImageUrl": {
                     "type": "string"
                 },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "variants": {
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/types.SockVariant"
                     }
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -1299,9 +4012,12 @@ const docTemplate = `{
             "required": [
                 "description",
                 "name",
-                "previewImageUrl"
+                "tags"
             ],
             "properties": {
+                "categoryId": {
+                    "type": "integer"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -1309,7 +4025,46 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "previewImageUrl": {
+                    "description": "Optional, replaced by the first gallery image once images are uploaded",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Free-form, new tags are created as needed",
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.SockImage": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Zero-based position in the gallery, the first image is the sock preview",
+                    "type": "integer"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
@@ -1319,28 +4074,53 @@ const docTemplate = `{
                 "createdAt": {
                     "type": "string"
                 },
+                "description": {
+                    "description": "Human readable options in attribute order, e.g. \"Size: M, Color: Red\"",
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "options": {
+                    "description": "Attribute code to value, e.g. {\"size\": \"M\", \"color\": \"Red\"}. Unique per sock.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "price": {
                     "type": "number"
                 },
                 "quantity": {
                     "type": "integer"
                 },
-                "size": {
-                    "type": "string"
+                "reorderThreshold": {
+                    "description": "The variant is low on stock when its quantity drops below the threshold. 0 disables low-stock alerts.",
+                    "type": "integer"
+                },
+                "sockId": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
         "types.SockVariantDTO": {
             "type": "object",
             "required": [
+                "options",
                 "price",
-                "quantity",
-                "size"
+                "quantity"
             ],
             "properties": {
+                "options": {
+                    "description": "Attribute code to value, e.g. {\"size\": \"M\", \"color\": \"Red\"}. Values must be allowed by the attribute.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "price": {
                     "type": "number"
                 },
@@ -1349,14 +4129,11 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 0
                 },
-                "size": {
-                    "type": "string",
-                    "enum": [
-                        "S",
-                        "M",
-                        "LG",
-                        "XL"
-                    ]
+                "reorderThreshold": {
+                    "description": "Optional, 0 disables low-stock alerts",
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 0
                 }
             }
         },
@@ -1389,6 +4166,37 @@ const docTemplate = `{
                 }
             }
         },
+        "types.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sockCount": {
+                    "description": "Number of socks with the tag, deleted socks included",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.TrackOrderRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "invoiceNumber"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "invoiceNumber": {
+                    "type": "string",
+                    "maxLength": 36
+                }
+            }
+        },
         "types.UpdateAddressRequest": {
             "type": "object",
             "required": [
@@ -1469,6 +4277,53 @@ const docTemplate = `{
                 }
             }
         },
+        "types.UpdateAdminRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "firstname": {
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 2
+                },
+                "isActive": {
+                    "description": "Only owners can (de)activate admins. Deactivated admins can not log in",
+                    "type": "boolean"
+                },
+                "lastname": {
+                    "type": "string",
+                    "maxLength": 16,
+                    "minLength": 1
+                },
+                "lowStockAlerts": {
+                    "description": "Subscribes the admin to the low-stock digest emails",
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.UpdateAdminRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "owner",
+                        "inventory_manager",
+                        "fulfillment",
+                        "read_only"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AdminRole"
+                        }
+                    ]
+                }
+            }
+        },
         "types.UpdateContactRequest": {
             "type": "object",
             "required": [
@@ -1525,7 +4380,9 @@ const docTemplate = `{
                     "$ref": "#/definitions/types.SockDTO"
                 },
                 "variants": {
+                    "description": "The full list of variants, one per combination of options. Variants that are left out are removed.",
                     "type": "array",
+                    "minItems": 1,
                     "items": {
                         "$ref": "#/definitions/types.SockVariantDTO"
                     }
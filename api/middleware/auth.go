@@ -5,11 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
-	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/sockify/sockify/config"
 	"github.com/sockify/sockify/types"
 	"github.com/sockify/sockify/utils"
@@ -19,8 +16,10 @@ import (
 type Key string
 
 const UserKey Key = "userID"
+const RoleKey Key = "role"
+const TokenKey Key = "token"
 
-// WithJWTAuth retrieves the JWT token from the request `Authorization` header (or "token" query param) and validates it. If everything is OK, it will attach a `UserKey` context to the request.
+// WithJWTAuth retrieves the JWT token from the request `Authorization` header (or "token" query param) and validates it. Revoked tokens are rejected. If everything is OK, it will attach a `UserKey` context to the request.
 func WithJWTAuth(store types.AdminStore, nextHandler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if config.Envs.DisableAuth {
@@ -36,60 +35,57 @@ func WithJWTAuth(store types.AdminStore, nextHandler http.HandlerFunc) http.Hand
 			return
 		}
 
-		token, err := auth.ValidateJWT(tokenStr)
+		token, err := auth.ValidateUserToken(tokenStr, auth.ScopeAdmin)
 		if err != nil {
 			log.Printf("unable to validate token: %v", err)
 			permissionUnauthorized(w)
 			return
 		}
 
-		if !token.Valid {
-			log.Println("invalid JWT token provided.")
+		revoked, err := store.IsTokenIDRevoked(token.TokenID)
+		if err != nil {
+			log.Printf("failed to check if token is revoked: %v", err)
 			permissionUnauthorized(w)
 			return
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
-		userIDStr := claims["userId"].(string)
-		expiredAtStr := claims["expiredAt"].(string)
-
-		userID, err := strconv.Atoi(userIDStr)
-		if err != nil {
-			log.Printf("failed to convert userId to int: %v", err)
+		if revoked {
+			log.Printf("token %v has been revoked", token.TokenID)
 			permissionUnauthorized(w)
 			return
 		}
 
-		expiredAt, err := strconv.ParseInt(expiredAtStr, 10, 64)
+		userID := token.UserID
+		admin, err := store.GetAdminByID(userID)
 		if err != nil {
-			log.Printf("failed to convert expiredAt to int: %v", err)
+			log.Printf("failed to get admin by id: %v", err)
 			permissionUnauthorized(w)
 			return
 		}
 
-		expiredAtTime := time.Unix(expiredAt, 0)
-		currentTime := time.Now()
-		if expiredAtTime.Before(currentTime) {
-			log.Printf("token has expired")
+		if admin == nil {
+			log.Printf("no admin found with id: %v", userID)
 			permissionUnauthorized(w)
 			return
 		}
 
-		admin, err := store.GetAdminByID(userID)
-		if err != nil {
-			log.Printf("failed to get admin by id: %v", err)
+		if !admin.IsActive {
+			log.Printf("admin id %v has been deactivated", userID)
 			permissionUnauthorized(w)
 			return
 		}
 
-		if admin == nil {
-			log.Printf("no admin found with id: %v", userID)
+		// Tokens issued before a role change must not keep the old permissions
+		if token.Role != string(admin.Role) {
+			log.Printf("token role '%v' does not match the role '%v' of admin id: %v", token.Role, admin.Role, userID)
 			permissionUnauthorized(w)
 			return
 		}
 
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, UserKey, admin.ID)
+		ctx = context.WithValue(ctx, RoleKey, admin.Role)
+		ctx = context.WithValue(ctx, TokenKey, token)
 		r = r.WithContext(ctx)
 
 		nextHandler(w, r)
@@ -110,6 +106,12 @@ func GetUserIDFromContext(ctx context.Context) int {
 	return userID
 }
 
+// GetTokenFromContext returns the validated token attached by `WithJWTAuth`.
+func GetTokenFromContext(ctx context.Context) (*auth.UserToken, bool) {
+	token, ok := ctx.Value(TokenKey).(*auth.UserToken)
+	return token, ok
+}
+
 func getTokenFromRequest(r *http.Request) (string, error) {
 	tokenAuth := r.Header.Get("Authorization")
 	tokenQuery := r.URL.Query().Get("token")
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+const CustomerKey Key = "customerID"
+
+// WithCustomerAuth requires a valid customer JWT token. If everything is OK, it will attach a `CustomerKey` context to the request.
+func WithCustomerAuth(store types.CustomerStore, nextHandler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		customerID, ok := authenticateCustomer(store, r)
+		if !ok {
+			permissionUnauthorized(w)
+			return
+		}
+
+		nextHandler(w, r.WithContext(context.WithValue(r.Context(), CustomerKey, customerID)))
+	}
+}
+
+// WithOptionalCustomerAuth attaches a `CustomerKey` context when the request has a customer JWT token, and lets anonymous requests through.
+// A token that is provided but invalid is still rejected, so customers are not silently checked out as guests.
+func WithOptionalCustomerAuth(store types.CustomerStore, nextHandler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && r.URL.Query().Get("token") == "" {
+			nextHandler(w, r)
+			return
+		}
+
+		WithCustomerAuth(store, nextHandler)(w, r)
+	}
+}
+
+// GetCustomerIDFromContext returns the `CustomerKey` from the context, if the request was authenticated as a customer.
+func GetCustomerIDFromContext(ctx context.Context) (customerID int, ok bool) {
+	customerID, ok = ctx.Value(CustomerKey).(int)
+	return customerID, ok
+}
+
+func authenticateCustomer(store types.CustomerStore, r *http.Request) (customerID int, ok bool) {
+	tokenStr, err := getTokenFromRequest(r)
+	if err != nil {
+		log.Printf("unable to get token from request: %v", err)
+		return 0, false
+	}
+
+	token, err := auth.ValidateUserToken(tokenStr, auth.ScopeCustomer)
+	if err != nil {
+		log.Printf("unable to validate customer token: %v", err)
+		return 0, false
+	}
+
+	customerID = token.UserID
+	customer, err := store.GetCustomerByID(customerID)
+	if err != nil {
+		log.Printf("failed to get customer by id: %v", err)
+		return 0, false
+	}
+	if customer == nil {
+		log.Printf("no customer found with id: %v", customerID)
+		return 0, false
+	}
+
+	return customer.ID, true
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sockify/sockify/utils"
+)
+
+// RateLimiter is an in-memory, fixed window rate limiter keyed by an arbitrary string (e.g. client IP).
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	windows   map[string]*rateWindow
+	lastPrune time.Time
+}
+
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:     limit,
+		window:    window,
+		windows:   make(map[string]*rateWindow),
+		lastPrune: time.Now(),
+	}
+}
+
+// Allow records a request for the key. When the limit is exceeded, it returns false along with how long to wait before retrying.
+func (l *RateLimiter) Allow(key string) (allowed bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	win, ok := l.windows[key]
+	if !ok || !now.Before(win.resetAt) {
+		win = &rateWindow{resetAt: now.Add(l.window)}
+		l.windows[key] = win
+	}
+
+	if win.count >= l.limit {
+		return false, win.resetAt.Sub(now)
+	}
+
+	win.count++
+	return true, 0
+}
+
+// prune drops expired windows so the map does not grow unbounded.
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.window {
+		return
+	}
+
+	for key, win := range l.windows {
+		if !now.Before(win.resetAt) {
+			delete(l.windows, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// WithRateLimit rejects requests with a 429 once the client IP exceeds the limiter's allowance.
+func WithRateLimit(limiter *RateLimiter, nextHandler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed, retryAfter := limiter.Allow(utils.GetClientIP(r))
+		if !allowed {
+			utils.WriteTooManyRequests(w, retryAfter)
+			return
+		}
+
+		nextHandler(w, r)
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/sockify/sockify/utils"
+)
+
+const RequestIDKey Key = "requestID"
+
+// Incoming request IDs are only reused when they are short and safe to log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// WithRequestID tags every request with an ID, reusing the `X-Request-ID` header sent by a proxy or client when there is one.
+// The ID is returned in the `X-Request-ID` response header and attached to the request context.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(utils.RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		w.Header().Set(utils.RequestIDHeader, requestID)
+		ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetRequestIDFromContext returns the `RequestIDKey` from the context.
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
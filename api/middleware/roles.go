@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// Role groups used by the route declarations.
+var (
+	AllAdminRoles     = []types.AdminRole{types.RoleOwner, types.RoleInventoryManager, types.RoleFulfillment, types.RoleReadOnly}
+	OwnerOnly         = []types.AdminRole{types.RoleOwner}
+	InventoryManagers = []types.AdminRole{types.RoleOwner, types.RoleInventoryManager}
+	FulfillmentStaff  = []types.AdminRole{types.RoleOwner, types.RoleFulfillment}
+)
+
+// RequireRole only lets admins with one of the given roles through. It must be wrapped by `WithJWTAuth`, which attaches the role to the request.
+func RequireRole(roles []types.AdminRole, nextHandler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if config.Envs.DisableAuth {
+			nextHandler(w, r)
+			return
+		}
+
+		role, ok := GetRoleFromContext(r.Context())
+		if !ok || !slices.Contains(roles, role) {
+			log.Printf("admin id %v with role '%v' is not allowed to %v %v", GetUserIDFromContext(r.Context()), role, r.Method, r.URL.Path)
+			utils.WriteError(w, http.StatusForbidden, fmt.Errorf("your role does not have permission to perform this action"))
+			return
+		}
+
+		nextHandler(w, r)
+	}
+}
+
+// WithRole authenticates the admin and requires one of the given roles. Shorthand for `WithJWTAuth` wrapping `RequireRole`.
+func WithRole(store types.AdminStore, roles []types.AdminRole, nextHandler http.HandlerFunc) http.HandlerFunc {
+	return WithJWTAuth(store, RequireRole(roles, nextHandler))
+}
+
+// GetRoleFromContext returns the `RoleKey` from the context.
+func GetRoleFromContext(ctx context.Context) (types.AdminRole, bool) {
+	role, ok := ctx.Value(RoleKey).(types.AdminRole)
+	return role, ok
+}
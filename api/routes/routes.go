@@ -2,39 +2,61 @@ package routes
 
 import (
 	"database/sql"
+	"log"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/sendgrid/sendgrid-go"
 	"github.com/sockify/sockify/config"
 	"github.com/sockify/sockify/services/admin"
 	"github.com/sockify/sockify/services/cart"
-	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/services/catalog"
+	"github.com/sockify/sockify/services/customers"
 	"github.com/sockify/sockify/services/inventory"
 	"github.com/sockify/sockify/services/newsletter"
 	"github.com/sockify/sockify/services/orders"
+	"github.com/sockify/sockify/services/payments"
+	"github.com/sockify/sockify/services/storage"
+	"github.com/sockify/sockify/types"
 )
 
 func Router(db *sql.DB) *mux.Router {
 	router := mux.NewRouter()
 	subrouter := router.PathPrefix("/api/v1").Subrouter()
 
-	client := sendgrid.NewSendClient(config.Envs.SendGridAPIKey)
-	emailService := email.NewService(client)
+	paymentGateway := newPaymentGateway()
 
 	adminStore := admin.NewStore(db)
-	adminHandler := admin.NewHandler(adminStore)
+	loginThrottler := admin.NewLoginThrottler(
+		newLoginThrottleStore(db),
+		int(config.Envs.LoginMaxFailuresPerUsername),
+		int(config.Envs.LoginMaxFailuresPerIP),
+		time.Duration(config.Envs.LoginLockoutInSeconds)*time.Second,
+	)
+	adminHandler := admin.NewHandler(adminStore, loginThrottler)
 	adminHandler.RegisterRoutes(subrouter)
 
+	blobStore := newBlobStore()
+	storageHandler := storage.NewHandler(blobStore)
+	storageHandler.RegisterRoutes(subrouter)
+
 	sockStore := inventory.NewSockStore(db)
-	sockHandler := inventory.NewSockHandler(sockStore)
+	sockHandler := inventory.NewSockHandler(sockStore, blobStore)
 	sockHandler.RegisterRoutes(subrouter, adminStore)
 
+	catalogStore := catalog.NewStore(db)
+	catalogHandler := catalog.NewHandler(catalogStore, catalog.NewAttributeStore(db))
+	catalogHandler.RegisterRoutes(subrouter, adminStore)
+
 	orderStore := orders.NewOrderStore(db, sockStore)
-	orderHandler := orders.NewOrderHandler(orderStore)
+	orderHandler := orders.NewOrderHandler(orderStore, paymentGateway)
 	orderHandler.RegisterRoutes(subrouter, adminStore)
 
-	cartHandler := cart.NewCartHandler(sockStore, orderStore, emailService)
-	cartHandler.RegisterRoutes(subrouter)
+	customerStore := customers.NewStore(db)
+	customerHandler := customers.NewHandler(customerStore, orderStore)
+	customerHandler.RegisterRoutes(subrouter)
+
+	cartHandler := cart.NewCartHandler(sockStore, orderStore, paymentGateway)
+	cartHandler.RegisterRoutes(subrouter, customerStore)
 
 	newsletterStore := newsletter.NewStore(db)
 	newsletterHandler := newsletter.NewHandler(newsletterStore)
@@ -42,3 +64,51 @@ func Router(db *sql.DB) *mux.Router {
 
 	return router
 }
+
+// newLoginThrottleStore returns the login throttle store configured through `LOGIN_THROTTLE_STORE`.
+func newLoginThrottleStore(db *sql.DB) types.LoginThrottleStore {
+	switch config.Envs.LoginThrottleStore {
+	case "memory":
+		log.Println("Tracking failed logins in memory. Limits are not shared between API replicas!")
+		return admin.NewMemoryLoginThrottleStore()
+	default:
+		return admin.NewLoginThrottleStore(db)
+	}
+}
+
+// newPaymentGateway returns the payment gateway configured through `PAYMENT_PROVIDER`.
+func newPaymentGateway() types.PaymentGateway {
+	if config.Envs.StripeWebhookSecret == "" {
+		log.Println("[WARN] STRIPE_WEBHOOK_SECRET is not set, payment webhooks will be rejected")
+	}
+
+	switch config.Envs.PaymentProvider {
+	case "fake":
+		log.Println("Using the fake in-memory payment gateway. Orders are paid without charging anyone!")
+		return payments.NewFakeGateway(config.Envs.StripeWebhookSecret)
+	default:
+		return payments.NewStripeGateway(config.Envs.StripeAPIKey, config.Envs.StripeWebhookSecret)
+	}
+}
+
+// newBlobStore returns the blob store configured through `BLOB_STORE`.
+func newBlobStore() types.BlobStore {
+	switch config.Envs.BlobStore {
+	case "s3":
+		store, err := storage.NewS3BlobStore(
+			config.Envs.S3Endpoint,
+			config.Envs.S3Region,
+			config.Envs.S3Bucket,
+			config.Envs.S3AccessKeyID,
+			config.Envs.S3SecretAccessKey,
+			config.Envs.S3ForcePathStyle,
+		)
+		if err != nil {
+			log.Fatalf("Unable to configure the S3 blob store: %v", err)
+		}
+		return store
+	default:
+		log.Printf("Storing uploaded files in %v. Files are not shared between API replicas!", config.Envs.BlobStoreLocalDir)
+		return storage.NewLocalBlobStore(config.Envs.BlobStoreLocalDir)
+	}
+}
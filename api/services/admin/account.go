@@ -0,0 +1,281 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/services/email/templates"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+// @Summary Updates an admin
+// @Description Updates the profile of an admin. Admins can update their own profile, owners can update anyone's. Only owners can (de)activate admins; deactivated admins are signed out and can not log in. Active admins with `lowStockAlerts` receive a digest email when variants drop below their reorder threshold.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param admin_id path int true "Admin ID"
+// @Param Body body types.UpdateAdminRequest true "Fields to update"
+// @Success 200 {object} types.Admin
+// @Router /admins/{admin_id} [patch]
+func (h *Handler) handleUpdateAdmin(w http.ResponseWriter, r *http.Request) {
+	adminID, err := strconv.Atoi(mux.Vars(r)["admin_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid admin ID"))
+		return
+	}
+
+	var payload types.UpdateAdminRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	currentAdminID := middleware.GetUserIDFromContext(r.Context())
+	role, _ := middleware.GetRoleFromContext(r.Context())
+	isOwner := role == types.RoleOwner || config.Envs.DisableAuth
+	if adminID != currentAdminID && !isOwner {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("only owners can update other admins"))
+		return
+	}
+
+	admin, err := h.store.GetAdminByID(adminID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if admin == nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("admin with id %v does not exist", adminID))
+		return
+	}
+
+	if payload.IsActive != nil && *payload.IsActive != admin.IsActive {
+		if !isOwner {
+			utils.WriteError(w, http.StatusForbidden, fmt.Errorf("only owners can activate or deactivate admins"))
+			return
+		}
+
+		if !*payload.IsActive {
+			if adminID == currentAdminID {
+				utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("you can not deactivate your own account"))
+				return
+			}
+		}
+
+		// Applied first, so nothing is updated when the last owner is deactivated
+		if err := h.store.SetAdminActive(adminID, *payload.IsActive); err != nil {
+			if errors.Is(err, types.ErrLastOwner) {
+				utils.WriteError(w, http.StatusBadRequest, err)
+				return
+			}
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	firstName, lastName, email := admin.FirstName, admin.LastName, admin.Email
+	if payload.FirstName != nil {
+		firstName = utils.TitleCase(*payload.FirstName)
+	}
+	if payload.LastName != nil {
+		lastName = utils.TitleCase(*payload.LastName)
+	}
+	if payload.Email != nil {
+		email = utils.Normalize(*payload.Email)
+	}
+
+	if err := h.store.UpdateAdmin(adminID, firstName, lastName, email); err != nil {
+		if errors.Is(err, types.ErrAdminEmailAlreadyExists) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if payload.LowStockAlerts != nil && *payload.LowStockAlerts != admin.LowStockAlerts {
+		if err := h.store.SetLowStockAlerts(adminID, *payload.LowStockAlerts); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	updated, err := h.store.GetAdminByID(adminID)
+	if err != nil || updated == nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to retrieve the updated admin: %v", err))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, updated)
+}
+
+// @Summary Changes the password of the logged in admin
+// @Description Changes the password of the logged in admin after checking the current one. All refresh tokens of the admin are revoked, so other sessions have to log in again.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Body body types.ChangeAdminPasswordRequest true "Current and new password"
+// @Success 200 {object} types.Message
+// @Router /admins/me/password [patch]
+func (h *Handler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	var payload types.ChangeAdminPasswordRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := h.getCurrentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if !auth.ComparePasswords(admin.PasswordHash, payload.CurrentPassword) {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("current password is incorrect"))
+		return
+	}
+
+	passwordHash, ok := hashNewPassword(w, payload.NewPassword)
+	if !ok {
+		return
+	}
+
+	if err := h.store.UpdateAdminPassword(admin.ID, passwordHash); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Password changed successfully"})
+}
+
+// @Summary Requests an admin password reset
+// @Description Emails a single-use password reset link to the admin with the given email. The response is the same whether or not the email belongs to an admin.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Param Body body types.ForgotAdminPasswordRequest true "Admin email"
+// @Success 202 {object} types.Message
+// @Router /admins/password/forgot [post]
+func (h *Handler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var payload types.ForgotAdminPasswordRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// Don't reveal which emails belong to an admin
+	response := types.Message{Message: "If the email belongs to an admin, a password reset link has been sent to it"}
+
+	admin, err := h.store.GetAdminByEmail(utils.Normalize(payload.Email))
+	if err != nil || admin == nil || !admin.IsActive {
+		utils.WriteJson(w, http.StatusAccepted, response)
+		return
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	expiresIn := time.Duration(config.Envs.PasswordResetExpirationInSeconds) * time.Second
+	resetURL := fmt.Sprintf("%s/admin/reset-password?token=%s", config.Envs.WebClientURL, url.QueryEscape(token))
+	plainText, htmlContent := templates.CreateAdminPasswordResetTemplate(*admin, resetURL, expiresIn)
+	resetEmail := types.OutboundEmail{
+		ToName:      admin.FirstName + " " + admin.LastName,
+		ToEmail:     admin.Email,
+		Subject:     templates.AdminPasswordResetSubject,
+		PlainText:   plainText,
+		HTMLContent: htmlContent,
+	}
+
+	err = h.store.CreatePasswordResetToken(admin.ID, auth.HashOpaqueToken(token), time.Now().Add(expiresIn), resetEmail)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	log.Printf("Password reset requested for admin ID %v", admin.ID)
+	utils.WriteJson(w, http.StatusAccepted, response)
+}
+
+// @Summary Resets an admin password
+// @Description Sets a new password using the token from a password reset email. All refresh tokens of the admin are revoked.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Param Body body types.ResetAdminPasswordRequest true "Reset token and new password"
+// @Success 200 {object} types.Message
+// @Router /admins/password/reset [post]
+func (h *Handler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var payload types.ResetAdminPasswordRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	passwordHash, ok := hashNewPassword(w, payload.NewPassword)
+	if !ok {
+		return
+	}
+
+	adminID, ok, err := h.store.ResetPasswordWithToken(auth.HashOpaqueToken(payload.Token), passwordHash)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("password reset link is invalid or has expired"))
+		return
+	}
+
+	log.Printf("Password reset completed for admin ID %v", adminID)
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Password reset successfully, please log in with your new password"})
+}
+
+// hashNewPassword checks the password against the minimum requirements and hashes it, writing an error response if it can't.
+func hashNewPassword(w http.ResponseWriter, password string) (string, bool) {
+	if err := auth.ValidatePassword(password); err != nil {
+		utils.WriteError(w, http.StatusBadRequest,
+			fmt.Errorf("password does not meet the minimum requirements: %v", err),
+		)
+		return "", false
+	}
+
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to hash password: %v", err))
+		return "", false
+	}
+	return passwordHash, true
+}
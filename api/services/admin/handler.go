@@ -1,11 +1,13 @@
 package admin
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/sockify/sockify/config"
 	"github.com/sockify/sockify/middleware"
@@ -15,18 +17,30 @@ import (
 )
 
 type Handler struct {
-	store types.AdminStore
+	store     types.AdminStore
+	throttler *LoginThrottler
 }
 
-func NewHandler(store types.AdminStore) *Handler {
-	return &Handler{store: store}
+func NewHandler(store types.AdminStore, throttler *LoginThrottler) *Handler {
+	return &Handler{store: store, throttler: throttler}
 }
 
 func (h *Handler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/admins/login", h.handleAdminLogin).Methods(http.MethodPost)
-	router.HandleFunc("/admins/register", middleware.WithJWTAuth(h.store, h.handleAdminRegister)).Methods(http.MethodPost)
-	router.HandleFunc("/admins", middleware.WithJWTAuth(h.store, h.handleGetAdmins)).Methods(http.MethodGet)
-	router.HandleFunc("/admins/{admin_id}", middleware.WithJWTAuth(h.store, h.handleGetAdmin)).Methods(http.MethodGet)
+	router.HandleFunc("/admins/login/mfa", h.handleAdminLoginMFA).Methods(http.MethodPost)
+	router.HandleFunc("/admins/refresh", h.handleRefreshToken).Methods(http.MethodPost)
+	router.HandleFunc("/admins/logout", middleware.WithJWTAuth(h.store, h.handleLogout)).Methods(http.MethodPost)
+	router.HandleFunc("/admins/password/forgot", h.handleForgotPassword).Methods(http.MethodPost)
+	router.HandleFunc("/admins/password/reset", h.handleResetPassword).Methods(http.MethodPost)
+	router.HandleFunc("/admins/register", middleware.WithRole(h.store, middleware.OwnerOnly, h.handleAdminRegister)).Methods(http.MethodPost)
+	router.HandleFunc("/admins", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleGetAdmins)).Methods(http.MethodGet)
+	router.HandleFunc("/admins/me/password", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleChangePassword)).Methods(http.MethodPatch)
+	router.HandleFunc("/admins/me/mfa/enroll", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleEnrollMFA)).Methods(http.MethodPost)
+	router.HandleFunc("/admins/me/mfa/confirm", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleConfirmMFA)).Methods(http.MethodPost)
+	router.HandleFunc("/admins/me/mfa", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleDisableMFA)).Methods(http.MethodDelete)
+	router.HandleFunc("/admins/{admin_id}", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleGetAdmin)).Methods(http.MethodGet)
+	router.HandleFunc("/admins/{admin_id}", middleware.WithRole(h.store, middleware.AllAdminRoles, h.handleUpdateAdmin)).Methods(http.MethodPatch)
+	router.HandleFunc("/admins/{admin_id}/role", middleware.WithRole(h.store, middleware.OwnerOnly, h.handleUpdateAdminRole)).Methods(http.MethodPatch)
 }
 
 // @Summary Get all admins.
@@ -69,7 +83,7 @@ func (h *Handler) handleGetAdmin(w http.ResponseWriter, r *http.Request) {
 
 	adminID, err := strconv.Atoi(adminIDStr)
 	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid admin ID: %v", adminID))
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid admin ID: %v", adminIDStr))
 		return
 	}
 
@@ -88,7 +102,7 @@ func (h *Handler) handleGetAdmin(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Logs in an admin.
-// @Description Logs in an admin using username and password credentials.
+// @Description Logs in an admin using username and password credentials. Returns a short-lived access token and a refresh token. Repeated failures are throttled with a 429 and a "Retry-After" header. When the admin has MFA enabled, no token is returned; instead "mfaRequired" is set along with an "mfaToken" to complete the login at `/admins/login/mfa`.
 // @Tags Admins
 // @Accept json
 // @Produce json
@@ -104,35 +118,56 @@ func (h *Handler) handleAdminLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", errors))
+		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	username := utils.Normalize(payload.UserName)
+	ipAddress := utils.GetClientIP(r)
+	attempt := h.checkLoginThrottle(w, username, ipAddress)
+	if attempt == nil {
+		return
+	}
+
 	admin, err := h.store.GetAdminByUsername(username)
-	if err != nil {
+	if err != nil || admin == nil || !auth.ComparePasswords(admin.PasswordHash, payload.Password) {
+		h.recordLoginFailure(attempt, loginFailureInvalidCredentials)
 		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid username or password"))
 		return
 	}
 
-	if !auth.ComparePasswords(admin.PasswordHash, payload.Password) {
-		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid username or password"))
+	if !admin.IsActive {
+		// The password was right, so the attempt is not counted as a failure
+		h.recordLoginSuccess(attempt)
+		if err := h.throttler.RecordRejected(username, ipAddress, loginFailureDeactivated); err != nil {
+			log.Printf("Unable to audit login of deactivated admin %v: %v", username, err)
+		}
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("this account has been deactivated"))
 		return
 	}
 
 	secret := []byte(config.Envs.JWTSecret)
-	jwtToken, err := auth.CreateJWTToken(secret, admin.ID)
-	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+	if admin.MFAEnabled {
+		// Failures of the MFA step are still counted against the username, so they are not cleared here
+		h.releaseLoginAttempt(attempt)
+
+		expiration := time.Duration(config.Envs.MFATokenExpirationInSeconds) * time.Second
+		mfaToken, err := auth.CreateJWTTokenWithExpiration(secret, admin.ID, auth.ScopeMFAPending, expiration)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		utils.WriteJson(w, http.StatusOK, types.LoginAdminResponse{MFARequired: true, MFAToken: mfaToken})
 		return
 	}
 
-	utils.WriteJson(w, http.StatusOK, types.LoginAdminResponse{Token: jwtToken})
+	h.recordLoginSuccess(attempt)
+	h.writeLoginResponse(w, *admin)
 }
 
 // @Summary Registers new admin credentials.
-// @Description Creates a new set of admin credentials.
+// @Description Creates a new set of admin credentials. The role defaults to "read_only". Only owners can register admins.
 // @Tags Admins
 // @Accept json
 // @Produce json
@@ -149,8 +184,7 @@ func (h *Handler) handleAdminRegister(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %v", errors))
+		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -182,12 +216,18 @@ func (h *Handler) handleAdminRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	role := payload.Role
+	if role == "" {
+		role = types.RoleReadOnly
+	}
+
 	err = h.store.CreateAdmin(
 		utils.TitleCase(payload.FirstName),
 		utils.TitleCase(payload.LastName),
 		email,
 		username,
 		passwordHash,
+		role,
 	)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError,
@@ -198,3 +238,53 @@ func (h *Handler) handleAdminRegister(w http.ResponseWriter, r *http.Request) {
 
 	utils.WriteJson(w, http.StatusCreated, types.Message{Message: "Successfully created the new admin credentials."})
 }
+
+// @Summary Updates the role of an admin
+// @Description Changes the role of an admin. Only owners can change roles, and the last owner can not be demoted. The admin has to log in again for the new role to apply.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param admin_id path int true "Admin ID"
+// @Param Body body types.UpdateAdminRoleRequest true "New role"
+// @Success 200 {object} types.Message
+// @Router /admins/{admin_id}/role [patch]
+func (h *Handler) handleUpdateAdminRole(w http.ResponseWriter, r *http.Request) {
+	adminID, err := strconv.Atoi(mux.Vars(r)["admin_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid admin ID"))
+		return
+	}
+
+	var payload types.UpdateAdminRoleRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, err := h.store.GetAdminByID(adminID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if admin == nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("admin with id %v does not exist", adminID))
+		return
+	}
+
+	if err := h.store.UpdateAdminRole(adminID, payload.Role); err != nil {
+		if errors.Is(err, types.ErrLastOwner) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Admin role updated successfully"})
+}
@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+// fakeAdminStore keeps a single admin in memory. Only the methods used by logins are implemented.
+type fakeAdminStore struct {
+	types.AdminStore
+	mu            sync.Mutex
+	admin         types.Admin
+	recoveryCodes map[string]bool
+}
+
+func newFakeAdminStore(t *testing.T, password string, recoveryCodes ...string) *fakeAdminStore {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := "JBSWY3DPEHPK3PXP"
+
+	s := &fakeAdminStore{
+		admin: types.Admin{
+			ID:           7,
+			Username:     "jane",
+			PasswordHash: hash,
+			Role:         types.RoleOwner,
+			IsActive:     true,
+			MFAEnabled:   true,
+			MFASecret:    &secret,
+		},
+		recoveryCodes: make(map[string]bool),
+	}
+	for _, code := range recoveryCodes {
+		s.recoveryCodes[auth.HashRecoveryCode(code)] = true
+	}
+	return s
+}
+
+func (s *fakeAdminStore) GetAdminByUsername(username string) (*types.Admin, error) {
+	if username != s.admin.Username {
+		return nil, nil
+	}
+	a := s.admin
+	return &a, nil
+}
+
+func (s *fakeAdminStore) GetAdminByID(id int) (*types.Admin, error) {
+	if id != s.admin.ID {
+		return nil, nil
+	}
+	a := s.admin
+	return &a, nil
+}
+
+func (s *fakeAdminStore) UseRecoveryCode(adminID int, codeHash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	delete(s.recoveryCodes, codeHash)
+	return true, nil
+}
+
+func (s *fakeAdminStore) CreateRefreshToken(adminID int, tokenHash string, expiresAt time.Time) error {
+	return nil
+}
+
+func postJson(handler http.HandlerFunc, path string, body any) (*httptest.ResponseRecorder, types.LoginAdminResponse) {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.RemoteAddr = "203.0.113.7:4321"
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	var res types.LoginAdminResponse
+	json.Unmarshal(rr.Body.Bytes(), &res)
+	return rr, res
+}
+
+func TestMFALoginsDoNotLockOutTheIP(t *testing.T) {
+	store := newFakeAdminStore(t, "Sup3r-secret!", "aaaaa-11111", "bbbbb-22222")
+	// Only 2 attempts per IP, so an attempt left behind by the first login blocks the second one
+	h := NewHandler(store, NewLoginThrottler(NewMemoryLoginThrottleStore(), 4, 2, time.Minute))
+
+	for i, code := range []string{"aaaaa-11111", "bbbbb-22222"} {
+		rr, res := postJson(h.handleAdminLogin, "/admins/login", types.LoginAdminRequest{UserName: "jane", Password: "Sup3r-secret!"})
+		if rr.Code != http.StatusOK || !res.MFARequired {
+			t.Fatalf("login %d: expected MFA to be required, got status %d: %s", i+1, rr.Code, rr.Body.String())
+		}
+
+		rr, res = postJson(h.handleAdminLoginMFA, "/admins/login/mfa", types.LoginAdminMFARequest{MFAToken: res.MFAToken, Code: code})
+		if rr.Code != http.StatusOK || res.Token == "" {
+			t.Fatalf("login %d: expected the MFA step to succeed, got status %d: %s", i+1, rr.Code, rr.Body.String())
+		}
+	}
+}
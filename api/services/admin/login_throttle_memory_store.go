@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+// MemoryLoginThrottleStore keeps failed logins in memory. Limits are per process, so only use it with a single API replica.
+type MemoryLoginThrottleStore struct {
+	mu        sync.Mutex
+	throttles map[string]*loginThrottle
+	lastPrune time.Time
+}
+
+type loginThrottle struct {
+	// Attempts that did not succeed yet
+	failures      int
+	lastFailureAt time.Time
+	lockedUntil   time.Time
+	window        time.Duration
+}
+
+func NewMemoryLoginThrottleStore() types.LoginThrottleStore {
+	return &MemoryLoginThrottleStore{throttles: make(map[string]*loginThrottle), lastPrune: time.Now()}
+}
+
+func (s *MemoryLoginThrottleStore) RecordLoginAttempt(key string, window time.Duration) (int, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.prune(now, window)
+
+	throttle, ok := s.throttles[key]
+	if !ok {
+		throttle = &loginThrottle{}
+		s.throttles[key] = throttle
+	}
+
+	if now.Before(throttle.lockedUntil) {
+		lockedUntil := throttle.lockedUntil
+		return throttle.failures, &lockedUntil, nil
+	}
+
+	if now.Sub(throttle.lastFailureAt) > window {
+		throttle.failures = 0
+	}
+	throttle.failures++
+	throttle.lastFailureAt = now
+	throttle.window = window
+
+	return throttle.failures, nil, nil
+}
+
+func (s *MemoryLoginThrottleStore) ReleaseLoginAttempt(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if throttle, ok := s.throttles[key]; ok && throttle.failures > 0 {
+		throttle.failures--
+	}
+	return nil
+}
+
+func (s *MemoryLoginThrottleStore) LockLogin(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if throttle, ok := s.throttles[key]; ok && until.After(throttle.lockedUntil) {
+		throttle.lockedUntil = until
+	}
+	return nil
+}
+
+func (s *MemoryLoginThrottleStore) ClearLoginFailures(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.throttles, key)
+	return nil
+}
+
+// RecordFailedAdminLogin writes the failed login to the logs, since there is nowhere to keep it.
+func (s *MemoryLoginThrottleStore) RecordFailedAdminLogin(username string, ipAddress string, reason string) error {
+	log.Printf("[AUDIT] failed admin login for username '%v' from %v: %v", username, ipAddress, reason)
+	return nil
+}
+
+// prune drops keys that have been quiet for a while so the map does not grow unbounded.
+func (s *MemoryLoginThrottleStore) prune(now time.Time, window time.Duration) {
+	if now.Sub(s.lastPrune) < window {
+		return
+	}
+
+	for key, throttle := range s.throttles {
+		if now.Sub(throttle.lastFailureAt) > throttle.window && !now.Before(throttle.lockedUntil) {
+			delete(s.throttles, key)
+		}
+	}
+	s.lastPrune = now
+}
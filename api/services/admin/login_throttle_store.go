@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+// LoginThrottleStore keeps failed logins in Postgres, so the limits are shared by all API replicas.
+type LoginThrottleStore struct {
+	db *sql.DB
+}
+
+func NewLoginThrottleStore(db *sql.DB) types.LoginThrottleStore {
+	return &LoginThrottleStore{db: db}
+}
+
+// RecordLoginAttempt increments and reads the attempts in a single statement, so concurrent attempts always see
+// each other. `failures` counts the attempts that did not succeed yet.
+func (s *LoginThrottleStore) RecordLoginAttempt(key string, window time.Duration) (attempts int, lockedUntil *time.Time, err error) {
+	err = s.db.QueryRow(`
+    INSERT INTO login_throttles (throttle_key, failures, last_failure_at)
+    VALUES ($1, 1, CURRENT_TIMESTAMP)
+    ON CONFLICT (throttle_key) DO UPDATE SET
+      failures = CASE
+        WHEN login_throttles.locked_until > CURRENT_TIMESTAMP THEN login_throttles.failures
+        WHEN login_throttles.last_failure_at < CURRENT_TIMESTAMP - $2 * INTERVAL '1 second' THEN 1
+        ELSE login_throttles.failures + 1
+      END,
+      last_failure_at = CASE
+        WHEN login_throttles.locked_until > CURRENT_TIMESTAMP THEN login_throttles.last_failure_at
+        ELSE CURRENT_TIMESTAMP
+      END
+    RETURNING failures, CASE WHEN locked_until > CURRENT_TIMESTAMP THEN locked_until END
+  `, key, window.Seconds()).Scan(&attempts, &lockedUntil)
+	if err != nil {
+		log.Printf("Error recording login attempt for %v: %v", key, err)
+		return 0, nil, err
+	}
+
+	// Forget keys that have been quiet for a while so the table does not grow unbounded
+	_, err = s.db.Exec(`
+    DELETE FROM login_throttles
+    WHERE last_failure_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'
+      AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+  `, window.Seconds())
+	if err != nil {
+		log.Printf("Error cleaning up login throttles: %v", err)
+	}
+
+	return attempts, lockedUntil, nil
+}
+
+func (s *LoginThrottleStore) ReleaseLoginAttempt(key string) error {
+	_, err := s.db.Exec("UPDATE login_throttles SET failures = GREATEST(failures - 1, 0) WHERE throttle_key = $1", key)
+	if err != nil {
+		log.Printf("Error releasing login attempt for %v: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+func (s *LoginThrottleStore) LockLogin(key string, until time.Time) error {
+	// Concurrent failures never shorten a lockout
+	_, err := s.db.Exec(`
+    UPDATE login_throttles SET locked_until = GREATEST(locked_until, $1)
+    WHERE throttle_key = $2
+  `, until.UTC(), key)
+	if err != nil {
+		log.Printf("Error locking login for %v: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+func (s *LoginThrottleStore) ClearLoginFailures(key string) error {
+	_, err := s.db.Exec("DELETE FROM login_throttles WHERE throttle_key = $1", key)
+	if err != nil {
+		log.Printf("Error clearing login failures for %v: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+func (s *LoginThrottleStore) RecordFailedAdminLogin(username string, ipAddress string, reason string) error {
+	_, err := s.db.Exec(`
+    INSERT INTO failed_admin_logins (username, ip_address, reason)
+    VALUES ($1, $2, $3)
+  `, username, ipAddress, reason)
+	if err != nil {
+		log.Printf("Error recording failed login for username %v: %v", username, err)
+		return err
+	}
+	return nil
+}
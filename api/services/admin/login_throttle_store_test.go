@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sockify/sockify/database/dbtest"
+)
+
+func newPostgresTestThrottler(t *testing.T) (*LoginThrottler, func(query string, args ...any) int) {
+	db := dbtest.New(t)
+	count := func(query string, args ...any) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	return NewLoginThrottler(NewLoginThrottleStore(db), 4, 100, time.Minute), count
+}
+
+func TestPostgresLoginThrottlerLimitsConcurrentAttempts(t *testing.T) {
+	throttler, _ := newPostgresTestThrottler(t)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, retryAfter, err := throttler.Attempt("jane", "203.0.113.7")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if retryAfter == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := allowed.Load(); n != 4 {
+		t.Errorf("expected 4 attempts to get through, got %d", n)
+	}
+}
+
+func TestPostgresLoginThrottlerLocksOutAfterFailures(t *testing.T) {
+	throttler, count := newPostgresTestThrottler(t)
+
+	for i := 1; i <= 3; i++ {
+		attempt, retryAfter, err := throttler.Attempt("jane", "203.0.113.7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retryAfter > 0 {
+			t.Fatalf("attempt %d: expected to proceed, got a lockout of %v", i, retryAfter)
+		}
+		if err := throttler.RecordFailure(attempt, loginFailureInvalidCredentials); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7"); retryAfter <= 0 {
+		t.Error("expected the username to be locked out after repeated failures")
+	}
+	if _, retryAfter, _ := throttler.Attempt("john", "203.0.113.7"); retryAfter > 0 {
+		t.Errorf("expected another username to proceed, got a lockout of %v", retryAfter)
+	}
+
+	audited := count("SELECT COUNT(*) FROM failed_admin_logins WHERE username = 'jane' AND reason = $1", loginFailureInvalidCredentials)
+	if audited != 3 {
+		t.Errorf("expected 3 failed logins to be audited, got %d", audited)
+	}
+}
+
+func TestPostgresLoginThrottlerSuccessResetsUsername(t *testing.T) {
+	throttler, count := newPostgresTestThrottler(t)
+
+	for i := 0; i < 2; i++ {
+		attempt, _, _ := throttler.Attempt("jane", "203.0.113.7")
+		if err := throttler.RecordFailure(attempt, loginFailureInvalidCredentials); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	attempt, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7")
+	if retryAfter > 0 {
+		t.Fatalf("expected to proceed, got a lockout of %v", retryAfter)
+	}
+	if err := throttler.RecordSuccess(attempt); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := count("SELECT COUNT(*) FROM login_throttles WHERE throttle_key = $1", usernameThrottleKey("jane")); n != 0 {
+		t.Errorf("expected the failures of the username to be cleared, got %d row(s)", n)
+	}
+	// The failures of the IP are kept, only the successful attempt stops counting
+	if n := count("SELECT failures FROM login_throttles WHERE throttle_key = $1", ipThrottleKey("203.0.113.7")); n != 2 {
+		t.Errorf("expected the IP to keep 2 failures, got %d", n)
+	}
+
+	for i := 1; i <= 4; i++ {
+		if _, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7"); retryAfter > 0 {
+			t.Fatalf("attempt %d: expected to proceed after a successful login, got a lockout of %v", i, retryAfter)
+		}
+	}
+}
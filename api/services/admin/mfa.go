@@ -0,0 +1,273 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+const recoveryCodeCount = 10
+
+// @Summary Completes an admin login with MFA
+// @Description Exchanges the "mfaToken" from `/admins/login` and a TOTP code (or a recovery code) for an access token and a refresh token.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Param Body body types.LoginAdminMFARequest true "MFA token and code"
+// @Success 200 {object} types.LoginAdminResponse
+// @Router /admins/login/mfa [post]
+func (h *Handler) handleAdminLoginMFA(w http.ResponseWriter, r *http.Request) {
+	var payload types.LoginAdminMFARequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	mfaToken, err := auth.ValidateUserToken(payload.MFAToken, auth.ScopeMFAPending)
+	if err != nil {
+		log.Printf("unable to validate MFA token: %v", err)
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("MFA token is invalid or has expired, please log in again"))
+		return
+	}
+
+	admin, err := h.store.GetAdminByID(mfaToken.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if admin == nil || !admin.MFAEnabled || !admin.IsActive {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("MFA token is invalid or has expired, please log in again"))
+		return
+	}
+
+	ipAddress := utils.GetClientIP(r)
+	attempt := h.checkLoginThrottle(w, admin.Username, ipAddress)
+	if attempt == nil {
+		return
+	}
+
+	ok, err := h.verifyMFACode(*admin, payload.Code)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		h.recordLoginFailure(attempt, loginFailureInvalidMFACode)
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("invalid MFA code"))
+		return
+	}
+
+	h.recordLoginSuccess(attempt)
+	h.writeLoginResponse(w, *admin)
+}
+
+// @Summary Starts MFA enrollment
+// @Description Generates a new TOTP secret for the logged in admin. Add it to an authenticator app (e.g. by rendering the provisioning URI as a QR code), then confirm it at `/admins/me/mfa/confirm`.
+// @Tags Admins
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} types.MFAEnrollmentResponse
+// @Router /admins/me/mfa/enroll [post]
+func (h *Handler) handleEnrollMFA(w http.ResponseWriter, r *http.Request) {
+	admin, ok := h.getCurrentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if admin.MFAEnabled {
+		utils.WriteError(w, http.StatusConflict, errors.New("MFA is already enabled, disable it first to enroll a new device"))
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.store.SetMFASecret(admin.ID, secret); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(config.Envs.MFAIssuer, admin.Username, secret),
+	})
+}
+
+// @Summary Confirms MFA enrollment
+// @Description Enables MFA once a valid TOTP code for the enrolled secret is provided. Returns one-time recovery codes, which are only shown once.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Body body types.MFACodeRequest true "TOTP code"
+// @Success 200 {object} types.MFARecoveryCodesResponse
+// @Router /admins/me/mfa/confirm [post]
+func (h *Handler) handleConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	var payload types.MFACodeRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := h.getCurrentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if admin.MFAEnabled {
+		utils.WriteError(w, http.StatusConflict, errors.New("MFA is already enabled"))
+		return
+	}
+	if admin.MFASecret == nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("MFA enrollment has not been started"))
+		return
+	}
+
+	valid, err := h.verifyTOTPCode(*admin, payload.Code)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !valid {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid MFA code"))
+		return
+	}
+
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+
+	if err := h.store.EnableMFA(admin.ID, hashes); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// @Summary Disables MFA
+// @Description Disables MFA for the logged in admin. Requires a valid TOTP code or recovery code.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Body body types.MFACodeRequest true "TOTP code or recovery code"
+// @Success 200 {object} types.Message
+// @Router /admins/me/mfa [delete]
+func (h *Handler) handleDisableMFA(w http.ResponseWriter, r *http.Request) {
+	var payload types.MFACodeRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	admin, ok := h.getCurrentAdmin(w, r)
+	if !ok {
+		return
+	}
+
+	if !admin.MFAEnabled {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("MFA is not enabled"))
+		return
+	}
+
+	valid, err := h.verifyMFACode(*admin, payload.Code)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !valid {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid MFA code"))
+		return
+	}
+
+	if err := h.store.DisableMFA(admin.ID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "MFA disabled successfully"})
+}
+
+// getCurrentAdmin returns the logged in admin, writing an error response if they can not be found.
+func (h *Handler) getCurrentAdmin(w http.ResponseWriter, r *http.Request) (*types.Admin, bool) {
+	adminID := middleware.GetUserIDFromContext(r.Context())
+	admin, err := h.store.GetAdminByID(adminID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if admin == nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("admin with id %v does not exist", adminID))
+		return nil, false
+	}
+	return admin, true
+}
+
+// verifyMFACode accepts either a TOTP code or an unused recovery code.
+func (h *Handler) verifyMFACode(admin types.Admin, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return h.verifyTOTPCode(admin, code)
+	}
+	return h.store.UseRecoveryCode(admin.ID, auth.HashRecoveryCode(code))
+}
+
+// verifyTOTPCode validates a TOTP code against the admin's secret. Each code can only be used once.
+func (h *Handler) verifyTOTPCode(admin types.Admin, code string) (bool, error) {
+	if admin.MFASecret == nil {
+		return false, nil
+	}
+
+	step, valid := auth.ValidateTOTPCode(*admin.MFASecret, code, time.Now())
+	if !valid {
+		return false, nil
+	}
+	return h.store.UseMFAStep(admin.ID, step)
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, ch := range code {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -4,10 +4,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 
+	"github.com/lib/pq"
 	"github.com/sockify/sockify/types"
 )
 
+// adminColumns are the columns read by `scanRowIntoAdmin`, in order.
+const adminColumns = "admin_id, firstname, lastname, email, username, password_hash, role, mfa_enabled, mfa_secret, is_active, low_stock_alerts, created_at"
+
 type Store struct {
 	db *sql.DB
 }
@@ -24,7 +29,7 @@ func (s *Store) GetAdmins(limit int, offset int) ([]types.Admin, int, error) {
 	}
 
 	rows, err := s.db.Query(`
-    SELECT * FROM admins
+    SELECT `+adminColumns+` FROM admins
     ORDER BY firstname, lastname, username, email ASC
     LIMIT $1
     OFFSET $2
@@ -35,7 +40,7 @@ func (s *Store) GetAdmins(limit int, offset int) ([]types.Admin, int, error) {
 
 	admins := make([]types.Admin, 0)
 	for rows.Next() {
-		admin, err := scanRowsIntoAdmin(rows)
+		admin, err := scanRowIntoAdmin(rows)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -47,17 +52,7 @@ func (s *Store) GetAdmins(limit int, offset int) ([]types.Admin, int, error) {
 }
 
 func (s *Store) GetAdminByID(id int) (*types.Admin, error) {
-	admin := &types.Admin{}
-	err := s.db.QueryRow("SELECT * FROM admins WHERE admin_id = $1", id).Scan(
-		&admin.ID,
-		&admin.FirstName,
-		&admin.LastName,
-		&admin.Email,
-		&admin.Username,
-		&admin.PasswordHash,
-		&admin.CreatedAt,
-	)
-
+	admin, err := scanRowIntoAdmin(s.db.QueryRow("SELECT "+adminColumns+" FROM admins WHERE admin_id = $1", id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -69,15 +64,16 @@ func (s *Store) GetAdminByID(id int) (*types.Admin, error) {
 }
 
 func (s *Store) GetAdminByUsername(username string) (*types.Admin, error) {
-	rows, err := s.db.Query("SELECT * FROM admins WHERE username = $1", username)
+	rows, err := s.db.Query("SELECT "+adminColumns+" FROM admins WHERE username = $1", username)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	admin := &types.Admin{}
 	found := false
 	for rows.Next() {
-		admin, err = scanRowsIntoAdmin(rows)
+		admin, err = scanRowIntoAdmin(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -92,15 +88,16 @@ func (s *Store) GetAdminByUsername(username string) (*types.Admin, error) {
 }
 
 func (s *Store) GetAdminByEmail(email string) (*types.Admin, error) {
-	rows, err := s.db.Query("SELECT * FROM admins WHERE email = $1", email)
+	rows, err := s.db.Query("SELECT "+adminColumns+" FROM admins WHERE email = $1", email)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	admin := &types.Admin{}
 	found := false
 	for rows.Next() {
-		admin, err = scanRowsIntoAdmin(rows)
+		admin, err = scanRowIntoAdmin(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -114,13 +111,14 @@ func (s *Store) GetAdminByEmail(email string) (*types.Admin, error) {
 	return admin, nil
 }
 
-func (s *Store) CreateAdmin(firstname string, lastname string, email string, username string, passwordHash string) error {
-	_, err := s.db.Exec("INSERT INTO admins (firstname, lastname, email, username, password_hash) VALUES ($1, $2, $3, $4, $5)",
+func (s *Store) CreateAdmin(firstname string, lastname string, email string, username string, passwordHash string, role types.AdminRole) error {
+	_, err := s.db.Exec("INSERT INTO admins (firstname, lastname, email, username, password_hash, role) VALUES ($1, $2, $3, $4, $5, $6)",
 		firstname,
 		lastname,
 		email,
 		username,
 		passwordHash,
+		role,
 	)
 	if err != nil {
 		return err
@@ -129,15 +127,356 @@ func (s *Store) CreateAdmin(firstname string, lastname string, email string, use
 	return nil
 }
 
-func scanRowsIntoAdmin(rows *sql.Rows) (*types.Admin, error) {
+// UpdateAdminRole changes the role of an admin. Returns `types.ErrLastOwner` when demoting the last active owner.
+func (s *Store) UpdateAdminRole(adminID int, role types.AdminRole) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if role != types.RoleOwner {
+		if err = ensureNotLastOwnerTx(tx, adminID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE admins SET role = $1 WHERE admin_id = $2", role, adminID)
+	if err != nil {
+		log.Printf("Error updating role for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) UpdateAdmin(adminID int, firstname string, lastname string, email string) error {
+	_, err := s.db.Exec(`
+    UPDATE admins
+    SET firstname = $1, lastname = $2, email = $3
+    WHERE admin_id = $4
+  `, firstname, lastname, email, adminID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return types.ErrAdminEmailAlreadyExists
+		}
+		log.Printf("Error updating admin ID %v: %v", adminID, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateAdminPassword changes the password of an admin and signs them out of their other sessions.
+func (s *Store) UpdateAdminPassword(adminID int, passwordHash string) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = updatePasswordTx(tx, adminID, passwordHash); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// SetAdminActive activates or deactivates an admin. Deactivated admins can't log in and their refresh tokens are revoked.
+// Returns `types.ErrLastOwner` when deactivating the last active owner.
+func (s *Store) SetAdminActive(adminID int, active bool) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if !active {
+		if err = ensureNotLastOwnerTx(tx, adminID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE admins SET is_active = $1 WHERE admin_id = $2", active, adminID)
+	if err != nil {
+		log.Printf("Error setting is_active to %v for admin ID %v: %v", active, adminID, err)
+		return err
+	}
+
+	if !active {
+		if err = revokeAllRefreshTokensTx(tx, adminID); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) SetLowStockAlerts(adminID int, enabled bool) error {
+	_, err := s.db.Exec("UPDATE admins SET low_stock_alerts = $1 WHERE admin_id = $2", enabled, adminID)
+	if err != nil {
+		log.Printf("Error setting low_stock_alerts to %v for admin ID %v: %v", enabled, adminID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) GetLowStockAlertSubscribers() ([]types.Admin, error) {
+	rows, err := s.db.Query("SELECT " + adminColumns + " FROM admins WHERE low_stock_alerts = true AND is_active = true ORDER BY admin_id")
+	if err != nil {
+		log.Printf("Error fetching low-stock alert subscribers: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := make([]types.Admin, 0)
+	for rows.Next() {
+		admin, err := scanRowIntoAdmin(rows)
+		if err != nil {
+			return nil, err
+		}
+		admins = append(admins, *admin)
+	}
+
+	return admins, rows.Err()
+}
+
+// ensureNotLastOwnerTx returns `types.ErrLastOwner` if the admin is the only active owner. The active owners are locked
+// until the transaction ends, so concurrent demotions or deactivations can never remove every owner.
+func ensureNotLastOwnerTx(tx *sql.Tx, adminID int) error {
+	rows, err := tx.Query("SELECT admin_id FROM admins WHERE role = $1 AND is_active = true FOR UPDATE", types.RoleOwner)
+	if err != nil {
+		log.Printf("Error locking the active owners: %v", err)
+		return err
+	}
+	defer rows.Close()
+
+	isOwner, owners := false, 0
+	for rows.Next() {
+		var ownerID int
+		if err := rows.Scan(&ownerID); err != nil {
+			return err
+		}
+		isOwner = isOwner || ownerID == adminID
+		owners++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if isOwner && owners <= 1 {
+		return types.ErrLastOwner
+	}
+	return nil
+}
+
+// SetMFASecret stores a new TOTP secret for an admin enrolling in MFA. MFA is only enabled once the secret is confirmed.
+func (s *Store) SetMFASecret(adminID int, secret string) error {
+	_, err := s.db.Exec(`
+    UPDATE admins
+    SET mfa_secret = $1, mfa_last_used_step = NULL
+    WHERE admin_id = $2 AND mfa_enabled = false
+  `, secret, adminID)
+	if err != nil {
+		log.Printf("Error setting MFA secret for admin ID %v: %v", adminID, err)
+		return err
+	}
+	return nil
+}
+
+// EnableMFA turns on MFA for an admin and replaces their recovery codes.
+func (s *Store) EnableMFA(adminID int, recoveryCodeHashes []string) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec("UPDATE admins SET mfa_enabled = true WHERE admin_id = $1", adminID)
+	if err != nil {
+		log.Printf("Error enabling MFA for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM admin_recovery_codes WHERE admin_id = $1", adminID)
+	if err != nil {
+		log.Printf("Error deleting recovery codes for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err = tx.Exec("INSERT INTO admin_recovery_codes (admin_id, code_hash) VALUES ($1, $2)", adminID, hash)
+		if err != nil {
+			log.Printf("Error inserting recovery code for admin ID %v: %v", adminID, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// DisableMFA turns off MFA for an admin, removing their secret and recovery codes.
+func (s *Store) DisableMFA(adminID int) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`
+    UPDATE admins
+    SET mfa_enabled = false, mfa_secret = NULL, mfa_last_used_step = NULL
+    WHERE admin_id = $1
+  `, adminID)
+	if err != nil {
+		log.Printf("Error disabling MFA for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM admin_recovery_codes WHERE admin_id = $1", adminID)
+	if err != nil {
+		log.Printf("Error deleting recovery codes for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// UseMFAStep records the TOTP time step of an accepted code. Returns false if a code from the same (or a later) step was already used, which prevents replays.
+func (s *Store) UseMFAStep(adminID int, step int64) (accepted bool, err error) {
+	res, err := s.db.Exec(`
+    UPDATE admins
+    SET mfa_last_used_step = $1
+    WHERE admin_id = $2 AND (mfa_last_used_step IS NULL OR mfa_last_used_step < $1)
+  `, step, adminID)
+	if err != nil {
+		log.Printf("Error recording MFA step for admin ID %v: %v", adminID, err)
+		return false, err
+	}
+
+	val, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return val > 0, nil
+}
+
+// UseRecoveryCode consumes an unused recovery code. Returns false if the code does not exist or was already used.
+func (s *Store) UseRecoveryCode(adminID int, codeHash string) (accepted bool, err error) {
+	res, err := s.db.Exec(`
+    UPDATE admin_recovery_codes
+    SET used_at = CURRENT_TIMESTAMP
+    WHERE admin_id = $1 AND code_hash = $2 AND used_at IS NULL
+  `, adminID, codeHash)
+	if err != nil {
+		log.Printf("Error using recovery code for admin ID %v: %v", adminID, err)
+		return false, err
+	}
+
+	val, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return val > 0, nil
+}
+
+func updatePasswordTx(tx *sql.Tx, adminID int, passwordHash string) error {
+	_, err := tx.Exec("UPDATE admins SET password_hash = $1 WHERE admin_id = $2", passwordHash, adminID)
+	if err != nil {
+		log.Printf("Error updating password for admin ID %v: %v", adminID, err)
+		return err
+	}
+	return revokeAllRefreshTokensTx(tx, adminID)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRowIntoAdmin(row rowScanner) (*types.Admin, error) {
 	admin := &types.Admin{}
-	err := rows.Scan(
+	err := row.Scan(
 		&admin.ID,
 		&admin.FirstName,
 		&admin.LastName,
 		&admin.Email,
 		&admin.Username,
 		&admin.PasswordHash,
+		&admin.Role,
+		&admin.MFAEnabled,
+		&admin.MFASecret,
+		&admin.IsActive,
+		&admin.LowStockAlerts,
 		&admin.CreatedAt,
 	)
 	if err != nil {
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/sockify/sockify/database/dbtest"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+// newMFATestAdmin creates an admin with MFA enabled and the given recovery codes.
+func newMFATestAdmin(t *testing.T, recoveryCodes ...string) (types.AdminStore, int) {
+	store := NewStore(dbtest.New(t))
+	if err := store.CreateAdmin("Jane", "Doe", "jane@example.com", "jane", "hash", types.RoleOwner); err != nil {
+		t.Fatalf("unable to create admin: %v", err)
+	}
+	admin, err := store.GetAdminByUsername("jane")
+	if err != nil || admin == nil {
+		t.Fatalf("unable to get admin: %v", err)
+	}
+
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+	if err := store.SetMFASecret(admin.ID, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnableMFA(admin.ID, hashes); err != nil {
+		t.Fatal(err)
+	}
+	return store, admin.ID
+}
+
+func TestUseMFAStepRejectsReplays(t *testing.T) {
+	store, adminID := newMFATestAdmin(t)
+
+	steps := []struct {
+		step     int64
+		accepted bool
+	}{
+		{100, true},
+		// The same code can not be used twice, nor an older one that is still within the allowed drift
+		{100, false},
+		{99, false},
+		{101, true},
+	}
+	for _, s := range steps {
+		accepted, err := store.UseMFAStep(adminID, s.step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted != s.accepted {
+			t.Errorf("step %d: expected accepted to be %v, got %v", s.step, s.accepted, accepted)
+		}
+	}
+}
+
+func TestRecoveryCodeCanOnlyBeUsedOnce(t *testing.T) {
+	store, adminID := newMFATestAdmin(t, "abcde-12345", "fghij-67890")
+
+	for i, expected := range []bool{true, false} {
+		accepted, err := store.UseRecoveryCode(adminID, auth.HashRecoveryCode("abcde-12345"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted != expected {
+			t.Errorf("use %d: expected accepted to be %v, got %v", i+1, expected, accepted)
+		}
+	}
+
+	if accepted, _ := store.UseRecoveryCode(adminID, auth.HashRecoveryCode("fghij-67890")); !accepted {
+		t.Error("expected the other recovery code to still be usable")
+	}
+	if accepted, _ := store.UseRecoveryCode(adminID, auth.HashRecoveryCode("zzzzz-00000")); accepted {
+		t.Error("expected an unknown recovery code to be rejected")
+	}
+}
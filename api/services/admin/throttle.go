@@ -0,0 +1,208 @@
+package admin
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// Reasons recorded in the failed login audit trail.
+const (
+	loginFailureInvalidCredentials = "invalid_credentials"
+	loginFailureInvalidMFACode     = "invalid_mfa_code"
+	loginFailureDeactivated        = "deactivated"
+	loginFailureThrottled          = "throttled"
+)
+
+const baseLoginBackoff = time.Second
+
+// LoginThrottler slows down password guessing by tracking failed logins per username and per client IP.
+// The first half of the allowed failures have no delay, to forgive typos. Every failure after that doubles the wait
+// before the next attempt, and reaching the maximum locks the key out.
+type LoginThrottler struct {
+	store                  types.LoginThrottleStore
+	maxFailuresPerUsername int
+	maxFailuresPerIP       int
+	lockout                time.Duration
+}
+
+func NewLoginThrottler(store types.LoginThrottleStore, maxFailuresPerUsername int, maxFailuresPerIP int, lockout time.Duration) *LoginThrottler {
+	return &LoginThrottler{
+		store:                  store,
+		maxFailuresPerUsername: maxFailuresPerUsername,
+		maxFailuresPerIP:       maxFailuresPerIP,
+		lockout:                lockout,
+	}
+}
+
+// LoginAttempt is a login counted by `Attempt`, before the credentials are verified.
+type LoginAttempt struct {
+	username  string
+	ipAddress string
+	keys      []throttledKey
+}
+
+type throttledKey struct {
+	key         string
+	maxFailures int
+	// Attempts counted for the key so far, including this one
+	attempts int
+}
+
+// Attempt atomically counts a login attempt for the username and IP before the credentials are verified, so concurrent
+// attempts can never get past the limits. Returns how long to wait when the attempt is rejected, 0 if it can proceed.
+func (t *LoginThrottler) Attempt(username string, ipAddress string) (*LoginAttempt, time.Duration, error) {
+	attempt := &LoginAttempt{
+		username:  username,
+		ipAddress: ipAddress,
+		keys: []throttledKey{
+			{key: usernameThrottleKey(username), maxFailures: t.maxFailuresPerUsername},
+			{key: ipThrottleKey(ipAddress), maxFailures: t.maxFailuresPerIP},
+		},
+	}
+
+	var retryAfter time.Duration
+	for i := range attempt.keys {
+		k := &attempt.keys[i]
+		attempts, lockedUntil, err := t.store.RecordLoginAttempt(k.key, t.lockout)
+		if err != nil {
+			return nil, 0, err
+		}
+		k.attempts = attempts
+
+		if lockedUntil != nil {
+			retryAfter = max(retryAfter, time.Until(*lockedUntil))
+		} else if attempts > k.maxFailures {
+			// Concurrent attempts got past the limit before the lockout was set
+			retryAfter = max(retryAfter, t.lockout)
+		}
+	}
+	return attempt, retryAfter, nil
+}
+
+// RecordFailure records a failed login attempt, and locks the username and IP out for the next attempt if needed.
+func (t *LoginThrottler) RecordFailure(attempt *LoginAttempt, reason string) error {
+	if err := t.store.RecordFailedAdminLogin(attempt.username, attempt.ipAddress, reason); err != nil {
+		return err
+	}
+
+	for _, k := range attempt.keys {
+		if err := t.lockAfterFailure(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordRejected audits a login that was rejected without counting it as a failure, e.g. because of a lockout.
+func (t *LoginThrottler) RecordRejected(username string, ipAddress string, reason string) error {
+	return t.store.RecordFailedAdminLogin(username, ipAddress, reason)
+}
+
+// RecordSuccess forgets the failures of the username, and stops counting the attempt against the IP. Earlier failures
+// of the IP are kept, so one valid account can't be used to reset them.
+func (t *LoginThrottler) RecordSuccess(attempt *LoginAttempt) error {
+	if err := t.Unlock(attempt.username); err != nil {
+		return err
+	}
+	return t.store.ReleaseLoginAttempt(ipThrottleKey(attempt.ipAddress))
+}
+
+// Release stops counting the attempt against the username and IP, without forgetting their earlier failures. Used
+// once the password is verified but the login still has to be completed with MFA, which counts its own attempt.
+func (t *LoginThrottler) Release(attempt *LoginAttempt) error {
+	for _, k := range attempt.keys {
+		if err := t.store.ReleaseLoginAttempt(k.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unlock forgets the failures and lifts the lockout of the username.
+func (t *LoginThrottler) Unlock(username string) error {
+	return t.store.ClearLoginFailures(usernameThrottleKey(username))
+}
+
+func (t *LoginThrottler) lockAfterFailure(k throttledKey) error {
+	delay := t.backoff(k.attempts, k.maxFailures)
+	if delay == 0 {
+		return nil
+	}
+
+	if k.attempts >= k.maxFailures {
+		log.Printf("[WARN] %v failed logins for %v, locking it out for %v", k.attempts, k.key, delay)
+	}
+	return t.store.LockLogin(k.key, time.Now().Add(delay))
+}
+
+// backoff returns how long the key has to wait after its latest failure.
+func (t *LoginThrottler) backoff(failures int, maxFailures int) time.Duration {
+	if failures >= maxFailures {
+		return t.lockout
+	}
+	freeFailures := maxFailures / 2
+	if failures <= freeFailures {
+		return 0
+	}
+
+	delay := baseLoginBackoff
+	for i := freeFailures + 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.lockout {
+			return t.lockout
+		}
+	}
+	return delay
+}
+
+// checkLoginThrottle counts the login attempt and writes a 429 response if the username or IP is locked out.
+// Returns nil if the login can not proceed.
+func (h *Handler) checkLoginThrottle(w http.ResponseWriter, username string, ipAddress string) *LoginAttempt {
+	attempt, retryAfter, err := h.throttler.Attempt(username, ipAddress)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return nil
+	}
+	if retryAfter <= 0 {
+		return attempt
+	}
+
+	if err := h.throttler.RecordRejected(username, ipAddress, loginFailureThrottled); err != nil {
+		log.Printf("Unable to audit throttled login for username %v: %v", username, err)
+	}
+	utils.WriteTooManyRequests(w, retryAfter)
+	return nil
+}
+
+// recordLoginFailure records a failed login. Errors are only logged, the login has failed either way.
+func (h *Handler) recordLoginFailure(attempt *LoginAttempt, reason string) {
+	if err := h.throttler.RecordFailure(attempt, reason); err != nil {
+		log.Printf("Unable to record failed login for username %v: %v", attempt.username, err)
+	}
+}
+
+// recordLoginSuccess forgets the failed logins of the username.
+func (h *Handler) recordLoginSuccess(attempt *LoginAttempt) {
+	if err := h.throttler.RecordSuccess(attempt); err != nil {
+		log.Printf("Unable to clear failed logins for username %v: %v", attempt.username, err)
+	}
+}
+
+// releaseLoginAttempt stops counting a login attempt that is continued at the MFA step.
+func (h *Handler) releaseLoginAttempt(attempt *LoginAttempt) {
+	if err := h.throttler.Release(attempt); err != nil {
+		log.Printf("Unable to release login attempt for username %v: %v", attempt.username, err)
+	}
+}
+
+func usernameThrottleKey(username string) string {
+	return "username:" + username
+}
+
+func ipThrottleKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestThrottler() *LoginThrottler {
+	return NewLoginThrottler(NewMemoryLoginThrottleStore(), 4, 100, time.Minute)
+}
+
+func TestLoginThrottlerLimitsConcurrentAttempts(t *testing.T) {
+	throttler := newTestThrottler()
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, retryAfter, err := throttler.Attempt("jane", "203.0.113.7")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if retryAfter == 0 {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The credentials of every attempt are still being verified, none of them failed yet
+	if n := allowed.Load(); n != 4 {
+		t.Errorf("expected 4 attempts to get through, got %d", n)
+	}
+}
+
+func TestLoginThrottlerLocksOutAfterFailures(t *testing.T) {
+	throttler := newTestThrottler()
+
+	// Half of the failures are free, every failure after that locks the username out for a while
+	for i := 1; i <= 3; i++ {
+		attempt, retryAfter, err := throttler.Attempt("jane", "203.0.113.7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retryAfter > 0 {
+			t.Fatalf("attempt %d: expected to proceed, got a lockout of %v", i, retryAfter)
+		}
+		if err := throttler.RecordFailure(attempt, loginFailureInvalidCredentials); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7"); retryAfter <= 0 {
+		t.Error("expected the username to be locked out after repeated failures")
+	}
+	if _, retryAfter, _ := throttler.Attempt("john", "203.0.113.7"); retryAfter > 0 {
+		t.Errorf("expected another username to proceed, got a lockout of %v", retryAfter)
+	}
+}
+
+func TestLoginThrottlerSuccessResetsUsername(t *testing.T) {
+	throttler := newTestThrottler()
+
+	for i := 0; i < 2; i++ {
+		attempt, _, _ := throttler.Attempt("jane", "203.0.113.7")
+		if err := throttler.RecordFailure(attempt, loginFailureInvalidCredentials); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	attempt, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7")
+	if retryAfter > 0 {
+		t.Fatalf("expected to proceed, got a lockout of %v", retryAfter)
+	}
+	if err := throttler.RecordSuccess(attempt); err != nil {
+		t.Fatal(err)
+	}
+
+	// The count restarted, so every free attempt is available again
+	for i := 1; i <= 4; i++ {
+		if _, retryAfter, _ := throttler.Attempt("jane", "203.0.113.7"); retryAfter > 0 {
+			t.Fatalf("attempt %d: expected to proceed after a successful login, got a lockout of %v", i, retryAfter)
+		}
+	}
+}
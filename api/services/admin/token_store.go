@@ -0,0 +1,258 @@
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/types"
+)
+
+func (s *Store) CreateRefreshToken(adminID int, tokenHash string, expiresAt time.Time) error {
+	_, err := s.db.Exec(`
+    INSERT INTO admin_refresh_tokens (admin_id, token_hash, expires_at)
+    VALUES ($1, $2, $3)
+  `, adminID, tokenHash, expiresAt.UTC())
+	if err != nil {
+		log.Printf("Error creating refresh token for admin ID %v: %v", adminID, err)
+		return err
+	}
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token by its hash, including revoked and expired ones.
+func (s *Store) GetRefreshToken(tokenHash string) (*types.AdminRefreshToken, error) {
+	token := &types.AdminRefreshToken{}
+	err := s.db.QueryRow(`
+    SELECT admin_refresh_token_id, admin_id, expires_at, revoked_at, created_at
+    FROM admin_refresh_tokens
+    WHERE token_hash = $1
+  `, tokenHash).Scan(&token.ID, &token.AdminID, &token.ExpiresAt, &token.RevokedAt, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching refresh token: %v", err)
+		return nil, err
+	}
+	return token, nil
+}
+
+// RotateRefreshToken revokes a refresh token and stores its replacement in a single transaction.
+// Returns false if the old token was already revoked, e.g. by a concurrent refresh.
+func (s *Store) RotateRefreshToken(oldTokenID int, adminID int, newTokenHash string, expiresAt time.Time) (rotated bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return false, err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil || !rotated {
+			tx.Rollback()
+		}
+	}()
+
+	res, err := tx.Exec(`
+    UPDATE admin_refresh_tokens
+    SET revoked_at = CURRENT_TIMESTAMP
+    WHERE admin_refresh_token_id = $1 AND revoked_at IS NULL
+  `, oldTokenID)
+	if err != nil {
+		log.Printf("Error revoking refresh token ID %v: %v", oldTokenID, err)
+		return false, err
+	}
+
+	val, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if val == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(`
+    INSERT INTO admin_refresh_tokens (admin_id, token_hash, expires_at)
+    VALUES ($1, $2, $3)
+  `, adminID, newTokenHash, expiresAt.UTC())
+	if err != nil {
+		log.Printf("Error creating refresh token for admin ID %v: %v", adminID, err)
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *Store) RevokeRefreshToken(tokenHash string) error {
+	_, err := s.db.Exec(`
+    UPDATE admin_refresh_tokens
+    SET revoked_at = CURRENT_TIMESTAMP
+    WHERE token_hash = $1 AND revoked_at IS NULL
+  `, tokenHash)
+	if err != nil {
+		log.Printf("Error revoking refresh token: %v", err)
+		return err
+	}
+	return nil
+}
+
+// RevokeAllRefreshTokens signs an admin out of every session once their access tokens expire.
+func (s *Store) RevokeAllRefreshTokens(adminID int) error {
+	_, err := s.db.Exec(revokeAllRefreshTokensQuery, adminID)
+	if err != nil {
+		log.Printf("Error revoking refresh tokens for admin ID %v: %v", adminID, err)
+		return err
+	}
+	return nil
+}
+
+const revokeAllRefreshTokensQuery = `
+    UPDATE admin_refresh_tokens
+    SET revoked_at = CURRENT_TIMESTAMP
+    WHERE admin_id = $1 AND revoked_at IS NULL
+  `
+
+func revokeAllRefreshTokensTx(tx *sql.Tx, adminID int) error {
+	_, err := tx.Exec(revokeAllRefreshTokensQuery, adminID)
+	if err != nil {
+		log.Printf("Error revoking refresh tokens for admin ID %v: %v", adminID, err)
+		return err
+	}
+	return nil
+}
+
+// RevokeTokenID revokes an access token by its `jti` until it expires. Entries for tokens that already expired are cleaned up along the way.
+func (s *Store) RevokeTokenID(jti string, expiresAt time.Time) error {
+	_, err := s.db.Exec(`
+    INSERT INTO revoked_tokens (jti, expires_at)
+    VALUES ($1, $2)
+    ON CONFLICT (jti) DO NOTHING
+  `, jti, expiresAt.UTC())
+	if err != nil {
+		log.Printf("Error revoking token ID %v: %v", jti, err)
+		return err
+	}
+
+	if _, err := s.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < CURRENT_TIMESTAMP"); err != nil {
+		log.Printf("Error cleaning up expired revoked tokens: %v", err)
+	}
+	return nil
+}
+
+func (s *Store) IsTokenIDRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := s.db.QueryRow("SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti).Scan(&revoked)
+	if err != nil {
+		log.Printf("Error checking if token ID %v is revoked: %v", jti, err)
+		return false, err
+	}
+	return revoked, nil
+}
+
+// CreatePasswordResetToken stores a password reset token and queues the email containing it in the same transaction.
+// Tokens issued earlier for the admin are invalidated.
+func (s *Store) CreatePasswordResetToken(adminID int, tokenHash string, expiresAt time.Time, resetEmail types.OutboundEmail) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`
+    UPDATE admin_password_reset_tokens
+    SET used_at = CURRENT_TIMESTAMP
+    WHERE admin_id = $1 AND used_at IS NULL
+  `, adminID)
+	if err != nil {
+		log.Printf("Error invalidating password reset tokens for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	_, err = tx.Exec(`
+    INSERT INTO admin_password_reset_tokens (admin_id, token_hash, expires_at)
+    VALUES ($1, $2, $3)
+  `, adminID, tokenHash, expiresAt.UTC())
+	if err != nil {
+		log.Printf("Error creating password reset token for admin ID %v: %v", adminID, err)
+		return err
+	}
+
+	if err = email.EnqueueTx(tx, resetEmail); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ResetPasswordWithToken uses a password reset token to set a new password. Returns false if the token is unknown, used or expired,
+// or if the admin has been deactivated.
+func (s *Store) ResetPasswordWithToken(tokenHash string, passwordHash string) (adminID int, ok bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return 0, false, err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil || !ok {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(`
+    UPDATE admin_password_reset_tokens t
+    SET used_at = CURRENT_TIMESTAMP
+    FROM admins a
+    WHERE t.admin_id = a.admin_id
+      AND t.token_hash = $1
+      AND t.used_at IS NULL
+      AND t.expires_at > CURRENT_TIMESTAMP
+      AND a.is_active = true
+    RETURNING t.admin_id
+  `, tokenHash).Scan(&adminID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		log.Printf("Error using password reset token: %v", err)
+		return 0, false, err
+	}
+
+	if err = updatePasswordTx(tx, adminID, passwordHash); err != nil {
+		return 0, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return 0, false, err
+	}
+	return adminID, true, nil
+}
@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+var errInvalidRefreshToken = errors.New("refresh token is invalid or has expired, please log in again")
+
+// @Summary Refreshes an admin access token
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Refresh tokens can only be used once; reusing one revokes every refresh token of the admin.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Param Body body types.RefreshAdminTokenRequest true "Refresh token"
+// @Success 200 {object} types.LoginAdminResponse
+// @Router /admins/refresh [post]
+func (h *Handler) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	var payload types.RefreshAdminTokenRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	stored, err := h.store.GetRefreshToken(auth.HashOpaqueToken(payload.RefreshToken))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if stored == nil || time.Now().After(stored.ExpiresAt) {
+		utils.WriteError(w, http.StatusUnauthorized, errInvalidRefreshToken)
+		return
+	}
+
+	// A revoked token being used again means it was most likely stolen, so sign the admin out everywhere
+	if stored.RevokedAt != nil {
+		log.Printf("[WARN] revoked refresh token ID %v was reused, revoking all refresh tokens of admin ID %v", stored.ID, stored.AdminID)
+		if err := h.store.RevokeAllRefreshTokens(stored.AdminID); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+		utils.WriteError(w, http.StatusUnauthorized, errInvalidRefreshToken)
+		return
+	}
+
+	admin, err := h.store.GetAdminByID(stored.AdminID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if admin == nil || !admin.IsActive {
+		utils.WriteError(w, http.StatusUnauthorized, errInvalidRefreshToken)
+		return
+	}
+
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	rotated, err := h.store.RotateRefreshToken(stored.ID, admin.ID, auth.HashOpaqueToken(refreshToken), refreshTokenExpiration())
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !rotated {
+		utils.WriteError(w, http.StatusUnauthorized, errInvalidRefreshToken)
+		return
+	}
+
+	accessToken, err := auth.CreateAdminJWTToken([]byte(config.Envs.JWTSecret), admin.ID, string(admin.Role))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.LoginAdminResponse{Token: accessToken, RefreshToken: refreshToken})
+}
+
+// @Summary Logs out an admin
+// @Description Revokes the access token used to make the request. If a refresh token is given, it is revoked as well.
+// @Tags Admins
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param Body body types.LogoutAdminRequest false "Refresh token to revoke"
+// @Success 200 {object} types.Message
+// @Router /admins/logout [post]
+func (h *Handler) handleLogout(w http.ResponseWriter, r *http.Request) {
+	var payload types.LogoutAdminRequest
+	// The body is optional
+	if err := utils.ParseJson(r, &payload); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if token, ok := middleware.GetTokenFromContext(r.Context()); ok {
+		if err := h.store.RevokeTokenID(token.TokenID, token.ExpiresAt); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	if payload.RefreshToken != "" {
+		tokenHash := auth.HashOpaqueToken(payload.RefreshToken)
+		stored, err := h.store.GetRefreshToken(tokenHash)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		// Don't let an admin revoke somebody else's session
+		if stored != nil && stored.AdminID == middleware.GetUserIDFromContext(r.Context()) {
+			if err := h.store.RevokeRefreshToken(tokenHash); err != nil {
+				utils.WriteError(w, http.StatusInternalServerError, err)
+				return
+			}
+		}
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "logged out successfully"})
+}
+
+// writeLoginResponse issues a new access token and refresh token for the admin.
+func (h *Handler) writeLoginResponse(w http.ResponseWriter, admin types.Admin) {
+	accessToken, err := auth.CreateAdminJWTToken([]byte(config.Envs.JWTSecret), admin.ID, string(admin.Role))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if err := h.store.CreateRefreshToken(admin.ID, auth.HashOpaqueToken(refreshToken), refreshTokenExpiration()); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.LoginAdminResponse{Token: accessToken, RefreshToken: refreshToken})
+}
+
+func refreshTokenExpiration() time.Time {
+	return time.Now().Add(time.Duration(config.Envs.AdminRefreshTokenExpirationInSeconds) * time.Second)
+}
@@ -0,0 +1,303 @@
+package cart
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/database/dbtest"
+	"github.com/sockify/sockify/services/inventory"
+	"github.com/sockify/sockify/services/orders"
+	"github.com/sockify/sockify/services/payments"
+	"github.com/sockify/sockify/types"
+)
+
+// checkoutTest runs checkouts end to end against the database and the fake payment gateway.
+type checkoutTest struct {
+	t          *testing.T
+	db         *sql.DB
+	sockStore  types.SockStore
+	orderStore types.OrderStore
+	gateway    *payments.FakeGateway
+	handler    *CartHandler
+}
+
+func newCheckoutTest(t *testing.T) *checkoutTest {
+	db := dbtest.New(t)
+	sockStore := inventory.NewSockStore(db)
+	orderStore := orders.NewOrderStore(db, sockStore)
+	gateway := payments.NewFakeGateway(testWebhookSecret)
+
+	return &checkoutTest{
+		t:          t,
+		db:         db,
+		sockStore:  sockStore,
+		orderStore: orderStore,
+		gateway:    gateway,
+		handler:    NewCartHandler(sockStore, orderStore, gateway),
+	}
+}
+
+// createVariant adds a sock with a single variant in stock and returns the variant ID.
+func (c *checkoutTest) createVariant(quantity int) int {
+	sockID, err := c.sockStore.CreateSock(
+		types.Sock{Name: "Test sock", Description: "Warm", PreviewImageURL: "https://example.com/sock.png"},
+		[]types.SockVariant{{Options: map[string]string{"size": "M"}, Price: 9.99, Quantity: quantity}},
+		types.SystemAdminID,
+	)
+	if err != nil {
+		c.t.Fatalf("unable to create sock: %v", err)
+	}
+
+	variants, err := c.sockStore.GetSockVariants(sockID)
+	if err != nil || len(variants) != 1 {
+		c.t.Fatalf("unable to get the sock variant: %v", err)
+	}
+	return variants[0].ID
+}
+
+// checkout creates a pending order and returns the status code and the checkout session ID.
+func (c *checkoutTest) checkout(variantID int, quantity int) (int, string) {
+	body, _ := json.Marshal(types.CheckoutOrderRequest{
+		Items:   []types.CheckoutItem{{SockVariantID: variantID, Quantity: quantity}},
+		Address: types.Address{Street: "1 Main St", City: "Miami", State: "FL", Zipcode: "33101"},
+		Contact: types.Contact{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/cart/checkout/stripe-session", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	c.handler.handleCheckoutWithStripe(rr, req)
+	if rr.Code != http.StatusOK {
+		return rr.Code, ""
+	}
+
+	var res types.StripeCheckoutResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+		c.t.Fatalf("unable to parse checkout response: %v", err)
+	}
+	paymentURL, err := url.Parse(res.PaymentURL)
+	if err != nil {
+		c.t.Fatalf("invalid payment URL %q: %v", res.PaymentURL, err)
+	}
+	return rr.Code, paymentURL.Query().Get("session_id")
+}
+
+func (c *checkoutTest) session(sessionID string) *types.CheckoutSessionStatus {
+	s, err := c.gateway.GetSessionStatus(sessionID)
+	if err != nil {
+		c.t.Fatalf("unable to get checkout session: %v", err)
+	}
+	return s
+}
+
+// sendEvent delivers a signed webhook event for the checkout session and returns the status code.
+func (c *checkoutTest) sendEvent(eventID string, eventType string, sessionID string) int {
+	payload, _ := json.Marshal(types.PaymentEvent{ID: eventID, Type: eventType, Session: c.session(sessionID)})
+	return postWebhook(c.handler, payload, payments.SignFakeWebhookPayload(payload, testWebhookSecret)).Code
+}
+
+func (c *checkoutTest) confirm(sessionID string) int {
+	req := httptest.NewRequest(http.MethodGet, "/cart/checkout/stripe-confirmation/"+sessionID, nil)
+	req = mux.SetURLVars(req, map[string]string{"session_id": sessionID})
+	rr := httptest.NewRecorder()
+	c.handler.handleStripeConfirmation(rr, req)
+	return rr.Code
+}
+
+// queuedEmails counts the emails queued in the outbox for the customer with a subject matching the `LIKE` pattern.
+func (c *checkoutTest) queuedEmails(subjectPattern string) int {
+	c.t.Helper()
+	var queued int
+	err := c.db.QueryRow("SELECT COUNT(*) FROM email_outbox WHERE to_email = 'jane@example.com' AND subject LIKE $1", subjectPattern).Scan(&queued)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return queued
+}
+
+func (c *checkoutTest) assertQuantity(variantID int, expected int) {
+	c.t.Helper()
+	v, err := c.sockStore.GetSockVariantByID(variantID)
+	if err != nil || v == nil {
+		c.t.Fatalf("unable to get sock variant %v: %v", variantID, err)
+	}
+	if v.Quantity != expected {
+		c.t.Errorf("expected %d in stock, got %d", expected, v.Quantity)
+	}
+}
+
+func (c *checkoutTest) assertStatus(orderID int, expected string) {
+	c.t.Helper()
+	status, err := c.orderStore.GetOrderStatusByID(orderID)
+	if err != nil {
+		c.t.Fatalf("unable to get order status: %v", err)
+	}
+	if status != expected {
+		c.t.Errorf("expected order status %q, got %q", expected, status)
+	}
+}
+
+func TestCheckoutPaidThroughWebhook(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(5)
+
+	code, sessionID := c.checkout(variantID, 2)
+	if code != http.StatusOK {
+		t.Fatalf("expected checkout to succeed, got status %d", code)
+	}
+	orderID := c.session(sessionID).OrderID
+	c.assertStatus(orderID, "pending")
+	c.assertQuantity(variantID, 3)
+
+	if code := c.sendEvent("evt_paid", types.PaymentEventCheckoutCompleted, sessionID); code != http.StatusOK {
+		t.Fatalf("expected webhook to succeed, got status %d", code)
+	}
+	c.assertStatus(orderID, "received")
+	c.assertQuantity(variantID, 3)
+
+	// The customer lands on the confirmation page after the webhook already completed the order
+	if code := c.confirm(sessionID); code != http.StatusOK {
+		t.Fatalf("expected confirmation to succeed, got status %d", code)
+	}
+	if queued := c.queuedEmails("Order confirmation%"); queued != 1 {
+		t.Errorf("expected 1 confirmation email, got %d", queued)
+	}
+
+	movements, err := c.sockStore.GetInventoryMovements(variantID, 10, 0)
+	if err != nil {
+		t.Fatalf("unable to get inventory movements: %v", err)
+	}
+	if len(movements) != 2 || movements[0].Reason != types.MovementSale || movements[0].Delta != -2 {
+		t.Errorf("expected the sale to be recorded in the ledger, got %+v", movements)
+	}
+}
+
+func TestCheckoutReservesStock(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(3)
+
+	if code, _ := c.checkout(variantID, 3); code != http.StatusOK {
+		t.Fatalf("expected checkout to succeed, got status %d", code)
+	}
+	if code, _ := c.checkout(variantID, 1); code != http.StatusBadRequest {
+		t.Errorf("expected checkout of reserved stock to fail with %d, got %d", http.StatusBadRequest, code)
+	}
+	c.assertQuantity(variantID, 0)
+}
+
+func TestExpiredCheckoutSessionRestocks(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(5)
+
+	_, sessionID := c.checkout(variantID, 5)
+	orderID := c.session(sessionID).OrderID
+	c.assertQuantity(variantID, 0)
+
+	if err := c.gateway.SetSessionStatus(sessionID, types.CheckoutSessionStatusExpired, false); err != nil {
+		t.Fatal(err)
+	}
+	// Duplicate deliveries must not restock twice
+	for i := 0; i < 2; i++ {
+		if code := c.sendEvent("evt_expired", types.PaymentEventCheckoutExpired, sessionID); code != http.StatusOK {
+			t.Fatalf("expected webhook to succeed, got status %d", code)
+		}
+	}
+
+	c.assertStatus(orderID, "canceled")
+	c.assertQuantity(variantID, 5)
+
+	// A late payment must not resurrect the canceled order
+	if err := c.gateway.SetSessionStatus(sessionID, types.CheckoutSessionStatusComplete, true); err != nil {
+		t.Fatal(err)
+	}
+	if code := c.sendEvent("evt_paid", types.PaymentEventCheckoutCompleted, sessionID); code != http.StatusOK {
+		t.Fatalf("expected webhook to succeed, got status %d", code)
+	}
+	c.assertStatus(orderID, "canceled")
+	c.assertQuantity(variantID, 5)
+}
+
+func TestAbandonedCheckoutIsReaped(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(5)
+
+	_, sessionID := c.checkout(variantID, 2)
+	orderID := c.session(sessionID).OrderID
+	c.assertQuantity(variantID, 3)
+
+	if _, err := c.db.Exec("UPDATE orders SET created_at = created_at - INTERVAL '2 hours' WHERE order_id = $1", orderID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A canceled context runs a single pass
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	orders.NewPendingOrderReaper(c.orderStore, time.Minute, 30*time.Minute).Run(ctx)
+
+	c.assertStatus(orderID, "canceled")
+	c.assertQuantity(variantID, 5)
+}
+
+func TestRefundedPaymentEmailsCustomer(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(5)
+
+	_, sessionID := c.checkout(variantID, 2)
+	orderID := c.session(sessionID).OrderID
+	if code := c.sendEvent("evt_paid", types.PaymentEventCheckoutCompleted, sessionID); code != http.StatusOK {
+		t.Fatalf("expected webhook to succeed, got status %d", code)
+	}
+
+	payload, _ := json.Marshal(types.PaymentEvent{
+		ID:               "evt_refunded",
+		Type:             types.PaymentEventRefunded,
+		PaymentReference: c.session(sessionID).PaymentReference,
+		FullyRefunded:    true,
+	})
+	if code := postWebhook(c.handler, payload, payments.SignFakeWebhookPayload(payload, testWebhookSecret)).Code; code != http.StatusOK {
+		t.Fatalf("expected webhook to succeed, got status %d", code)
+	}
+	c.assertStatus(orderID, "canceled")
+
+	if queued := c.queuedEmails("%canceled%"); queued != 1 {
+		t.Errorf("expected the cancellation email to be queued, got %d email(s)", queued)
+	}
+}
+
+func TestCanceledPaidOrderRestocks(t *testing.T) {
+	c := newCheckoutTest(t)
+	variantID := c.createVariant(5)
+
+	_, sessionID := c.checkout(variantID, 2)
+	orderID := c.session(sessionID).OrderID
+	if code := c.sendEvent("evt_paid", types.PaymentEventCheckoutCompleted, sessionID); code != http.StatusOK {
+		t.Fatalf("expected webhook to succeed, got status %d", code)
+	}
+
+	canceled, err := c.orderStore.CancelOrder(orderID, types.SystemAdminID, "received", "Canceled at the customer's request.")
+	if err != nil || !canceled {
+		t.Fatalf("expected the order to be canceled, got %v: %v", canceled, err)
+	}
+	c.assertStatus(orderID, "canceled")
+	c.assertQuantity(variantID, 5)
+
+	// The order is no longer "received", so canceling it again is a no-op
+	if canceled, _ := c.orderStore.CancelOrder(orderID, types.SystemAdminID, "received", ""); canceled {
+		t.Error("expected a second cancellation to be rejected")
+	}
+	c.assertQuantity(variantID, 5)
+
+	movements, err := c.sockStore.GetInventoryMovements(variantID, 10, 0)
+	if err != nil {
+		t.Fatalf("unable to get inventory movements: %v", err)
+	}
+	if len(movements) != 3 || movements[0].Reason != types.MovementCanceled || movements[0].Delta != 2 {
+		t.Errorf("expected the cancellation to be recorded in the ledger, got %+v", movements)
+	}
+}
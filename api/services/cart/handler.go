@@ -1,42 +1,46 @@
 package cart
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"strconv"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
 	"github.com/sockify/sockify/config"
-	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/middleware"
 	"github.com/sockify/sockify/types"
 	"github.com/sockify/sockify/utils"
-	"github.com/stripe/stripe-go/v80"
-	"github.com/stripe/stripe-go/v80/checkout/session"
 )
 
+// Stripe recommends keeping webhook payloads under 64KB.
+const maxWebhookPayloadBytes = 65536
+
 type CartHandler struct {
-	sockStore    types.SockStore
-	orderStore   types.OrderStore
-	emailService email.Service
+	sockStore  types.SockStore
+	orderStore types.OrderStore
+	payments   types.PaymentGateway
 }
 
-func NewCartHandler(ss types.SockStore, os types.OrderStore, es email.Service) *CartHandler {
-	return &CartHandler{sockStore: ss, orderStore: os, emailService: es}
+func NewCartHandler(ss types.SockStore, os types.OrderStore, pg types.PaymentGateway) *CartHandler {
+	return &CartHandler{sockStore: ss, orderStore: os, payments: pg}
 }
 
-func (h *CartHandler) RegisterRoutes(router *mux.Router) {
-	router.HandleFunc("/cart/checkout/stripe-session", h.handleCheckoutWithStripe).Methods(http.MethodPost)
+func (h *CartHandler) RegisterRoutes(router *mux.Router, customerStore types.CustomerStore) {
+	router.HandleFunc("/cart/checkout/stripe-session", middleware.WithOptionalCustomerAuth(customerStore, h.handleCheckoutWithStripe)).Methods(http.MethodPost)
 	router.HandleFunc("/cart/checkout/stripe-confirmation/{session_id}", h.handleStripeConfirmation).Methods(http.MethodGet)
+	router.HandleFunc("/cart/checkout/stripe-webhook", h.handleStripeWebhook).Methods(http.MethodPost)
 }
 
 // @Summary Creates a Stripe checkout session
 // @Description Creates a new Stripe checkout session after creating a "pending" order in the database. The "orderId" is attached within the metadata.
+// @Description Guests can checkout anonymously. When a customer token is provided, the order is linked to the customer account.
 // @Tags Cart
 // @Accept json
 // @Produce json
+// @Security Bearer
 // @Param payload body types.CheckoutOrderRequest true "Order to checkout"
 // @Success 200 {object} types.StripeCheckoutResponse
 // @Router /cart/checkout/stripe-session [post]
@@ -48,8 +52,7 @@ func (h *CartHandler) handleCheckoutWithStripe(w http.ResponseWriter, r *http.Re
 	}
 
 	if err := utils.Validate.Struct(cart); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload: %v", errors))
+		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
@@ -60,9 +63,20 @@ func (h *CartHandler) handleCheckoutWithStripe(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	orderID, err := h.createOrder(sockVariants, cart)
+	var customerID *int
+	if id, ok := middleware.GetCustomerIDFromContext(r.Context()); ok {
+		customerID = &id
+	}
+
+	orderID, err := h.createOrder(sockVariants, cart, customerID)
 	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		if errors.Is(err, types.ErrInsufficientStock) || errors.Is(err, errEmptyCart) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		log.Printf("Failed to create order for checkout: %v", err)
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to create the order"))
 		return
 	}
 
@@ -72,43 +86,20 @@ func (h *CartHandler) handleCheckoutWithStripe(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	var lineItems []*stripe.CheckoutSessionLineItemParams
-	for _, item := range order.Items {
-		lineItems = append(lineItems, &stripe.CheckoutSessionLineItemParams{
-			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-				Currency: stripe.String(string(stripe.CurrencyUSD)),
-				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
-					Name:        stripe.String(item.Name),
-					Description: stripe.String("Size: " + item.Size),
-					// TODO: we can add images here
-				},
-				// Stripe expects price to be a whole number (e.g. $12.09 -> 1209)
-				UnitAmount: stripe.Int64(int64(item.Price * 100)),
-			},
-			Quantity: stripe.Int64(int64(item.Quantity)),
-		})
-	}
-
-	params := &stripe.CheckoutSessionParams{
-		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
-		LineItems:          lineItems,
-		Mode:               stripe.String("payment"),
-		SuccessURL:         stripe.String(config.Envs.WebClientURL + "/cart/checkout/order-confirmation?session_id={CHECKOUT_SESSION_ID}"),
-		CancelURL:          stripe.String(config.Envs.WebClientURL + "/cart/checkout/payment-canceled"),
-		ExpiresAt:          stripe.Int64(time.Now().Add(30 * time.Minute).Unix()),
-		Metadata: map[string]string{
-			"orderId": strconv.Itoa(orderID),
-		},
-	}
-
-	s, err := session.New(params)
+	session, err := h.payments.CreateCheckoutSession(types.CheckoutSessionRequest{
+		OrderID:    orderID,
+		Items:      order.Items,
+		SuccessURL: config.Envs.WebClientURL + "/cart/checkout/order-confirmation?session_id={SESSION_ID}",
+		CancelURL:  config.Envs.WebClientURL + "/cart/checkout/payment-canceled",
+		ExpiresAt:  time.Now().Add(time.Duration(config.Envs.CheckoutExpirationInSeconds) * time.Second),
+	})
 	if err != nil {
-		log.Printf("Failed to create Stripe session for order ID %v with params %v: %v", orderID, params, err)
+		log.Printf("Failed to create checkout session for order ID %v: %v", orderID, err)
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to create Stripe session"))
 		return
 	}
 
-	utils.WriteJson(w, http.StatusOK, types.StripeCheckoutResponse{PaymentURL: s.URL})
+	utils.WriteJson(w, http.StatusOK, types.StripeCheckoutResponse{PaymentURL: session.PaymentURL})
 }
 
 // @Summary Confirms a Stripe checkout session and retrieves the order
@@ -127,37 +118,50 @@ func (h *CartHandler) handleStripeConfirmation(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	s, err := session.Get(sessionID, nil)
+	s, err := h.payments.GetSessionStatus(sessionID)
 	if err != nil {
 		log.Printf("failed to retrieve checkout session with ID %v: %v", sessionID, err)
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("failed to retrieved checkout session"))
 		return
 	}
 
-	orderIDStr := s.Metadata["orderId"]
-	orderID, err := strconv.Atoi(orderIDStr)
-	if err != nil {
-		log.Printf("failed to convert orderIDStr '%v' to a number: %v", orderIDStr, err)
+	orderID := s.OrderID
+	if orderID == 0 {
+		log.Printf("failed to get the order ID for checkout session with ID %v", sessionID)
 		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to parse order ID"))
 		return
 	}
 
 	switch s.Status {
-	case stripe.CheckoutSessionStatusOpen:
+	case types.CheckoutSessionStatusOpen:
 		log.Printf("Order with ID %v is in 'open' state, payment pending", orderID)
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("payment for this order is still pending"))
 		return
 
-	case stripe.CheckoutSessionStatusComplete:
-		err := h.orderStore.UpdateOrderStatusNoLogs(orderID, "received")
+	case types.CheckoutSessionStatusComplete:
+		if !s.Paid {
+			log.Printf("Order with ID %v completed checkout but the payment is still processing", orderID)
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("payment for this order is still pending"))
+			return
+		}
+
+		// The webhook may have already completed the order, so this is a no-op in that case.
+		order, err := h.completeOrder(orderID, s.PaymentReference)
 		if err != nil {
-			log.Printf("Unable to update order status to 'received' for Stripe checkout session ID %v and order ID %v after successful payment: %v", sessionID, orderID, err)
+			log.Printf("Unable to complete order for Stripe checkout session ID %v and order ID %v after successful payment: %v", sessionID, orderID, err)
 			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to update order status to 'received' after successful payment"))
 			return
 		}
+		if order == nil {
+			log.Printf("order associated with Stripe checkout session ID %v and order ID  %v was not found", sessionID, orderID)
+			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order associated with the checkout session was not found"))
+			return
+		}
 
-	case stripe.CheckoutSessionStatusExpired:
-		err := h.orderStore.UpdateOrderStatusNoLogs(orderID, "canceled")
+		utils.WriteJson(w, http.StatusOK, toOrderConfirmation(*order))
+
+	case types.CheckoutSessionStatusExpired:
+		_, err := h.orderStore.CancelPendingOrder(orderID, types.SystemAdminID, "Order canceled since the Stripe checkout session expired.")
 		if err != nil {
 			log.Printf("Unable to cancel order with ID %v due to incomplete payment status '%v': %v", orderID, s.Status, err)
 			utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to cancel order with an incomplete payment status"))
@@ -165,34 +169,48 @@ func (h *CartHandler) handleStripeConfirmation(w http.ResponseWriter, r *http.Re
 		}
 
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("checkout session through Stripe was not completed. The order has been cancelled"))
-		return
 
 	default:
 		log.Printf("Unexpected status '%v' for Stripe checkout session ID %v and order ID %v", s.Status, sessionID, orderID)
 		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unexpected checkout session status: %v", s.Status))
-		return
 	}
+}
 
-	order, err := h.orderStore.GetOrderById(orderID)
+// @Summary Receives Stripe webhook events
+// @Description Handles the payment lifecycle events sent by Stripe ("checkout.session.completed", "checkout.session.expired", "charge.refunded"). Requests must be signed with the `Stripe-Signature` header. Processing is idempotent, so Stripe can safely retry deliveries. Returns a 503 when the webhook secret is not configured.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param Stripe-Signature header string true "Stripe webhook signature"
+// @Success 200 {object} types.Message
+// @Router /cart/checkout/stripe-webhook [post]
+func (h *CartHandler) handleStripeWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayloadBytes))
 	if err != nil {
-		log.Printf("unable to find order with ID '%v': %v", orderID, err)
-		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to find order"))
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unable to read the webhook payload"))
 		return
 	}
-	if order == nil {
-		log.Printf("order associated with Stripe checkout session ID %v and order ID  %v was not found: %v", sessionID, orderID, err)
-		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order associated with the checkout session was not found"))
+
+	event, err := h.payments.ParseWebhookEvent(payload, r.Header.Get("Stripe-Signature"))
+	if errors.Is(err, types.ErrWebhookNotConfigured) {
+		log.Println("[ERROR] rejected a Stripe webhook since STRIPE_WEBHOOK_SECRET is not set")
+		utils.WriteError(w, http.StatusServiceUnavailable, err)
 		return
 	}
-
-	oc := toOrderConfirmation(*order)
-	err = h.emailService.SendOrderConfirmationEmail(order.Contact.FirstName+" "+order.Contact.LastName, order.Contact.Email, oc)
 	if err != nil {
-		log.Printf("unable to send order confirmation email for invoice number %v and email %v", order.InvoiceNumber, order.Contact.Email)
-		// TODO: would probably be a good idea to add a "System" log for the order saying this
+		log.Printf("Unable to verify Stripe webhook signature: %v", err)
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid webhook signature"))
+		return
+	}
+
+	if err := h.handlePaymentEvent(event); err != nil {
+		log.Printf("Unable to handle Stripe webhook event %v (%v): %v", event.ID, event.Type, err)
+		// A non-2xx response lets Stripe retry the delivery later.
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to process the webhook event"))
+		return
 	}
 
-	utils.WriteJson(w, http.StatusOK, oc)
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Webhook event received"})
 }
 
 func toOrderConfirmation(o types.Order) types.OrderConfirmation {
@@ -0,0 +1,278 @@
+package cart
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sockify/sockify/services/payments"
+	"github.com/sockify/sockify/types"
+	"github.com/stripe/stripe-go/v80/webhook"
+)
+
+const testWebhookSecret = "whsec_test_secret"
+
+// fakeOrderStore keeps orders in memory. Only the methods used by payment events are implemented.
+type fakeOrderStore struct {
+	types.OrderStore
+	mu     sync.Mutex
+	orders map[int]*types.Order
+	// Order confirmation emails queued by `MarkOrderPaid`
+	confirmations int
+}
+
+func newFakeOrderStore(orders ...types.Order) *fakeOrderStore {
+	s := &fakeOrderStore{orders: make(map[int]*types.Order)}
+	for i := range orders {
+		s.orders[orders[i].ID] = &orders[i]
+	}
+	return s
+}
+
+func (s *fakeOrderStore) MarkOrderPaid(orderID int, paymentReference string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok || order.Status != "pending" {
+		return false, nil
+	}
+	order.Status = "received"
+	order.PaymentReference = &paymentReference
+	s.confirmations++
+	return true, nil
+}
+
+func (s *fakeOrderStore) GetOrderById(orderID int) (*types.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return nil, nil
+	}
+	o := *order
+	return &o, nil
+}
+
+func (s *fakeOrderStore) CancelPendingOrder(orderID int, adminID int, message string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, ok := s.orders[orderID]
+	if !ok {
+		return false, sql.ErrNoRows
+	}
+	if order.Status != "pending" {
+		return false, nil
+	}
+	order.Status = "canceled"
+	return true, nil
+}
+
+func (s *fakeOrderStore) CreateOrderUpdate(orderID int, adminID int, message string, isInternal bool) error {
+	return nil
+}
+
+func (s *fakeOrderStore) status(orderID int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.orders[orderID].Status
+}
+
+func (s *fakeOrderStore) queuedConfirmations() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.confirmations
+}
+
+func newWebhookTestHandler(webhookSecret string) (*CartHandler, *fakeOrderStore) {
+	store := newFakeOrderStore(types.Order{
+		ID:            42,
+		InvoiceNumber: "inv-42",
+		Status:        "pending",
+		Contact:       types.Contact{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"},
+	})
+	gateway := payments.NewStripeGateway("sk_test", webhookSecret)
+	return NewCartHandler(nil, store, gateway), store
+}
+
+// checkoutCompletedEvent returns the payload of a Stripe "checkout.session.completed" event for the order.
+func checkoutCompletedEvent(eventID string, orderID int) []byte {
+	return []byte(fmt.Sprintf(`{
+  "id": %q,
+  "object": "event",
+  "type": "checkout.session.completed",
+  "data": {
+    "object": {
+      "id": "cs_test_1",
+      "object": "checkout.session",
+      "status": "complete",
+      "payment_status": "paid",
+      "payment_intent": "pi_test_1",
+      "metadata": {"orderId": "%d"}
+    }
+  }
+}`, eventID, orderID))
+}
+
+// checkoutExpiredEvent returns the payload of a Stripe "checkout.session.expired" event for the order.
+func checkoutExpiredEvent(eventID string, orderID int) []byte {
+	return []byte(fmt.Sprintf(`{
+  "id": %q,
+  "object": "event",
+  "type": "checkout.session.expired",
+  "data": {
+    "object": {
+      "id": "cs_test_1",
+      "object": "checkout.session",
+      "status": "expired",
+      "payment_status": "unpaid",
+      "metadata": {"orderId": "%d"}
+    }
+  }
+}`, eventID, orderID))
+}
+
+// signStripePayload builds a `Stripe-Signature` header for the payload as Stripe does.
+func signStripePayload(payload []byte, secret string, at time.Time) string {
+	signature := webhook.ComputeSignature(at, payload, secret)
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), hex.EncodeToString(signature))
+}
+
+func postWebhook(h *CartHandler, payload []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/cart/checkout/stripe-webhook", bytes.NewReader(payload))
+	req.Header.Set("Stripe-Signature", signature)
+	rr := httptest.NewRecorder()
+	h.handleStripeWebhook(rr, req)
+	return rr
+}
+
+func TestStripeWebhookCompletesOrder(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutCompletedEvent("evt_1", 42)
+
+	rr := postWebhook(h, payload, signStripePayload(payload, testWebhookSecret, time.Now()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "received" {
+		t.Errorf("expected order to be received, got %q", status)
+	}
+	if queued := store.queuedConfirmations(); queued != 1 {
+		t.Errorf("expected 1 confirmation email, got %d", queued)
+	}
+}
+
+func TestStripeWebhookRejectsBadSignature(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutCompletedEvent("evt_1", 42)
+
+	rr := postWebhook(h, payload, signStripePayload(payload, "whsec_forged", time.Now()))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "pending" {
+		t.Errorf("expected order to stay pending, got %q", status)
+	}
+	if queued := store.queuedConfirmations(); queued != 0 {
+		t.Errorf("expected no email, got %d", queued)
+	}
+}
+
+func TestStripeWebhookRejectsTamperedPayload(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	signature := signStripePayload(checkoutCompletedEvent("evt_1", 7), testWebhookSecret, time.Now())
+
+	rr := postWebhook(h, checkoutCompletedEvent("evt_1", 42), signature)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "pending" {
+		t.Errorf("expected order to stay pending, got %q", status)
+	}
+}
+
+func TestStripeWebhookRejectsReplayedEvent(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutCompletedEvent("evt_1", 42)
+
+	// Signed correctly, but outside of the tolerated window
+	rr := postWebhook(h, payload, signStripePayload(payload, testWebhookSecret, time.Now().Add(-time.Hour)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "pending" {
+		t.Errorf("expected order to stay pending, got %q", status)
+	}
+}
+
+func TestStripeWebhookDuplicateEventIsNoop(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutCompletedEvent("evt_1", 42)
+	signature := signStripePayload(payload, testWebhookSecret, time.Now())
+
+	for i := 0; i < 2; i++ {
+		if rr := postWebhook(h, payload, signature); rr.Code != http.StatusOK {
+			t.Fatalf("delivery %d: expected status %d, got %d: %s", i+1, http.StatusOK, rr.Code, rr.Body.String())
+		}
+	}
+
+	if status := store.status(42); status != "received" {
+		t.Errorf("expected order to be received, got %q", status)
+	}
+	if queued := store.queuedConfirmations(); queued != 1 {
+		t.Errorf("expected 1 confirmation email for a duplicate delivery, got %d", queued)
+	}
+}
+
+func TestStripeWebhookWithoutSecretFailsClosed(t *testing.T) {
+	h, store := newWebhookTestHandler("")
+	payload := checkoutCompletedEvent("evt_1", 42)
+
+	// Signed with the former default secret
+	rr := postWebhook(h, payload, signStripePayload(payload, "FIXME", time.Now()))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusServiceUnavailable, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "pending" {
+		t.Errorf("expected order to stay pending, got %q", status)
+	}
+}
+
+func TestStripeWebhookExpiredSessionCancelsOrder(t *testing.T) {
+	h, store := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutExpiredEvent("evt_1", 42)
+
+	rr := postWebhook(h, payload, signStripePayload(payload, testWebhookSecret, time.Now()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if status := store.status(42); status != "canceled" {
+		t.Errorf("expected order to be canceled, got %q", status)
+	}
+}
+
+func TestStripeWebhookExpiredSessionOfUnknownOrderIsNoop(t *testing.T) {
+	h, _ := newWebhookTestHandler(testWebhookSecret)
+	payload := checkoutExpiredEvent("evt_1", 7)
+
+	// Retrying can never find the order, so the event is acknowledged
+	rr := postWebhook(h, payload, signStripePayload(payload, testWebhookSecret, time.Now()))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+}
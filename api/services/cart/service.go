@@ -1,69 +1,133 @@
 package cart
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 
 	"github.com/sockify/sockify/types"
 )
 
-func (h *CartHandler) createOrder(sockVariants []types.SockVariant, cart types.CheckoutOrderRequest) (orderID int, err error) {
+var errEmptyCart = types.NewError("empty_cart", "cart is empty")
+
+func (h *CartHandler) createOrder(sockVariants []types.SockVariant, cart types.CheckoutOrderRequest, customerID *int) (orderID int, err error) {
 	sockVariantsMap := make(map[int]types.SockVariant)
 	for _, sv := range sockVariants {
 		sockVariantsMap[sv.ID] = sv
 	}
 
+	// Quick pre-check for friendlier errors. The order store still guarantees stock is available when reserving it.
 	err = isInStock(sockVariantsMap, cart.Items)
 	if err != nil {
 		return 0, err
 	}
 
-	for _, item := range cart.Items {
-		sv := sockVariantsMap[item.SockVariantID]
-		newQuantity := sv.Quantity - item.Quantity
-		h.sockStore.UpdateSockVariantQuantity(item.SockVariantID, newQuantity)
+	orderID, err = h.orderStore.CreateOrder(cart.Items, cart.Address, cart.Contact, customerID)
+	if err != nil {
+		return 0, err
 	}
 
-	total := calculateOrderTotal(sockVariantsMap, cart.Items)
-	orderID, err = h.orderStore.CreateOrder(cart.Items, total, cart.Address, cart.Contact)
+	return orderID, nil
+}
+
+// completeOrder marks an order as paid, which queues the order confirmation email.
+// It is safe to call multiple times (e.g. from the confirmation page and the webhook): only the call that moves the order out of "pending" queues the email.
+func (h *CartHandler) completeOrder(orderID int, paymentReference string) (*types.Order, error) {
+	updated, err := h.orderStore.MarkOrderPaid(orderID, paymentReference)
 	if err != nil {
-		return 0, fmt.Errorf("unable to create the order: %v", err)
+		return nil, err
 	}
 
-	for _, item := range cart.Items {
-		sv := sockVariantsMap[item.SockVariantID]
-		err := h.orderStore.CreateOrderItem(orderID, item.SockVariantID, sv.Price, item.Quantity)
+	order, err := h.orderStore.GetOrderById(orderID)
+	if err != nil || order == nil {
+		return order, err
+	}
+
+	if !updated && order.Status == "canceled" {
+		log.Printf("[WARN] payment %v was completed for order ID %v but the order is canceled and requires a refund", paymentReference, orderID)
+	}
+
+	return order, nil
+}
+
+// handlePaymentEvent applies a verified payment webhook event to the matching order.
+// Returning an error signals the payment provider to retry the delivery, so events that can never succeed are only logged.
+func (h *CartHandler) handlePaymentEvent(event *types.PaymentEvent) error {
+	switch event.Type {
+	case types.PaymentEventCheckoutCompleted:
+		s := event.Session
+		if s == nil || s.OrderID == 0 {
+			log.Printf("Ignoring completed checkout session without an order ID (event %v)", event.ID)
+			return nil
+		}
+		if !s.Paid {
+			log.Printf("Checkout session ID %v for order ID %v was completed but is not paid yet", s.SessionID, s.OrderID)
+			return nil
+		}
+
+		_, err := h.completeOrder(s.OrderID, s.PaymentReference)
+		return err
+
+	case types.PaymentEventCheckoutExpired:
+		s := event.Session
+		if s == nil || s.OrderID == 0 {
+			log.Printf("Ignoring expired checkout session without an order ID (event %v)", event.ID)
+			return nil
+		}
+
+		_, err := h.orderStore.CancelPendingOrder(s.OrderID, types.SystemAdminID, "Order canceled since the Stripe checkout session expired.")
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Ignoring expired checkout session ID %v: order ID %v not found", s.SessionID, s.OrderID)
+			return nil
+		}
+		return err
+
+	case types.PaymentEventRefunded:
+		if event.PaymentReference == "" {
+			log.Printf("Ignoring refund without a payment reference (event %v)", event.ID)
+			return nil
+		}
+		if !event.FullyRefunded {
+			log.Printf("Payment %v was partially refunded", event.PaymentReference)
+			return nil
+		}
+
+		order, err := h.orderStore.GetOrderByPaymentReference(event.PaymentReference)
 		if err != nil {
-			return 0, nil
+			return err
+		}
+		if order == nil {
+			log.Printf("Ignoring refund for payment %v: no order found", event.PaymentReference)
+			return nil
 		}
-	}
 
-	return orderID, nil
+		// Orders that already shipped are handled through returns by an admin.
+		_, err = h.orderStore.UpdateOrderStatusIf(order.ID, types.SystemAdminID, "received", "canceled", "Order canceled since the payment was refunded through Stripe.")
+		return err
+
+	default:
+		log.Printf("Ignoring unhandled payment webhook event type: %v", event.Type)
+		return nil
+	}
 }
 
 func isInStock(sockVariantsMap map[int]types.SockVariant, items []types.CheckoutItem) error {
 	if len(items) == 0 {
-		return fmt.Errorf("cart is empty")
+		return errEmptyCart
 	}
 
 	for _, item := range items {
 		sv := sockVariantsMap[item.SockVariantID]
 
 		if sv.Quantity <= 0 {
-			return fmt.Errorf("sock variant with ID %v is out of stock", item.SockVariantID)
+			return fmt.Errorf("%w: sock variant with ID %v is out of stock", types.ErrInsufficientStock, item.SockVariantID)
 		}
 
 		if sv.Quantity < item.Quantity {
-			return fmt.Errorf("sock variant with ID %v is not available in the quantity requested", item.SockVariantID)
+			return fmt.Errorf("%w: sock variant with ID %v is not available in the quantity requested", types.ErrInsufficientStock, item.SockVariantID)
 		}
 	}
 
 	return nil
 }
-
-func calculateOrderTotal(sockVariantsMap map[int]types.SockVariant, items []types.CheckoutItem) (total float64) {
-	for _, item := range items {
-		sv := sockVariantsMap[item.SockVariantID]
-		total += (sv.Price * float64(item.Quantity))
-	}
-	return total
-}
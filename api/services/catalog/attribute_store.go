@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/types"
+)
+
+const attributeColumns = "attribute_id, code, name, position, created_at"
+
+type AttributeStore struct {
+	db *sql.DB
+}
+
+func NewAttributeStore(db *sql.DB) types.AttributeStore {
+	return &AttributeStore{db: db}
+}
+
+// GetAttributes retrieves every attribute with its values, both sorted by position.
+func (s *AttributeStore) GetAttributes() ([]types.Attribute, error) {
+	rows, err := s.db.Query("SELECT " + attributeColumns + " FROM attributes ORDER BY position ASC, attribute_id ASC")
+	if err != nil {
+		log.Printf("Error fetching attributes: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := make([]types.Attribute, 0)
+	for rows.Next() {
+		attribute, err := scanRowIntoAttribute(rows)
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, *attribute)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	values, err := s.getAttributeValues(nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range attributes {
+		attributes[i].Values = values[attributes[i].ID]
+		if attributes[i].Values == nil {
+			attributes[i].Values = make([]types.AttributeValue, 0)
+		}
+	}
+	return attributes, nil
+}
+
+func (s *AttributeStore) GetAttributeByID(attributeID int) (*types.Attribute, error) {
+	attribute, err := scanRowIntoAttribute(s.db.QueryRow("SELECT "+attributeColumns+" FROM attributes WHERE attribute_id = $1", attributeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching attribute ID %v: %v", attributeID, err)
+		return nil, err
+	}
+
+	values, err := s.getAttributeValues(&attributeID)
+	if err != nil {
+		return nil, err
+	}
+	attribute.Values = values[attributeID]
+	if attribute.Values == nil {
+		attribute.Values = make([]types.AttributeValue, 0)
+	}
+	return attribute, nil
+}
+
+// getAttributeValues returns the values of an attribute, or of every attribute when the ID is nil, by attribute ID.
+func (s *AttributeStore) getAttributeValues(attributeID *int) (map[int][]types.AttributeValue, error) {
+	rows, err := s.db.Query(`
+    SELECT attribute_id, attribute_value_id, value, position
+    FROM attribute_values
+    WHERE $1::INTEGER IS NULL OR attribute_id = $1
+    ORDER BY position ASC, attribute_value_id ASC
+  `, attributeID)
+	if err != nil {
+		log.Printf("Error fetching attribute values: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[int][]types.AttributeValue)
+	for rows.Next() {
+		var id int
+		var value types.AttributeValue
+		if err := rows.Scan(&id, &value.ID, &value.Value, &value.Position); err != nil {
+			log.Printf("Error scanning attribute value: %v", err)
+			return nil, err
+		}
+		values[id] = append(values[id], value)
+	}
+	return values, rows.Err()
+}
+
+func (s *AttributeStore) CreateAttribute(attribute types.Attribute) (attributeID int, err error) {
+	err = s.db.QueryRow(
+		"INSERT INTO attributes (code, name, position) VALUES ($1, $2, $3) RETURNING attribute_id",
+		attribute.Code, attribute.Name, attribute.Position,
+	).Scan(&attributeID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, types.ErrAttributeAlreadyExists
+		}
+		log.Printf("Error creating attribute: %v", err)
+		return 0, err
+	}
+	return attributeID, nil
+}
+
+func (s *AttributeStore) UpdateAttribute(attribute types.Attribute) error {
+	_, err := s.db.Exec(
+		"UPDATE attributes SET code = $1, name = $2, position = $3 WHERE attribute_id = $4",
+		attribute.Code, attribute.Name, attribute.Position, attribute.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return types.ErrAttributeAlreadyExists
+		}
+		log.Printf("Error updating attribute ID %v: %v", attribute.ID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteAttribute deletes an attribute and its values. Values referenced by variants, archived ones included, block the deletion.
+func (s *AttributeStore) DeleteAttribute(attributeID int) error {
+	_, err := s.db.Exec("DELETE FROM attributes WHERE attribute_id = $1", attributeID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return types.ErrAttributeInUse
+		}
+		log.Printf("Error deleting attribute ID %v: %v", attributeID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *AttributeStore) CreateAttributeValue(attributeID int, value types.AttributeValue) (valueID int, err error) {
+	err = s.db.QueryRow(
+		"INSERT INTO attribute_values (attribute_id, value, position) VALUES ($1, $2, $3) RETURNING attribute_value_id",
+		attributeID, value.Value, value.Position,
+	).Scan(&valueID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, types.ErrAttributeValueAlreadyExists
+		}
+		log.Printf("Error creating value for attribute ID %v: %v", attributeID, err)
+		return 0, err
+	}
+	return valueID, nil
+}
+
+// UpdateAttributeValue renames or moves a value. Variants using the value show the new name.
+func (s *AttributeStore) UpdateAttributeValue(attributeID int, value types.AttributeValue) error {
+	_, err := s.db.Exec(
+		"UPDATE attribute_values SET value = $1, position = $2 WHERE attribute_value_id = $3 AND attribute_id = $4",
+		value.Value, value.Position, value.ID, attributeID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return types.ErrAttributeValueAlreadyExists
+		}
+		log.Printf("Error updating attribute value ID %v: %v", value.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *AttributeStore) DeleteAttributeValue(attributeID int, valueID int) error {
+	_, err := s.db.Exec("DELETE FROM attribute_values WHERE attribute_value_id = $1 AND attribute_id = $2", valueID, attributeID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return types.ErrAttributeInUse
+		}
+		log.Printf("Error deleting attribute value ID %v: %v", valueID, err)
+		return err
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+func scanRowIntoAttribute(row rowScanner) (*types.Attribute, error) {
+	attribute := &types.Attribute{}
+	if err := row.Scan(&attribute.ID, &attribute.Code, &attribute.Name, &attribute.Position, &attribute.CreatedAt); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
@@ -0,0 +1,317 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var errInvalidAttributeCode = types.NewError("invalid_attribute_code", "code must start with a letter and only contain lowercase letters, digits and underscores")
+
+// @Summary Get all attributes
+// @Description Returns the variant attributes, such as size or color, with their allowed values. Attributes and values are sorted by position.
+// @Tags Catalog
+// @Produce json
+// @Success 200 {array} types.Attribute
+// @Router /attributes [get]
+func (h *Handler) handleGetAttributes(w http.ResponseWriter, r *http.Request) {
+	attributes, err := h.attributes.GetAttributes()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, attributes)
+}
+
+// @Summary Get an attribute
+// @Tags Catalog
+// @Produce json
+// @Param attribute_id path int true "Attribute ID"
+// @Success 200 {object} types.Attribute
+// @Router /attributes/{attribute_id} [get]
+func (h *Handler) handleGetAttribute(w http.ResponseWriter, r *http.Request) {
+	attribute, ok := h.attributeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, attribute)
+}
+
+// @Summary Create an attribute
+// @Description Creates a variant attribute without values. The code is the key used in variant options and filters, e.g. "color".
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param attribute body types.AttributeRequest true "Attribute"
+// @Success 201 {object} types.Attribute
+// @Router /attributes [post]
+func (h *Handler) handleCreateAttribute(w http.ResponseWriter, r *http.Request) {
+	attribute, ok := parseAttributeRequest(w, r)
+	if !ok {
+		return
+	}
+
+	attributeID, err := h.attributes.CreateAttribute(attribute)
+	if err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	created, err := h.attributes.GetAttributeByID(attributeID)
+	if err != nil || created == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the created attribute"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, created)
+}
+
+// @Summary Update an attribute
+// @Description Renames or moves an attribute. Changing the code changes the key of the option in every variant.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param attribute_id path int true "Attribute ID"
+// @Param attribute body types.AttributeRequest true "Attribute"
+// @Success 200 {object} types.Attribute
+// @Router /attributes/{attribute_id} [patch]
+func (h *Handler) handleUpdateAttribute(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.attributeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	attribute, ok := parseAttributeRequest(w, r)
+	if !ok {
+		return
+	}
+	attribute.ID = existing.ID
+
+	if err := h.attributes.UpdateAttribute(attribute); err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	h.writeAttribute(w, existing.ID, http.StatusOK)
+}
+
+// @Summary Delete an attribute
+// @Description Deletes an attribute and its values. Returns 409 if a sock variant, including an archived one, uses one of its values.
+// @Tags Catalog
+// @Produce json
+// @Security Bearer
+// @Param attribute_id path int true "Attribute ID"
+// @Success 200 {object} types.Message
+// @Router /attributes/{attribute_id} [delete]
+func (h *Handler) handleDeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	attribute, ok := h.attributeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.attributes.DeleteAttribute(attribute.ID); err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Attribute deleted successfully"})
+}
+
+// @Summary Add an attribute value
+// @Description Allows a new value for the attribute. Values are unique per attribute, ignoring case.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param attribute_id path int true "Attribute ID"
+// @Param value body types.AttributeValueRequest true "Value"
+// @Success 201 {object} types.Attribute
+// @Router /attributes/{attribute_id}/values [post]
+func (h *Handler) handleCreateAttributeValue(w http.ResponseWriter, r *http.Request) {
+	attribute, ok := h.attributeFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	value, ok := parseAttributeValueRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.attributes.CreateAttributeValue(attribute.ID, value); err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	h.writeAttribute(w, attribute.ID, http.StatusCreated)
+}
+
+// @Summary Update an attribute value
+// @Description Renames or moves a value. Sock variants using the value show the new name.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param attribute_id path int true "Attribute ID"
+// @Param value_id path int true "Value ID"
+// @Param value body types.AttributeValueRequest true "Value"
+// @Success 200 {object} types.Attribute
+// @Router /attributes/{attribute_id}/values/{value_id} [patch]
+func (h *Handler) handleUpdateAttributeValue(w http.ResponseWriter, r *http.Request) {
+	attribute, valueID, ok := h.attributeValueFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	value, ok := parseAttributeValueRequest(w, r)
+	if !ok {
+		return
+	}
+	value.ID = valueID
+
+	if err := h.attributes.UpdateAttributeValue(attribute.ID, value); err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	h.writeAttribute(w, attribute.ID, http.StatusOK)
+}
+
+// @Summary Delete an attribute value
+// @Description Deletes a value. Returns 409 if a sock variant, including an archived one, uses it.
+// @Tags Catalog
+// @Produce json
+// @Security Bearer
+// @Param attribute_id path int true "Attribute ID"
+// @Param value_id path int true "Value ID"
+// @Success 200 {object} types.Message
+// @Router /attributes/{attribute_id}/values/{value_id} [delete]
+func (h *Handler) handleDeleteAttributeValue(w http.ResponseWriter, r *http.Request) {
+	attribute, valueID, ok := h.attributeValueFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.attributes.DeleteAttributeValue(attribute.ID, valueID); err != nil {
+		writeAttributeError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Attribute value deleted successfully"})
+}
+
+// attributeFromRequest fetches the attribute in the request path, writing an error response if it does not exist.
+func (h *Handler) attributeFromRequest(w http.ResponseWriter, r *http.Request) (*types.Attribute, bool) {
+	attributeID, ok := pathID(w, r, "attribute_id")
+	if !ok {
+		return nil, false
+	}
+
+	attribute, err := h.attributes.GetAttributeByID(attributeID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if attribute == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("attribute not found"))
+		return nil, false
+	}
+	return attribute, true
+}
+
+// attributeValueFromRequest fetches the attribute in the request path and checks that the value in the path belongs to it.
+func (h *Handler) attributeValueFromRequest(w http.ResponseWriter, r *http.Request) (*types.Attribute, int, bool) {
+	attribute, ok := h.attributeFromRequest(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+	valueID, ok := pathID(w, r, "value_id")
+	if !ok {
+		return nil, 0, false
+	}
+
+	for _, value := range attribute.Values {
+		if value.ID == valueID {
+			return attribute, valueID, true
+		}
+	}
+	utils.WriteError(w, http.StatusNotFound, errors.New("attribute value not found"))
+	return nil, 0, false
+}
+
+// writeAttribute writes the current state of an attribute.
+func (h *Handler) writeAttribute(w http.ResponseWriter, attributeID int, status utils.HttpStatus) {
+	attribute, err := h.attributes.GetAttributeByID(attributeID)
+	if err != nil || attribute == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the updated attribute"))
+		return
+	}
+
+	utils.WriteJson(w, status, attribute)
+}
+
+func parseAttributeRequest(w http.ResponseWriter, r *http.Request) (attribute types.Attribute, ok bool) {
+	var req types.AttributeRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return attribute, false
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return attribute, false
+	}
+
+	code := utils.Normalize(req.Code)
+	if !attributeCodePattern.MatchString(code) {
+		utils.WriteError(w, http.StatusBadRequest, errInvalidAttributeCode)
+		return attribute, false
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("attribute name cannot be blank"))
+		return attribute, false
+	}
+
+	return types.Attribute{Code: code, Name: name, Position: req.Position}, true
+}
+
+func parseAttributeValueRequest(w http.ResponseWriter, r *http.Request) (value types.AttributeValue, ok bool) {
+	var req types.AttributeValueRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return value, false
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return value, false
+	}
+
+	text := strings.TrimSpace(req.Value)
+	if text == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("attribute value cannot be blank"))
+		return value, false
+	}
+
+	return types.AttributeValue{Value: text, Position: req.Position}, true
+}
+
+func writeAttributeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, types.ErrAttributeAlreadyExists),
+		errors.Is(err, types.ErrAttributeValueAlreadyExists),
+		errors.Is(err, types.ErrAttributeInUse):
+		utils.WriteError(w, http.StatusConflict, err)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, err)
+	}
+}
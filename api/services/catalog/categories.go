@@ -0,0 +1,190 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// @Summary Get all categories
+// @Description Returns every category sorted by name. Categories are hierarchical: subcategories reference their parent through "parentId".
+// @Tags Catalog
+// @Produce json
+// @Success 200 {array} types.Category
+// @Router /categories [get]
+func (h *Handler) handleGetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.store.GetCategories()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, categories)
+}
+
+// @Summary Get a category
+// @Tags Catalog
+// @Produce json
+// @Param category_id path int true "Category ID"
+// @Success 200 {object} types.Category
+// @Router /categories/{category_id} [get]
+func (h *Handler) handleGetCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := pathID(w, r, "category_id")
+	if !ok {
+		return
+	}
+
+	category, err := h.store.GetCategoryByID(categoryID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if category == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("category not found"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, category)
+}
+
+// @Summary Create a category
+// @Description Creates a category, optionally under a parent category. The slug is generated from the name when omitted.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param category body types.CategoryRequest true "Category"
+// @Success 201 {object} types.Category
+// @Router /categories [post]
+func (h *Handler) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	category, ok := parseCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	categoryID, err := h.store.CreateCategory(category)
+	if err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	created, err := h.store.GetCategoryByID(categoryID)
+	if err != nil || created == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the created category"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, created)
+}
+
+// @Summary Update a category
+// @Description Renames or moves a category. A category cannot be moved under itself or one of its subcategories.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param category_id path int true "Category ID"
+// @Param category body types.CategoryRequest true "Category"
+// @Success 200 {object} types.Category
+// @Router /categories/{category_id} [patch]
+func (h *Handler) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := pathID(w, r, "category_id")
+	if !ok {
+		return
+	}
+
+	existing, err := h.store.GetCategoryByID(categoryID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if existing == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("category not found"))
+		return
+	}
+
+	category, ok := parseCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+	category.ID = categoryID
+
+	if err := h.store.UpdateCategory(category); err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	updated, err := h.store.GetCategoryByID(categoryID)
+	if err != nil || updated == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the updated category"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, updated)
+}
+
+// @Summary Delete a category
+// @Description Deletes a category without subcategories. Its socks are left without a category.
+// @Tags Catalog
+// @Produce json
+// @Security Bearer
+// @Param category_id path int true "Category ID"
+// @Success 200 {object} types.Message
+// @Router /categories/{category_id} [delete]
+func (h *Handler) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := pathID(w, r, "category_id")
+	if !ok {
+		return
+	}
+
+	category, err := h.store.GetCategoryByID(categoryID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if category == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("category not found"))
+		return
+	}
+
+	if err := h.store.DeleteCategory(categoryID); err != nil {
+		writeCategoryError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Category deleted successfully"})
+}
+
+func parseCategoryRequest(w http.ResponseWriter, r *http.Request) (category types.Category, ok bool) {
+	var req types.CategoryRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return category, false
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return category, false
+	}
+
+	slug, err := slugFor(req.Slug, req.Name)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return category, false
+	}
+
+	return types.Category{ParentID: req.ParentID, Name: strings.TrimSpace(req.Name), Slug: slug}, true
+}
+
+func writeCategoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, types.ErrSlugAlreadyExists), errors.Is(err, types.ErrCategoryHasChildren):
+		utils.WriteError(w, http.StatusConflict, err)
+	case errors.Is(err, types.ErrCategoryNotFound), errors.Is(err, types.ErrCategoryCycle):
+		utils.WriteError(w, http.StatusBadRequest, err)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,218 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// @Summary Get all collections
+// @Description Returns every collection sorted by name. Use `GET /socks?collection_id=` to list the socks of a collection.
+// @Tags Catalog
+// @Produce json
+// @Success 200 {array} types.Collection
+// @Router /collections [get]
+func (h *Handler) handleGetCollections(w http.ResponseWriter, r *http.Request) {
+	collections, err := h.store.GetCollections()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, collections)
+}
+
+// @Summary Get a collection
+// @Description Returns a collection with the IDs of its socks in display order.
+// @Tags Catalog
+// @Produce json
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} types.Collection
+// @Router /collections/{collection_id} [get]
+func (h *Handler) handleGetCollection(w http.ResponseWriter, r *http.Request) {
+	collection, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, collection)
+}
+
+// @Summary Create a collection
+// @Description Creates an empty collection. The slug is generated from the name when omitted.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param collection body types.CollectionRequest true "Collection"
+// @Success 201 {object} types.Collection
+// @Router /collections [post]
+func (h *Handler) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	collection, ok := parseCollectionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	collectionID, err := h.store.CreateCollection(collection)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	created, err := h.store.GetCollectionByID(collectionID)
+	if err != nil || created == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the created collection"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, created)
+}
+
+// @Summary Update a collection
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param collection_id path int true "Collection ID"
+// @Param collection body types.CollectionRequest true "Collection"
+// @Success 200 {object} types.Collection
+// @Router /collections/{collection_id} [patch]
+func (h *Handler) handleUpdateCollection(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	collection, ok := parseCollectionRequest(w, r)
+	if !ok {
+		return
+	}
+	collection.ID = existing.ID
+
+	if err := h.store.UpdateCollection(collection); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	updated, err := h.store.GetCollectionByID(existing.ID)
+	if err != nil || updated == nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("unable to fetch the updated collection"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, updated)
+}
+
+// @Summary Delete a collection
+// @Description Deletes a collection. Its socks are not affected.
+// @Tags Catalog
+// @Produce json
+// @Security Bearer
+// @Param collection_id path int true "Collection ID"
+// @Success 200 {object} types.Message
+// @Router /collections/{collection_id} [delete]
+func (h *Handler) handleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	collection, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.store.DeleteCollection(collection.ID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Collection deleted successfully"})
+}
+
+// @Summary Set the socks of a collection
+// @Description Replaces the socks of a collection. Socks are displayed in the given order. Returns 400 if a sock does not exist.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param collection_id path int true "Collection ID"
+// @Param socks body types.SetCollectionSocksRequest true "Sock IDs"
+// @Success 200 {object} types.Collection
+// @Router /collections/{collection_id}/socks [put]
+func (h *Handler) handleSetCollectionSocks(w http.ResponseWriter, r *http.Request) {
+	collection, ok := h.collectionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.SetCollectionSocksRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.store.SetCollectionSocks(collection.ID, req.SockIDs); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	collection.SockIDs = req.SockIDs
+	utils.WriteJson(w, http.StatusOK, collection)
+}
+
+// collectionFromRequest fetches the collection in the request path, writing an error response if it does not exist.
+func (h *Handler) collectionFromRequest(w http.ResponseWriter, r *http.Request) (*types.Collection, bool) {
+	collectionID, ok := pathID(w, r, "collection_id")
+	if !ok {
+		return nil, false
+	}
+
+	collection, err := h.store.GetCollectionByID(collectionID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if collection == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("collection not found"))
+		return nil, false
+	}
+	return collection, true
+}
+
+func parseCollectionRequest(w http.ResponseWriter, r *http.Request) (collection types.Collection, ok bool) {
+	var req types.CollectionRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return collection, false
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return collection, false
+	}
+
+	slug, err := slugFor(req.Slug, req.Name)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return collection, false
+	}
+
+	return types.Collection{
+		Name:        strings.TrimSpace(req.Name),
+		Slug:        slug,
+		Description: strings.TrimSpace(req.Description),
+	}, true
+}
+
+func writeCollectionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, types.ErrSlugAlreadyExists):
+		utils.WriteError(w, http.StatusConflict, err)
+	case errors.Is(err, types.ErrSockNotFound):
+		utils.WriteError(w, http.StatusBadRequest, err)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, err)
+	}
+}
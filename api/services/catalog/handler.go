@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+var errEmptySlug = types.NewError("invalid_slug", "slug must contain letters or numbers")
+
+type Handler struct {
+	store      types.CatalogStore
+	attributes types.AttributeStore
+}
+
+func NewHandler(store types.CatalogStore, attributes types.AttributeStore) *Handler {
+	return &Handler{store: store, attributes: attributes}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router, adminStore types.AdminStore) {
+	router.HandleFunc("/categories", h.handleGetCategories).Methods(http.MethodGet)
+	router.HandleFunc("/categories", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleCreateCategory)).Methods(http.MethodPost)
+	router.HandleFunc("/categories/{category_id}", h.handleGetCategory).Methods(http.MethodGet)
+	router.HandleFunc("/categories/{category_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUpdateCategory)).Methods(http.MethodPatch)
+	router.HandleFunc("/categories/{category_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteCategory)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/tags", h.handleGetTags).Methods(http.MethodGet)
+	router.HandleFunc("/tags/{tag_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleRenameTag)).Methods(http.MethodPatch)
+	router.HandleFunc("/tags/{tag_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteTag)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/attributes", h.handleGetAttributes).Methods(http.MethodGet)
+	router.HandleFunc("/attributes", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleCreateAttribute)).Methods(http.MethodPost)
+	router.HandleFunc("/attributes/{attribute_id}", h.handleGetAttribute).Methods(http.MethodGet)
+	router.HandleFunc("/attributes/{attribute_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUpdateAttribute)).Methods(http.MethodPatch)
+	router.HandleFunc("/attributes/{attribute_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteAttribute)).Methods(http.MethodDelete)
+	router.HandleFunc("/attributes/{attribute_id}/values", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleCreateAttributeValue)).Methods(http.MethodPost)
+	router.HandleFunc("/attributes/{attribute_id}/values/{value_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUpdateAttributeValue)).Methods(http.MethodPatch)
+	router.HandleFunc("/attributes/{attribute_id}/values/{value_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteAttributeValue)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/collections", h.handleGetCollections).Methods(http.MethodGet)
+	router.HandleFunc("/collections", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleCreateCollection)).Methods(http.MethodPost)
+	router.HandleFunc("/collections/{collection_id}", h.handleGetCollection).Methods(http.MethodGet)
+	router.HandleFunc("/collections/{collection_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUpdateCollection)).Methods(http.MethodPatch)
+	router.HandleFunc("/collections/{collection_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteCollection)).Methods(http.MethodDelete)
+	router.HandleFunc("/collections/{collection_id}/socks", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleSetCollectionSocks)).Methods(http.MethodPut)
+}
+
+// pathID parses a numeric ID from the request path, writing an error response if it is invalid.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (id int, ok bool) {
+	id, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid "+name))
+		return 0, false
+	}
+	return id, true
+}
+
+// slugFor returns the slug to store: the requested one, or one generated from the name.
+func slugFor(slug string, name string) (string, error) {
+	if slug == "" {
+		slug = name
+	}
+	if slug = utils.Slugify(slug); slug == "" {
+		return "", errEmptySlug
+	}
+	return slug, nil
+}
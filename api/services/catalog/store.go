@@ -0,0 +1,382 @@
+package catalog
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/types"
+)
+
+const categoryColumns = "category_id, parent_id, name, slug, created_at"
+
+const collectionColumns = "collection_id, name, slug, description, created_at"
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) types.CatalogStore {
+	return &Store{db: db}
+}
+
+// GetCategories retrieves every category, sorted by name. Clients build the hierarchy from the parent IDs.
+func (s *Store) GetCategories() ([]types.Category, error) {
+	rows, err := s.db.Query("SELECT " + categoryColumns + " FROM categories ORDER BY name ASC, category_id ASC")
+	if err != nil {
+		log.Printf("Error fetching categories: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]types.Category, 0)
+	for rows.Next() {
+		category, err := scanRowIntoCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, *category)
+	}
+	return categories, nil
+}
+
+func (s *Store) GetCategoryByID(categoryID int) (*types.Category, error) {
+	category, err := scanRowIntoCategory(s.db.QueryRow("SELECT "+categoryColumns+" FROM categories WHERE category_id = $1", categoryID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching category ID %v: %v", categoryID, err)
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *Store) CreateCategory(category types.Category) (categoryID int, err error) {
+	err = s.db.QueryRow(
+		"INSERT INTO categories (parent_id, name, slug) VALUES ($1, $2, $3) RETURNING category_id",
+		category.ParentID, category.Name, category.Slug,
+	).Scan(&categoryID)
+	if err != nil {
+		if mapped := mapCategoryError(err); mapped != nil {
+			return 0, mapped
+		}
+		log.Printf("Error creating category: %v", err)
+		return 0, err
+	}
+	return categoryID, nil
+}
+
+// UpdateCategory updates a category. Returns `types.ErrCategoryCycle` if the new parent is the category itself or one
+// of its subcategories.
+func (s *Store) UpdateCategory(category types.Category) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if category.ParentID != nil {
+		// Locking the categories serializes concurrent moves, otherwise two categories could be moved under each other.
+		// Categories can still be referenced (e.g. by socks) in the meantime.
+		_, err = tx.Exec("SELECT 1 FROM categories FOR NO KEY UPDATE")
+		if err != nil {
+			log.Printf("Error locking categories: %v", err)
+			return err
+		}
+
+		// The new parent must not be the category itself or one of its descendants
+		var isDescendant bool
+		err = tx.QueryRow(`
+      WITH RECURSIVE subtree AS (
+        SELECT category_id FROM categories WHERE category_id = $1
+        UNION
+        SELECT c.category_id FROM categories c JOIN subtree st ON c.parent_id = st.category_id
+      )
+      SELECT EXISTS (SELECT 1 FROM subtree WHERE category_id = $2)
+    `, category.ID, *category.ParentID).Scan(&isDescendant)
+		if err != nil {
+			log.Printf("Error checking the subcategories of category ID %v: %v", category.ID, err)
+			return err
+		}
+		if isDescendant {
+			err = types.ErrCategoryCycle
+			return err
+		}
+	}
+
+	_, err = tx.Exec(
+		"UPDATE categories SET parent_id = $1, name = $2, slug = $3 WHERE category_id = $4",
+		category.ParentID, category.Name, category.Slug, category.ID,
+	)
+	if err != nil {
+		if mapped := mapCategoryError(err); mapped != nil {
+			err = mapped
+			return err
+		}
+		log.Printf("Error updating category ID %v: %v", category.ID, err)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) DeleteCategory(categoryID int) error {
+	_, err := s.db.Exec("DELETE FROM categories WHERE category_id = $1", categoryID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return types.ErrCategoryHasChildren
+		}
+		log.Printf("Error deleting category ID %v: %v", categoryID, err)
+		return err
+	}
+	return nil
+}
+
+// mapCategoryError maps constraint violations on categories to their API error, or returns nil.
+func mapCategoryError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+	switch pqErr.Code {
+	case "23505":
+		return types.ErrSlugAlreadyExists
+	case "23503":
+		return types.ErrCategoryNotFound
+	default:
+		return nil
+	}
+}
+
+// GetTags retrieves every tag with the number of socks using it, sorted by name.
+func (s *Store) GetTags() ([]types.Tag, error) {
+	rows, err := s.db.Query(`
+    SELECT t.tag_id, t.name, COUNT(st.sock_id)
+    FROM tags t
+    LEFT JOIN sock_tags st ON st.tag_id = t.tag_id
+    GROUP BY t.tag_id
+    ORDER BY t.name ASC
+  `)
+	if err != nil {
+		log.Printf("Error fetching tags: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]types.Tag, 0)
+	for rows.Next() {
+		var tag types.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.SockCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (s *Store) GetTagByID(tagID int) (*types.Tag, error) {
+	var tag types.Tag
+	err := s.db.QueryRow(`
+    SELECT t.tag_id, t.name, (SELECT COUNT(*) FROM sock_tags st WHERE st.tag_id = t.tag_id)
+    FROM tags t
+    WHERE t.tag_id = $1
+  `, tagID).Scan(&tag.ID, &tag.Name, &tag.SockCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching tag ID %v: %v", tagID, err)
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (s *Store) RenameTag(tagID int, name string) error {
+	_, err := s.db.Exec("UPDATE tags SET name = $1 WHERE tag_id = $2", name, tagID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return types.ErrTagAlreadyExists
+		}
+		log.Printf("Error renaming tag ID %v: %v", tagID, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteTag deletes a tag and removes it from every sock.
+func (s *Store) DeleteTag(tagID int) error {
+	if _, err := s.db.Exec("DELETE FROM tags WHERE tag_id = $1", tagID); err != nil {
+		log.Printf("Error deleting tag ID %v: %v", tagID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) GetCollections() ([]types.Collection, error) {
+	rows, err := s.db.Query("SELECT " + collectionColumns + " FROM collections ORDER BY name ASC, collection_id ASC")
+	if err != nil {
+		log.Printf("Error fetching collections: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := make([]types.Collection, 0)
+	for rows.Next() {
+		collection, err := scanRowIntoCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, *collection)
+	}
+	return collections, nil
+}
+
+// GetCollectionByID retrieves a collection along with the IDs of its socks in display order.
+func (s *Store) GetCollectionByID(collectionID int) (*types.Collection, error) {
+	collection, err := scanRowIntoCollection(s.db.QueryRow("SELECT "+collectionColumns+" FROM collections WHERE collection_id = $1", collectionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching collection ID %v: %v", collectionID, err)
+		return nil, err
+	}
+
+	rows, err := s.db.Query("SELECT sock_id FROM collection_socks WHERE collection_id = $1 ORDER BY position ASC", collectionID)
+	if err != nil {
+		log.Printf("Error fetching socks of collection ID %v: %v", collectionID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	collection.SockIDs = make([]int, 0)
+	for rows.Next() {
+		var sockID int
+		if err := rows.Scan(&sockID); err != nil {
+			return nil, err
+		}
+		collection.SockIDs = append(collection.SockIDs, sockID)
+	}
+	return collection, nil
+}
+
+func (s *Store) CreateCollection(collection types.Collection) (collectionID int, err error) {
+	err = s.db.QueryRow(
+		"INSERT INTO collections (name, slug, description) VALUES ($1, $2, $3) RETURNING collection_id",
+		collection.Name, collection.Slug, collection.Description,
+	).Scan(&collectionID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, types.ErrSlugAlreadyExists
+		}
+		log.Printf("Error creating collection: %v", err)
+		return 0, err
+	}
+	return collectionID, nil
+}
+
+func (s *Store) UpdateCollection(collection types.Collection) error {
+	_, err := s.db.Exec(
+		"UPDATE collections SET name = $1, slug = $2, description = $3 WHERE collection_id = $4",
+		collection.Name, collection.Slug, collection.Description, collection.ID,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return types.ErrSlugAlreadyExists
+		}
+		log.Printf("Error updating collection ID %v: %v", collection.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) DeleteCollection(collectionID int) error {
+	if _, err := s.db.Exec("DELETE FROM collections WHERE collection_id = $1", collectionID); err != nil {
+		log.Printf("Error deleting collection ID %v: %v", collectionID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) SetCollectionSocks(collectionID int, sockIDs []int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec("DELETE FROM collection_socks WHERE collection_id = $1", collectionID); err != nil {
+		log.Printf("Error clearing socks of collection ID %v: %v", collectionID, err)
+		return err
+	}
+
+	for position, sockID := range sockIDs {
+		_, err = tx.Exec("INSERT INTO collection_socks (collection_id, sock_id, position) VALUES ($1, $2, $3)", collectionID, sockID, position)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				err = fmt.Errorf("%w: %v", types.ErrSockNotFound, sockID)
+				return err
+			}
+			log.Printf("Error adding sock ID %v to collection ID %v: %v", sockID, collectionID, err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRowIntoCategory(row rowScanner) (*types.Category, error) {
+	category := &types.Category{}
+	if err := row.Scan(&category.ID, &category.ParentID, &category.Name, &category.Slug, &category.CreatedAt); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func scanRowIntoCollection(row rowScanner) (*types.Collection, error) {
+	collection := &types.Collection{}
+	if err := row.Scan(&collection.ID, &collection.Name, &collection.Slug, &collection.Description, &collection.CreatedAt); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
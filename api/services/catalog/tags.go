@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// @Summary Get all tags
+// @Description Returns every tag sorted by name, with the number of socks using it.
+// @Tags Catalog
+// @Produce json
+// @Success 200 {array} types.Tag
+// @Router /tags [get]
+func (h *Handler) handleGetTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.store.GetTags()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, tags)
+}
+
+// @Summary Rename a tag
+// @Description Renames a tag on every sock using it. Tag names are stored lowercase. Returns 409 if another tag already has the name.
+// @Tags Catalog
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param tag_id path int true "Tag ID"
+// @Param tag body types.RenameTagRequest true "Tag"
+// @Success 200 {object} types.Tag
+// @Router /tags/{tag_id} [patch]
+func (h *Handler) handleRenameTag(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := pathID(w, r, "tag_id")
+	if !ok {
+		return
+	}
+
+	var req types.RenameTagRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	name := utils.Normalize(req.Name)
+	if name == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("tag name cannot be blank"))
+		return
+	}
+
+	tag, err := h.store.GetTagByID(tagID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tag == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("tag not found"))
+		return
+	}
+
+	if err := h.store.RenameTag(tagID, name); err != nil {
+		if errors.Is(err, types.ErrTagAlreadyExists) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	tag.Name = name
+	utils.WriteJson(w, http.StatusOK, tag)
+}
+
+// @Summary Delete a tag
+// @Description Deletes a tag and removes it from every sock.
+// @Tags Catalog
+// @Produce json
+// @Security Bearer
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} types.Message
+// @Router /tags/{tag_id} [delete]
+func (h *Handler) handleDeleteTag(w http.ResponseWriter, r *http.Request) {
+	tagID, ok := pathID(w, r, "tag_id")
+	if !ok {
+		return
+	}
+
+	tag, err := h.store.GetTagByID(tagID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if tag == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("tag not found"))
+		return
+	}
+
+	if err := h.store.DeleteTag(tagID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Tag deleted successfully"})
+}
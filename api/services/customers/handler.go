@@ -0,0 +1,205 @@
+package customers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+	"github.com/sockify/sockify/utils/auth"
+)
+
+type Handler struct {
+	store      types.CustomerStore
+	orderStore types.OrderStore
+}
+
+func NewHandler(store types.CustomerStore, orderStore types.OrderStore) *Handler {
+	return &Handler{store: store, orderStore: orderStore}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/customers/register", h.handleRegister).Methods(http.MethodPost)
+	router.HandleFunc("/customers/login", h.handleLogin).Methods(http.MethodPost)
+	router.HandleFunc("/customers/me", middleware.WithCustomerAuth(h.store, h.handleGetMe)).Methods(http.MethodGet)
+	router.HandleFunc("/customers/me/orders", middleware.WithCustomerAuth(h.store, h.handleGetMyOrders)).Methods(http.MethodGet)
+}
+
+// @Summary Registers a customer account
+// @Description Creates a new customer account and logs the customer in. The returned token can only be used on customer endpoints.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param Body body types.RegisterCustomerRequest true "Customer details"
+// @Success 201 {object} types.LoginCustomerResponse
+// @Router /customers/register [post]
+func (h *Handler) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var payload types.RegisterCustomerRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := auth.ValidatePassword(payload.Password); err != nil {
+		utils.WriteError(w, http.StatusBadRequest,
+			fmt.Errorf("password does not meet the minimum requirements: %v", err),
+		)
+		return
+	}
+
+	email := utils.Normalize(payload.Email)
+	existing, err := h.store.GetCustomerByEmail(email)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if existing != nil {
+		utils.WriteError(w, http.StatusConflict, types.ErrCustomerAlreadyExists)
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(payload.Password)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to hash password: %v", err))
+		return
+	}
+
+	customer := types.Customer{
+		FirstName:    utils.TitleCase(payload.FirstName),
+		LastName:     utils.TitleCase(payload.LastName),
+		Email:        email,
+		PasswordHash: passwordHash,
+	}
+	if phone := strings.TrimSpace(payload.Phone); phone != "" {
+		customer.Phone = &phone
+	}
+
+	customerID, err := h.store.CreateCustomer(customer)
+	if err != nil {
+		if errors.Is(err, types.ErrCustomerAlreadyExists) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to create the customer account: %v", err))
+		return
+	}
+
+	created, err := h.store.GetCustomerByID(customerID)
+	if err != nil || created == nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to retrieve the new customer account: %v", err))
+		return
+	}
+
+	h.writeLoginResponse(w, http.StatusCreated, *created)
+}
+
+// @Summary Logs in a customer
+// @Description Logs in a customer using email and password credentials. The returned token can only be used on customer endpoints.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param Body body types.LoginCustomerRequest true "Login credentials"
+// @Success 200 {object} types.LoginCustomerResponse
+// @Router /customers/login [post]
+func (h *Handler) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var payload types.LoginCustomerRequest
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	customer, err := h.store.GetCustomerByEmail(utils.Normalize(payload.Email))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if customer == nil || !auth.ComparePasswords(customer.PasswordHash, payload.Password) {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("invalid email or password"))
+		return
+	}
+
+	h.writeLoginResponse(w, http.StatusOK, *customer)
+}
+
+// @Summary Get the logged in customer
+// @Description Retrieves the account details of the logged in customer.
+// @Tags Customers
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} types.Customer
+// @Router /customers/me [get]
+func (h *Handler) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	customerID, _ := middleware.GetCustomerIDFromContext(r.Context())
+
+	customer, err := h.store.GetCustomerByID(customerID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if customer == nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("customer with ID %v does not exist", customerID))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, customer)
+}
+
+// @Summary Get the order history of the logged in customer
+// @Description Retrieves the orders placed by the logged in customer, newest first.
+// @Tags Customers
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Limit the number of results" default(20)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} types.OrdersPaginatedResponse
+// @Router /customers/me/orders [get]
+func (h *Handler) handleGetMyOrders(w http.ResponseWriter, r *http.Request) {
+	limit, offset := utils.GetLimitOffset(r, 20, 0)
+	customerID, _ := middleware.GetCustomerIDFromContext(r.Context())
+
+	filters := types.OrderFilters{CustomerID: &customerID, SortDirection: "desc"}
+	orders, err := h.orderStore.GetOrders(limit, offset, filters)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	total, err := h.orderStore.CountOrders(filters)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.OrdersPaginatedResponse{
+		Items:  orders,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (h *Handler) writeLoginResponse(w http.ResponseWriter, status utils.HttpStatus, customer types.Customer) {
+	token, err := auth.CreateJWTToken([]byte(config.Envs.JWTSecret), customer.ID, auth.ScopeCustomer)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, status, types.LoginCustomerResponse{Token: token, Customer: customer})
+}
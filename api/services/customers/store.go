@@ -0,0 +1,78 @@
+package customers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/types"
+)
+
+const customerColumns = "customer_id, firstname, lastname, email, phone, password_hash, created_at"
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) types.CustomerStore {
+	return &Store{db: db}
+}
+
+func (s *Store) GetCustomerByID(id int) (*types.Customer, error) {
+	customer, err := scanRowIntoCustomer(s.db.QueryRow("SELECT "+customerColumns+" FROM customers WHERE customer_id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching customer ID %v: %v", id, err)
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (s *Store) GetCustomerByEmail(email string) (*types.Customer, error) {
+	customer, err := scanRowIntoCustomer(s.db.QueryRow("SELECT "+customerColumns+" FROM customers WHERE email = $1", email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching customer by email: %v", err)
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (s *Store) CreateCustomer(customer types.Customer) (customerID int, err error) {
+	err = s.db.QueryRow(`
+    INSERT INTO customers (firstname, lastname, email, phone, password_hash)
+    VALUES ($1, $2, $3, $4, $5)
+    RETURNING customer_id
+  `, customer.FirstName, customer.LastName, customer.Email, customer.Phone, customer.PasswordHash).Scan(&customerID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return 0, types.ErrCustomerAlreadyExists
+		}
+		log.Printf("Error creating customer: %v", err)
+		return 0, err
+	}
+	return customerID, nil
+}
+
+func scanRowIntoCustomer(row *sql.Row) (*types.Customer, error) {
+	customer := &types.Customer{}
+	err := row.Scan(
+		&customer.ID,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.Email,
+		&customer.Phone,
+		&customer.PasswordHash,
+		&customer.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
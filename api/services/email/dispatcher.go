@@ -0,0 +1,102 @@
+package email
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+const (
+	outboxBatchSize   = 20
+	outboxMaxAttempts = 8
+	// How long a claimed email stays hidden from other dispatchers while it is being sent
+	outboxLease       = 2 * time.Minute
+	outboxBaseBackoff = 30 * time.Second
+	outboxMaxBackoff  = 6 * time.Hour
+)
+
+// OutboxDispatcher periodically delivers queued emails from the outbox, retrying failures with exponential backoff.
+type OutboxDispatcher struct {
+	store    types.EmailOutboxStore
+	service  *Service
+	interval time.Duration
+}
+
+func NewOutboxDispatcher(store types.EmailOutboxStore, service *Service, interval time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{store: store, service: service, interval: interval}
+}
+
+// Run dispatches pending emails every interval until the context is canceled.
+func (d *OutboxDispatcher) Run(ctx context.Context) {
+	log.Printf("Email outbox dispatcher started (interval: %v)", d.interval)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Email outbox dispatcher stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		emails, err := d.store.ClaimPendingEmails(outboxBatchSize, outboxLease)
+		if err != nil {
+			log.Printf("Email outbox dispatcher was unable to claim pending emails: %v", err)
+			return
+		}
+
+		for _, e := range emails {
+			d.send(e)
+		}
+
+		if len(emails) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+func (d *OutboxDispatcher) send(e types.OutboxEmail) {
+	sendErr := d.service.SendEmail(e.OutboundEmail)
+	if sendErr == nil {
+		if err := d.store.MarkEmailSent(e.ID); err != nil {
+			log.Printf("Email outbox dispatcher sent email ID %v but could not mark it as sent: %v", e.ID, err)
+		}
+		return
+	}
+
+	attempts := e.Attempts + 1
+	var nextAttemptAt *time.Time
+	if attempts < outboxMaxAttempts {
+		retryAt := time.Now().Add(outboxBackoff(attempts))
+		nextAttemptAt = &retryAt
+		log.Printf("Unable to send email ID %v (attempt %v), retrying at %v: %v", e.ID, attempts, retryAt.Format(time.RFC3339), sendErr)
+	} else {
+		log.Printf("[ERROR] giving up on email ID %v to %v after %v attempts: %v", e.ID, e.ToEmail, attempts, sendErr)
+	}
+
+	if err := d.store.MarkEmailFailed(e.ID, sendErr, nextAttemptAt); err != nil {
+		log.Printf("Email outbox dispatcher was unable to record the failure for email ID %v: %v", e.ID, err)
+	}
+}
+
+// outboxBackoff doubles the wait after every failed attempt, up to `outboxMaxBackoff`.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,87 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"time"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// Mailer delivers rendered emails through a specific transport.
+type Mailer interface {
+	Send(email types.OutboundEmail) error
+}
+
+// NewMailer returns the mailer configured through `EMAIL_TRANSPORT`. Unknown transports are rejected, so a typo
+// never silently sends emails through SendGrid.
+func NewMailer() (Mailer, error) {
+	switch config.Envs.EmailTransport {
+	case "sendgrid":
+		return NewSendGridMailer(config.Envs.SendGridAPIKey), nil
+	case "smtp":
+		log.Printf("Sending emails through SMTP server %v:%v", config.Envs.SMTPHost, config.Envs.SMTPPort)
+		return NewSMTPMailer(config.Envs.SMTPHost, config.Envs.SMTPPort, config.Envs.SMTPUsername, config.Envs.SMTPPassword), nil
+	case "file":
+		log.Printf("Writing emails as .eml files to %v instead of sending them", config.Envs.EmailOutputDir)
+		return NewFileMailer(config.Envs.EmailOutputDir), nil
+	case "memory":
+		log.Println("Keeping emails in memory instead of sending them")
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_TRANSPORT %q, expected \"sendgrid\", \"smtp\", \"file\" or \"memory\"", config.Envs.EmailTransport)
+	}
+}
+
+// buildMIMEMessage renders the email as a multipart/alternative RFC 5322 message with plain text and HTML parts.
+func buildMIMEMessage(email types.OutboundEmail) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	from := mail.Address{Name: utils.EmailSenderName, Address: utils.NoReplyEmailAddress}
+	to := mail.Address{Name: email.ToName, Address: email.ToEmail}
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", email.PlainText},
+		{"text/html; charset=utf-8", email.HTMLContent},
+	}
+	for _, p := range parts {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(p.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,230 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+func testEmail() types.OutboundEmail {
+	return types.OutboundEmail{
+		ToName:    "Zoë Doe",
+		ToEmail:   "zoe+orders@example.com",
+		Subject:   "Your order has been shipped ✓ (inv-42)",
+		PlainText: "Hello Zoë,\n\n" + strings.Repeat("Your socks are on their way. ", 10),
+		// Long enough to be wrapped, with characters that must be escaped in quoted-printable
+		HTMLContent: `<p style="color: #333;">Hello Zoë, ` + strings.Repeat("your socks are on their way. ", 10) + `</p>`,
+	}
+}
+
+// parseMIMEMessage parses the message and returns its headers and the decoded body of each part by content type.
+func parseMIMEMessage(t *testing.T, raw []byte) (*mail.Message, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("unable to parse the message: %v", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("expected a multipart/alternative message, got %q: %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	bodies := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unable to read part: %v", err)
+		}
+		// The quoted-printable body is decoded by the reader. Text line breaks are sent as CRLF.
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("unable to read part: %v", err)
+		}
+		bodies[part.Header.Get("Content-Type")] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+	return msg, bodies
+}
+
+func TestBuildMIMEMessage(t *testing.T) {
+	email := testEmail()
+	raw, err := buildMIMEMessage(email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 5322 limits lines to 998 characters, quoted-printable wraps them at 76
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 998 {
+			t.Errorf("line is too long (%d characters): %.40q...", len(line), line)
+		}
+	}
+
+	msg, bodies := parseMIMEMessage(t, raw)
+
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) != 1 || from[0].Address != utils.NoReplyEmailAddress || from[0].Name != utils.EmailSenderName {
+		t.Errorf("unexpected From header %q: %v", msg.Header.Get("From"), err)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 1 || to[0].Address != email.ToEmail || to[0].Name != email.ToName {
+		t.Errorf("unexpected To header %q: %v", msg.Header.Get("To"), err)
+	}
+	if raw := msg.Header.Get("Subject"); !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Errorf("expected the subject to be Q-encoded, got %q", raw)
+	}
+	if subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); err != nil || subject != email.Subject {
+		t.Errorf("expected subject %q, got %q: %v", email.Subject, subject, err)
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("invalid Date header %q: %v", msg.Header.Get("Date"), err)
+	}
+	if v := msg.Header.Get("MIME-Version"); v != "1.0" {
+		t.Errorf("expected MIME-Version 1.0, got %q", v)
+	}
+
+	if body := bodies["text/plain; charset=utf-8"]; body != email.PlainText {
+		t.Errorf("expected plain text part %q, got %q", email.PlainText, body)
+	}
+	if body := bodies["text/html; charset=utf-8"]; body != email.HTMLContent {
+		t.Errorf("expected HTML part %q, got %q", email.HTMLContent, body)
+	}
+}
+
+func TestFileMailerWritesEmlFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "emails")
+	email := testEmail()
+
+	if err := NewFileMailer(dir).Send(email); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(entries))
+	}
+	// Characters that are unsafe in file names are replaced
+	if name := entries[0].Name(); !strings.HasSuffix(name, "_zoe_orders@example.com.eml") {
+		t.Errorf("unexpected file name %q", name)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, bodies := parseMIMEMessage(t, raw)
+	if body := bodies["text/plain; charset=utf-8"]; body != email.PlainText {
+		t.Errorf("expected the file to contain the email, got plain text part %q", body)
+	}
+}
+
+func TestSMTPMailerSendsMessage(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan []string, 1)
+	go serveFakeSMTP(listener, received)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	email := testEmail()
+	if err := NewSMTPMailer(host, port, "", "").Send(email); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := <-received
+	if !contains(commands, "MAIL FROM:<"+utils.NoReplyEmailAddress+">") {
+		t.Errorf("expected the email to be sent from %v, got %q", utils.NoReplyEmailAddress, commands)
+	}
+	if !contains(commands, "RCPT TO:<"+email.ToEmail+">") {
+		t.Errorf("expected the email to be sent to %v, got %q", email.ToEmail, commands)
+	}
+}
+
+// serveFakeSMTP accepts a single SMTP session and sends the commands it received once the client quits.
+func serveFakeSMTP(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	var commands []string
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		commands = append(commands, line)
+
+		switch {
+		case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+			reply("250 localhost")
+		case line == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			for {
+				data, err := r.ReadString('\n')
+				if err != nil || data == ".\r\n" {
+					break
+				}
+			}
+			reply("250 OK")
+		case line == "QUIT":
+			reply("221 bye")
+			received <- commands
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func contains(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewMailerRejectsUnknownTransport(t *testing.T) {
+	original := config.Envs.EmailTransport
+	t.Cleanup(func() { config.Envs.EmailTransport = original })
+
+	config.Envs.EmailTransport = "sendgird"
+	if _, err := NewMailer(); err == nil {
+		t.Error("expected an unknown transport to be rejected")
+	}
+
+	config.Envs.EmailTransport = "memory"
+	if _, err := NewMailer(); err != nil {
+		t.Errorf("expected the memory transport to be accepted, got %v", err)
+	}
+}
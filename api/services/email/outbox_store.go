@@ -0,0 +1,112 @@
+package email
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+const enqueueQuery = `
+  INSERT INTO email_outbox (to_name, to_email, subject, plain_text, html_content)
+  VALUES ($1, $2, $3, $4, $5)
+`
+
+type OutboxStore struct {
+	db *sql.DB
+}
+
+func NewOutboxStore(db *sql.DB) types.EmailOutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// EnqueueTx queues an email as part of an existing transaction, so the email is only sent if the transaction commits.
+func EnqueueTx(tx *sql.Tx, email types.OutboundEmail) error {
+	_, err := tx.Exec(enqueueQuery, email.ToName, email.ToEmail, email.Subject, email.PlainText, email.HTMLContent)
+	if err != nil {
+		log.Printf("Error queueing email '%v' to %v: %v", email.Subject, email.ToEmail, err)
+		return err
+	}
+	return nil
+}
+
+func (s *OutboxStore) Enqueue(email types.OutboundEmail) error {
+	_, err := s.db.Exec(enqueueQuery, email.ToName, email.ToEmail, email.Subject, email.PlainText, email.HTMLContent)
+	if err != nil {
+		log.Printf("Error queueing email '%v' to %v: %v", email.Subject, email.ToEmail, err)
+		return err
+	}
+	return nil
+}
+
+// ClaimPendingEmails returns up to `limit` emails that are due to be sent. Claimed emails are hidden from other
+// dispatchers for the duration of the lease, so multiple API instances never send the same email concurrently.
+func (s *OutboxStore) ClaimPendingEmails(limit int, lease time.Duration) ([]types.OutboxEmail, error) {
+	rows, err := s.db.Query(`
+    UPDATE email_outbox
+    SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'
+    WHERE email_outbox_id IN (
+      SELECT email_outbox_id
+      FROM email_outbox
+      WHERE sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP
+      ORDER BY next_attempt_at
+      LIMIT $1
+      FOR UPDATE SKIP LOCKED
+    )
+    RETURNING email_outbox_id, attempts, to_name, to_email, subject, plain_text, html_content
+  `, limit, lease.Seconds())
+	if err != nil {
+		log.Printf("Error claiming pending emails: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []types.OutboxEmail{}
+	for rows.Next() {
+		var e types.OutboxEmail
+		if err := rows.Scan(&e.ID, &e.Attempts, &e.ToName, &e.ToEmail, &e.Subject, &e.PlainText, &e.HTMLContent); err != nil {
+			log.Printf("Error scanning outbox email: %v", err)
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+
+	return emails, rows.Err()
+}
+
+func (s *OutboxStore) MarkEmailSent(emailID int) error {
+	_, err := s.db.Exec(`
+    UPDATE email_outbox
+    SET sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+    WHERE email_outbox_id = $1
+  `, emailID)
+	if err != nil {
+		log.Printf("Error marking outbox email ID %v as sent: %v", emailID, err)
+		return err
+	}
+	return nil
+}
+
+// MarkEmailFailed records a failed delivery attempt. The email is retried at `nextAttemptAt`, or abandoned when it is nil.
+func (s *OutboxStore) MarkEmailFailed(emailID int, sendErr error, nextAttemptAt *time.Time) error {
+	var err error
+	if nextAttemptAt == nil {
+		_, err = s.db.Exec(`
+      UPDATE email_outbox
+      SET attempts = attempts + 1, last_error = $1, failed_at = CURRENT_TIMESTAMP
+      WHERE email_outbox_id = $2
+    `, sendErr.Error(), emailID)
+	} else {
+		_, err = s.db.Exec(`
+      UPDATE email_outbox
+      SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2
+      WHERE email_outbox_id = $3
+    `, sendErr.Error(), nextAttemptAt.UTC(), emailID)
+	}
+	if err != nil {
+		log.Printf("Error marking outbox email ID %v as failed: %v", emailID, err)
+		return err
+	}
+	return nil
+}
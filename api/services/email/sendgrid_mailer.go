@@ -0,0 +1,33 @@
+package email
+
+import (
+	"fmt"
+
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+type SendGridMailer struct {
+	client *sendgrid.Client
+}
+
+func NewSendGridMailer(apiKey string) *SendGridMailer {
+	return &SendGridMailer{client: sendgrid.NewSendClient(apiKey)}
+}
+
+func (m *SendGridMailer) Send(email types.OutboundEmail) error {
+	from := mail.NewEmail(utils.EmailSenderName, utils.NoReplyEmailAddress)
+	to := mail.NewEmail(email.ToName, email.ToEmail)
+	message := mail.NewSingleEmail(from, email.Subject, to, email.PlainText, email.HTMLContent)
+
+	res, err := m.client.Send(message)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode >= 400 {
+		return fmt.Errorf("sendgrid responded with status %v: %v", res.StatusCode, res.Body)
+	}
+	return nil
+}
@@ -1,38 +1,16 @@
 package email
 
-import (
-	"fmt"
-	"log"
-
-	"github.com/sendgrid/sendgrid-go"
-	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"github.com/sockify/sockify/services/email/templates"
-	"github.com/sockify/sockify/types"
-	"github.com/sockify/sockify/utils"
-)
+import "github.com/sockify/sockify/types"
 
 type Service struct {
-	client *sendgrid.Client
+	mailer Mailer
 }
 
-func NewService(client *sendgrid.Client) Service {
-	return Service{client: client}
+func NewService(mailer Mailer) Service {
+	return Service{mailer: mailer}
 }
 
-func (s *Service) SendOrderConfirmationEmail(toName string, toEmail string, order types.OrderConfirmation) error {
-	plainText, htmlContent := templates.CreateOrderConfirmationTemplate(order)
-
-	from := mail.NewEmail(utils.EmailSenderName, utils.NoReplyEmailAddress)
-	subject := fmt.Sprintf("Order confirmation (%s)", order.InvoiceNumber)
-	to := mail.NewEmail(toName, toEmail)
-	message := mail.NewSingleEmail(from, subject, to, plainText, htmlContent)
-
-	_, err := s.client.Send(message)
-	if err != nil {
-		log.Printf("Failed to send order confirmation for invoice: %s - Error: %v\n", order.InvoiceNumber, err)
-		return err
-	}
-
-	log.Printf("Sent email confirmation for invoice number: %v", order.InvoiceNumber)
-	return nil
+// SendEmail sends an already rendered email.
+func (s *Service) SendEmail(email types.OutboundEmail) error {
+	return s.mailer.Send(email)
 }
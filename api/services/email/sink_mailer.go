@@ -0,0 +1,68 @@
+package email
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+// FileMailer writes every email as an `.eml` file instead of sending it. The files open in any email client.
+type FileMailer struct {
+	dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{dir: dir}
+}
+
+func (m *FileMailer) Send(email types.OutboundEmail) error {
+	message, err := buildMIMEMessage(email)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s_%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), unsafeFilenameChars.ReplaceAllString(email.ToEmail, "_"))
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, message, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("Wrote email '%v' to %v", email.Subject, path)
+	return nil
+}
+
+// MemoryMailer keeps every email in memory instead of sending it.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []types.OutboundEmail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(email types.OutboundEmail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+// Sent returns a copy of all the emails "sent" so far, oldest first.
+func (m *MemoryMailer) Sent() []types.OutboundEmail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]types.OutboundEmail{}, m.sent...)
+}
@@ -0,0 +1,32 @@
+package email
+
+import (
+	"net"
+	"net/smtp"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// SMTPMailer sends emails through a plain SMTP server, e.g. Mailpit or MailHog during local development.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates an SMTP mailer. Authentication is skipped when no username is given.
+func NewSMTPMailer(host string, port string, username string, password string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: net.JoinHostPort(host, port), auth: auth}
+}
+
+func (m *SMTPMailer) Send(email types.OutboundEmail) error {
+	message, err := buildMIMEMessage(email)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, utils.NoReplyEmailAddress, []string{email.ToEmail}, message)
+}
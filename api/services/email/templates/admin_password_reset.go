@@ -0,0 +1,50 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+const AdminPasswordResetSubject = "Reset your Sockify admin password"
+
+func CreateAdminPasswordResetTemplate(admin types.Admin, resetURL string, expiresIn time.Duration) (plainText string, htmlContent string) {
+	plainText = fmt.Sprintf(`
+  Hello %s,
+
+  We received a request to reset the password of your Sockify admin account (%s).
+
+  Use the link below to choose a new password. The link expires in %v minutes and can only be used once:
+  %s
+
+  If you did not request a password reset, you can ignore this email and your password will stay the same.
+
+  Best regards,
+  Sockify team`,
+		admin.FirstName,
+		admin.Username,
+		int(expiresIn.Minutes()),
+		resetURL,
+	)
+
+	htmlContent = fmt.Sprintf(`<html>
+  <body>
+    <h2>Reset your password</h2>
+    <p>Hello %s,</p>
+    <p>We received a request to reset the password of your Sockify admin account (<strong>%s</strong>).</p>
+    <p>Use the link below to choose a new password. The link expires in %v minutes and can only be used once.</p>
+    <p><a href="%s">Reset my password</a></p>
+    <p>If you did not request a password reset, you can ignore this email and your password will stay the same.</p>
+    <p>Best regards,<br>Sockify team</p>
+  </body>
+</html>`,
+		html.EscapeString(admin.FirstName),
+		html.EscapeString(admin.Username),
+		int(expiresIn.Minutes()),
+		html.EscapeString(resetURL),
+	)
+
+	return plainText, htmlContent
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+)
+
+func LowStockDigestSubject(variants []types.LowStockVariant) string {
+	return fmt.Sprintf("Low stock: %d sock variant(s) below their reorder threshold", len(variants))
+}
+
+func CreateLowStockDigestTemplate(admin types.Admin, variants []types.LowStockVariant) (plainText string, htmlContent string) {
+	plainText = fmt.Sprintf(`
+  Hello %s,
+
+  The following sock variants dropped below their reorder threshold:
+  %s
+  You will not be alerted about these variants again until they are restocked.
+
+  Best regards,
+  Sockify team`,
+		admin.FirstName,
+		formatLowStockPlain(variants),
+	)
+
+	htmlContent = fmt.Sprintf(`<html>
+  <body>
+    <h2>Low stock</h2>
+    <p>Hello %s,</p>
+    <p>The following sock variants dropped below their reorder threshold:</p>
+    <table style="width:100%%; border-collapse: collapse;">
+      <thead>
+        <tr>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Sock</th>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Variant</th>
+          <th style="text-align: right; padding: 8px; border: 1px solid #ddd;">Quantity</th>
+          <th style="text-align: right; padding: 8px; border: 1px solid #ddd;">Reorder threshold</th>
+        </tr>
+      </thead>
+      <tbody>
+        %s
+      </tbody>
+    </table>
+    <p>You will not be alerted about these variants again until they are restocked.</p>
+    <p>Best regards,<br>Sockify team</p>
+  </body>
+</html>`,
+		html.EscapeString(admin.FirstName),
+		formatLowStockHTML(variants),
+	)
+
+	return plainText, htmlContent
+}
+
+func formatLowStockPlain(variants []types.LowStockVariant) string {
+	result := make([]string, 0)
+	for _, v := range variants {
+		name := v.SockName
+		if v.Description != "" {
+			name += " (" + v.Description + ")"
+		}
+		result = append(result, fmt.Sprintf("- %s: %d left, reorder threshold %d\n", name, v.Quantity, v.ReorderThreshold))
+	}
+	return strings.Join(result, "")
+}
+
+func formatLowStockHTML(variants []types.LowStockVariant) string {
+	result := make([]string, 0)
+	for _, v := range variants {
+		result = append(result, fmt.Sprintf(`<tr>
+          <td style="padding: 8px; border: 1px solid #ddd;">%s</td>
+          <td style="padding: 8px; border: 1px solid #ddd;">%s</td>
+          <td style="padding: 8px; border: 1px solid #ddd; text-align: right;">%d</td>
+          <td style="padding: 8px; border: 1px solid #ddd; text-align: right;">%d</td>
+        </tr>`, html.EscapeString(v.SockName), html.EscapeString(v.Description), v.Quantity, v.ReorderThreshold))
+	}
+	return strings.Join(result, "")
+}
@@ -66,7 +66,7 @@ func CreateOrderConfirmationTemplate(order types.OrderConfirmation) (plainText s
       <thead>
         <tr>
           <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Item</th>
-          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Size</th>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Variant</th>
           <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Quantity</th>
           <th style="text-align: right; padding: 8px; border: 1px solid #ddd;">Price</th>
         </tr>
@@ -100,7 +100,11 @@ func CreateOrderConfirmationTemplate(order types.OrderConfirmation) (plainText s
 func formatItemsPlain(items []types.OrderItem) string {
 	result := make([]string, 0)
 	for _, item := range items {
-		result = append(result, fmt.Sprintf("- %s (Size: %s) x%d - $%.2f\n", item.Name, item.Size, item.Quantity, item.Price))
+		name := item.Name
+		if item.VariantDescription != "" {
+			name += " (" + item.VariantDescription + ")"
+		}
+		result = append(result, fmt.Sprintf("- %s x%d - $%.2f\n", name, item.Quantity, item.Price))
 	}
 	return strings.Join(result, "")
 }
@@ -113,7 +117,7 @@ func formatItemsHTML(items []types.OrderItem) string {
           <td style="padding: 8px; border: 1px solid #ddd;">%s</td>
           <td style="padding: 8px; border: 1px solid #ddd;">%d</td>
           <td style="padding: 8px; border: 1px solid #ddd; text-align: right;">$%.2f</td>
-        </tr>`, item.Name, item.Size, item.Quantity, item.Price))
+        </tr>`, item.Name, item.VariantDescription, item.Quantity, item.Price))
 	}
 	return strings.Join(result, "")
 }
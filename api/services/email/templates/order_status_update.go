@@ -0,0 +1,109 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+)
+
+// Headline and explanation shown for each status that customers are notified about.
+var statusUpdateCopy = map[string][2]string{
+	"shipped":   {"Your order is on its way!", "Good news, your order has been shipped."},
+	"delivered": {"Your order has been delivered!", "Your order has been delivered. We hope you enjoy your new socks!"},
+	"canceled":  {"Your order has been canceled", "Your order has been canceled. If you were charged, the payment will be refunded."},
+	"returned":  {"Your return has been processed", "We received your returned items and your return has been processed."},
+}
+
+// HasOrderStatusUpdateTemplate reports whether customers are emailed when an order moves to the status.
+func HasOrderStatusUpdateTemplate(status string) bool {
+	_, ok := statusUpdateCopy[status]
+	return ok
+}
+
+func OrderStatusUpdateSubject(invoiceNumber string, status string) string {
+	return fmt.Sprintf("%s (%s)", statusUpdateCopy[status][0], invoiceNumber)
+}
+
+func CreateOrderStatusUpdateTemplate(order types.Order, newStatus string, message string) (plainText string, htmlContent string) {
+	headline, explanation := statusUpdateCopy[newStatus][0], statusUpdateCopy[newStatus][1]
+
+	// TODO: remove disclaimers if actual store is set up
+	plainText = fmt.Sprintf(`
+  DISCLAIMER: this is a test notification, the order won't be fulfilled!
+
+  Hello %s,
+
+  %s
+
+  Invoice #: %s
+  Status: %s
+
+  Message from our team:
+  %s
+
+  Items:
+  %s
+
+  Total: $%.2f
+
+  Thank you for shopping with us!
+
+  Best regards,
+  Sockify team`,
+		order.Contact.FirstName,
+		explanation,
+		order.InvoiceNumber,
+		newStatus,
+		message,
+		formatItemsPlain(order.Items),
+		order.Total,
+	)
+
+	htmlContent = fmt.Sprintf(`<html>
+  <body>
+    <h2>%s</h2>
+    <em>DISCLAIMER: this is a test notification, the order won't be fulfilled!</em>
+    <p>Hello %s,</p>
+    <p>%s</p>
+    <p><strong>Invoice #:</strong> %s</p>
+    <p><strong>Status:</strong> %s</p>
+
+    <h3>Message from our team:</h3>
+    <p>%s</p>
+
+    <h3>Items:</h3>
+    <table style="width:100%%; border-collapse: collapse;">
+      <thead>
+        <tr>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Item</th>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Variant</th>
+          <th style="text-align: left; padding: 8px; border: 1px solid #ddd;">Quantity</th>
+          <th style="text-align: right; padding: 8px; border: 1px solid #ddd;">Price</th>
+        </tr>
+      </thead>
+      <tbody>
+        %s
+      </tbody>
+    </table>
+
+    <h4>Total: $%.2f</h4>
+
+    <p>Thank you for shopping with us!</p>
+    <p>Best regards,<br>Sockify team</p>
+  </body>
+</html>`,
+		headline,
+		html.EscapeString(order.Contact.FirstName),
+		explanation,
+		order.InvoiceNumber,
+		newStatus,
+		// Admin messages are free text, so escape them and keep their line breaks
+		strings.ReplaceAll(html.EscapeString(message), "\n", "<br>"),
+		formatItemsHTML(order.Items),
+		order.Total,
+	)
+
+	return plainText, htmlContent
+}
@@ -0,0 +1,135 @@
+package inventory
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"net/http"
+
+	"github.com/sockify/sockify/types"
+)
+
+// maxImagePixels limits the dimensions of uploaded images, since a small file can decode into a huge image.
+const maxImagePixels = 40_000_000
+
+// imageExtensions are the supported image content types and the file extensions they are stored with.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+var errUnsupportedImageType = types.NewError("unsupported_image_type", "image must be a JPEG or PNG file")
+
+var errInvalidImage = types.NewError("invalid_image", "image could not be read, it may be corrupted")
+
+var errImageDimensionsTooLarge = types.NewError("image_too_large", "image dimensions are too large")
+
+// processedImage is a validated upload along with its thumbnail.
+type processedImage struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Thumbnail   []byte
+}
+
+// processImage checks that the data is a supported image, sniffing the content type instead of trusting the client,
+// and creates a thumbnail that fits within `thumbnailSize` pixels.
+func processImage(data []byte, thumbnailSize int) (*processedImage, error) {
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, errUnsupportedImageType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Error decoding uploaded image config: %v", err)
+		return nil, errInvalidImage
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil, errImageDimensionsTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Printf("Error decoding uploaded image: %v", err)
+		return nil, errInvalidImage
+	}
+
+	var thumbnail bytes.Buffer
+	resized := resizeToFit(img, thumbnailSize)
+	if contentType == "image/png" {
+		err = png.Encode(&thumbnail, resized)
+	} else {
+		err = jpeg.Encode(&thumbnail, resized, &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &processedImage{
+		ContentType: contentType,
+		Extension:   extension,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Thumbnail:   thumbnail.Bytes(),
+	}, nil
+}
+
+// resizeToFit scales an image down to fit within a square of the given size, keeping its aspect ratio.
+// Every pixel of the result is the average of the source pixels it covers, which avoids the aliasing of nearest neighbour scaling.
+func resizeToFit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > size || srcH > size {
+		if srcW >= srcH {
+			dstW, dstH = size, max(1, srcH*size/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*size/srcH), size
+		}
+	}
+
+	// Work on premultiplied RGBA pixels, image/draw converts common formats (e.g. JPEG's YCbCr) efficiently
+	src := image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+	if dstW == srcW && dstH == srcH {
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					b += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0], d[1], d[2], d[3] = uint8(r/n), uint8(g/n), uint8(b/n), uint8(a/n)
+		}
+	}
+	return dst
+}
+
+// isImageError reports whether an error from `processImage` is caused by the uploaded file.
+func isImageError(err error) bool {
+	return errors.Is(err, errInvalidImage) || errors.Is(err, errImageDimensionsTooLarge)
+}
@@ -0,0 +1,24 @@
+package inventory
+
+import (
+	"net/http"
+
+	"github.com/sockify/sockify/utils"
+)
+
+// @Summary Get the low-stock report
+// @Description Returns the active sock variants whose quantity is below their reorder threshold, the emptiest first. `alertedAt` is set once subscribed admins were emailed about the variant, and reset when it is restocked.
+// @Tags Inventory
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} types.LowStockVariant
+// @Router /inventory/low-stock [get]
+func (h *SockHandler) handleGetLowStockVariants(w http.ResponseWriter, r *http.Request) {
+	variants, err := h.store.GetLowStockVariants()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, variants)
+}
@@ -0,0 +1,61 @@
+package inventory
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+// LowStockMonitor periodically queues a digest of the variants that dropped below their reorder threshold to the
+// subscribed admins. Each variant is only included once until it is restocked.
+type LowStockMonitor struct {
+	store      types.SockStore
+	adminStore types.AdminStore
+	interval   time.Duration
+}
+
+func NewLowStockMonitor(store types.SockStore, adminStore types.AdminStore, interval time.Duration) *LowStockMonitor {
+	return &LowStockMonitor{store: store, adminStore: adminStore, interval: interval}
+}
+
+// Run checks for low stock every interval until the context is canceled.
+func (m *LowStockMonitor) Run(ctx context.Context) {
+	log.Printf("Low-stock monitor started (interval: %v)", m.interval)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.check()
+
+		select {
+		case <-ctx.Done():
+			log.Println("Low-stock monitor stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *LowStockMonitor) check() {
+	admins, err := m.adminStore.GetLowStockAlertSubscribers()
+	if err != nil {
+		log.Printf("Low-stock monitor was unable to fetch the subscribed admins: %v", err)
+		return
+	}
+	// Variants are left unclaimed, so the first admin to subscribe is alerted about them
+	if len(admins) == 0 {
+		return
+	}
+
+	variants, err := m.store.ClaimLowStockAlerts(admins)
+	if err != nil {
+		log.Printf("Low-stock monitor was unable to claim low-stock variants: %v", err)
+		return
+	}
+	if len(variants) > 0 {
+		log.Printf("Queued low-stock digest of %v variant(s) for %v admin(s)", len(variants), len(admins))
+	}
+}
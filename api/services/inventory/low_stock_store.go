@@ -0,0 +1,140 @@
+package inventory
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/types"
+)
+
+const lowStockColumns = "sv.sock_variant_id, sv.sock_id, s.name, sv.quantity, sv.reorder_threshold, sv.low_stock_alerted_at"
+
+// GetLowStockVariants returns the active variants below their reorder threshold, the emptiest first.
+func (s *SockStore) GetLowStockVariants() ([]types.LowStockVariant, error) {
+	rows, err := s.db.Query(`
+    SELECT ` + lowStockColumns + `
+    FROM sock_variants sv
+    JOIN socks s ON s.sock_id = sv.sock_id
+    WHERE sv.quantity < sv.reorder_threshold AND sv.is_deleted = false AND s.is_deleted = false
+    ORDER BY sv.quantity ASC, s.name ASC, sv.sock_variant_id ASC
+  `)
+	if err != nil {
+		log.Printf("Error fetching low-stock variants: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	return s.scanLowStockVariants(rows)
+}
+
+// ClaimLowStockAlerts marks the low-stock variants that were not alerted yet as alerted, queues a digest of them
+// for each subscriber and returns them. The digests are queued in the same transaction, so a variant is never
+// marked as alerted without its digest being delivered.
+// Concurrent claims never return the same variant, since the update locks the claimed rows.
+func (s *SockStore) ClaimLowStockAlerts(subscribers []types.Admin) (claimed []types.LowStockVariant, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return nil, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Also covers thresholds that were lowered below the current quantity
+	_, err = tx.Exec(`
+    UPDATE sock_variants
+    SET low_stock_alerted_at = NULL
+    WHERE low_stock_alerted_at IS NOT NULL AND quantity >= reorder_threshold
+  `)
+	if err != nil {
+		log.Printf("Error resetting low-stock alerts of restocked variants: %v", err)
+		return nil, err
+	}
+
+	rows, err := tx.Query(`
+    UPDATE sock_variants sv
+    SET low_stock_alerted_at = CURRENT_TIMESTAMP
+    FROM socks s
+    WHERE s.sock_id = sv.sock_id AND sv.low_stock_alerted_at IS NULL
+      AND sv.quantity < sv.reorder_threshold AND sv.is_deleted = false AND s.is_deleted = false
+    RETURNING ` + lowStockColumns)
+	if err != nil {
+		log.Printf("Error claiming low-stock alerts: %v", err)
+		return nil, err
+	}
+
+	claimed, err = s.scanLowStockVariants(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(claimed) > 0 {
+		for _, admin := range subscribers {
+			if err = email.EnqueueTx(tx, buildLowStockDigest(admin, claimed)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// resetLowStockAlertTx re-arms the low-stock alert of a variant once it is back at or above its reorder threshold.
+func resetLowStockAlertTx(tx *sql.Tx, sockVariantID int) error {
+	_, err := tx.Exec(`
+    UPDATE sock_variants
+    SET low_stock_alerted_at = NULL
+    WHERE sock_variant_id = $1 AND low_stock_alerted_at IS NOT NULL AND quantity >= reorder_threshold
+  `, sockVariantID)
+	if err != nil {
+		log.Printf("Error resetting low-stock alert of sock variant ID %v: %v", sockVariantID, err)
+		return err
+	}
+	return nil
+}
+
+// scanLowStockVariants reads the low-stock variants from the rows and sets their descriptions.
+func (s *SockStore) scanLowStockVariants(rows *sql.Rows) ([]types.LowStockVariant, error) {
+	variants := make([]types.LowStockVariant, 0)
+	for rows.Next() {
+		var v types.LowStockVariant
+		err := rows.Scan(&v.SockVariantID, &v.SockID, &v.SockName, &v.Quantity, &v.ReorderThreshold, &v.AlertedAt)
+		if err != nil {
+			log.Printf("Error scanning low-stock variant: %v", err)
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.SockVariantID
+	}
+
+	descriptions, err := s.GetVariantDescriptions(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range variants {
+		variants[i].Description = descriptions[variants[i].SockVariantID]
+	}
+
+	return variants, nil
+}
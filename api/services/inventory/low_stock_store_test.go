@@ -0,0 +1,65 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/sockify/sockify/database/dbtest"
+	"github.com/sockify/sockify/types"
+)
+
+func TestLowStockVariantIsAlertedOncePerShortage(t *testing.T) {
+	db := dbtest.New(t)
+	store := NewSockStore(db)
+	subscribers := []types.Admin{{ID: 1, FirstName: "Ada", LastName: "Admin", Email: "ada@example.com"}}
+
+	sockID, err := store.CreateSock(
+		types.Sock{Name: "Test sock", Description: "Warm", PreviewImageURL: "https://example.com/sock.png"},
+		[]types.SockVariant{{Options: map[string]string{"size": "M"}, Price: 9.99, Quantity: 2, ReorderThreshold: 5}},
+		types.SystemAdminID,
+	)
+	if err != nil {
+		t.Fatalf("unable to create sock: %v", err)
+	}
+	variants, err := store.GetSockVariants(sockID)
+	if err != nil || len(variants) != 1 {
+		t.Fatalf("unable to get the sock variant: %v", err)
+	}
+	variantID := variants[0].ID
+
+	claim := func(expectedVariants int, expectedQueued int) {
+		t.Helper()
+		claimed, err := store.ClaimLowStockAlerts(subscribers)
+		if err != nil {
+			t.Fatalf("unable to claim low-stock alerts: %v", err)
+		}
+		if len(claimed) != expectedVariants {
+			t.Errorf("expected %d variant(s) in the digest, got %+v", expectedVariants, claimed)
+		}
+
+		var queued int
+		if err := db.QueryRow("SELECT COUNT(*) FROM email_outbox WHERE to_email = 'ada@example.com'").Scan(&queued); err != nil {
+			t.Fatal(err)
+		}
+		if queued != expectedQueued {
+			t.Errorf("expected %d digest(s) to be queued, got %d", expectedQueued, queued)
+		}
+	}
+	adjust := func(delta int, reason string) {
+		t.Helper()
+		_, err := store.AdjustVariantStock(types.InventoryMovement{SockVariantID: variantID, Delta: delta, Reason: reason})
+		if err != nil {
+			t.Fatalf("unable to adjust stock: %v", err)
+		}
+	}
+
+	claim(1, 1)
+	// Already alerted, even if the stock keeps dropping
+	adjust(-1, types.MovementShrinkage)
+	claim(0, 1)
+
+	// Restocking re-arms the alert for the next shortage
+	adjust(10, types.MovementRestock)
+	claim(0, 1)
+	adjust(-10, types.MovementShrinkage)
+	claim(1, 2)
+}
@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/middleware"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// @Summary Get the stock movements of a sock variant
+// @Description Returns the stock ledger of a sock variant, newest first. Every change of quantity is recorded along with its reason (`sale`, `canceled`, `return`, `restock`, `shrinkage` or `correction`), the admin who made it and the related order, if any.
+// @Tags Inventory
+// @Produce json
+// @Security Bearer
+// @Param sock_id path int true "Sock ID"
+// @Param variant_id path int true "Sock variant ID"
+// @Param limit query int false "Number of movements to return" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} types.InventoryMovementsPaginatedResponse
+// @Router /socks/{sock_id}/variants/{variant_id}/movements [get]
+func (h *SockHandler) handleGetInventoryMovements(w http.ResponseWriter, r *http.Request) {
+	variant, ok := h.variantFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	limit, offset := utils.GetLimitOffset(r, 50, 0)
+
+	movements, err := h.store.GetInventoryMovements(variant.ID, limit, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	total, err := h.store.CountInventoryMovements(variant.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, types.InventoryMovementsPaginatedResponse{
+		Items:  movements,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// @Summary Adjust the stock of a sock variant
+// @Description Adds (positive delta) or removes (negative delta) stock from a sock variant and records the movement in its ledger. Manual adjustments must be a `restock`, `shrinkage` or `correction`. Adjustments that would make the quantity negative are rejected with a 409.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param sock_id path int true "Sock ID"
+// @Param variant_id path int true "Sock variant ID"
+// @Param adjustment body types.AdjustStockRequest true "Stock adjustment"
+// @Success 201 {object} types.InventoryMovement
+// @Router /socks/{sock_id}/variants/{variant_id}/movements [post]
+func (h *SockHandler) handleAdjustVariantStock(w http.ResponseWriter, r *http.Request) {
+	variant, ok := h.variantFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.AdjustStockRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	adminID := middleware.GetUserIDFromContext(r.Context())
+	movement, err := h.store.AdjustVariantStock(types.InventoryMovement{
+		SockVariantID: variant.ID,
+		Delta:         req.Delta,
+		Reason:        req.Reason,
+		AdminID:       adminRef(adminID),
+		Note:          req.Note,
+	})
+	if err != nil {
+		if errors.Is(err, types.ErrInsufficientStock) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, movement)
+}
+
+// variantFromRequest returns the variant of the sock from the request path, writing an error response otherwise.
+func (h *SockHandler) variantFromRequest(w http.ResponseWriter, r *http.Request) (*types.SockVariant, bool) {
+	vars := mux.Vars(r)
+	sockID, err := strconv.Atoi(vars["sock_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid sock ID"))
+		return nil, false
+	}
+
+	variantID, err := strconv.Atoi(vars["variant_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid sock variant ID"))
+		return nil, false
+	}
+
+	variant, err := h.store.GetSockVariantByID(variantID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if variant == nil || variant.SockID != sockID {
+		utils.WriteError(w, http.StatusNotFound, errors.New("sock variant not found"))
+		return nil, false
+	}
+
+	return variant, true
+}
@@ -0,0 +1,123 @@
+package inventory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/sockify/sockify/types"
+)
+
+// RecordMovementTx appends a movement to the stock ledger as part of an existing transaction.
+// It must be called in the same transaction as the quantity change it records. Restocked variants can be alerted
+// again the next time they run low.
+func RecordMovementTx(tx *sql.Tx, movement types.InventoryMovement) error {
+	_, err := tx.Exec(`
+		INSERT INTO inventory_movements (sock_variant_id, delta, quantity_after, reason, admin_id, order_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		movement.SockVariantID, movement.Delta, movement.QuantityAfter, movement.Reason, movement.AdminID, movement.OrderID, movement.Note)
+	if err != nil {
+		log.Printf("Error recording %v movement for sock variant ID %v: %v", movement.Reason, movement.SockVariantID, err)
+		return err
+	}
+
+	if movement.Delta > 0 {
+		return resetLowStockAlertTx(tx, movement.SockVariantID)
+	}
+	return nil
+}
+
+// AdjustVariantStock adds the delta of the movement to the stock of an active variant and records the movement.
+func (s *SockStore) AdjustVariantStock(movement types.InventoryMovement) (created *types.InventoryMovement, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return nil, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(`
+    UPDATE sock_variants
+    SET quantity = quantity + $1, version = version + 1
+    WHERE sock_variant_id = $2 AND quantity + $1 >= 0 AND is_deleted = false
+    RETURNING quantity
+  `, movement.Delta, movement.SockVariantID).Scan(&movement.QuantityAfter)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("%w for sock variant with ID %v", types.ErrInsufficientStock, movement.SockVariantID)
+			return nil, err
+		}
+		log.Printf("Error adjusting stock of sock variant ID %v: %v", movement.SockVariantID, err)
+		return nil, err
+	}
+
+	if err = RecordMovementTx(tx, movement); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return nil, err
+	}
+
+	return &movement, nil
+}
+
+// GetInventoryMovements retrieves the stock ledger of a variant with pagination, newest first.
+func (s *SockStore) GetInventoryMovements(sockVariantID int, limit int, offset int) ([]types.InventoryMovement, error) {
+	rows, err := s.db.Query(`
+    SELECT m.inventory_movement_id, m.sock_variant_id, m.delta, m.quantity_after, m.reason,
+      m.admin_id, a.username, m.order_id, m.note, m.created_at
+    FROM inventory_movements m
+    LEFT JOIN admins a ON a.admin_id = m.admin_id
+    WHERE m.sock_variant_id = $1
+    ORDER BY m.inventory_movement_id DESC
+    LIMIT $2 OFFSET $3
+  `, sockVariantID, limit, offset)
+	if err != nil {
+		log.Printf("Error fetching inventory movements for sock variant ID %v: %v", sockVariantID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := make([]types.InventoryMovement, 0)
+	for rows.Next() {
+		var m types.InventoryMovement
+		err := rows.Scan(&m.ID, &m.SockVariantID, &m.Delta, &m.QuantityAfter, &m.Reason,
+			&m.AdminID, &m.AdminUsername, &m.OrderID, &m.Note, &m.CreatedAt)
+		if err != nil {
+			log.Printf("Error scanning inventory movement: %v", err)
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
+
+func (s *SockStore) CountInventoryMovements(sockVariantID int) (int, error) {
+	var count int
+	err := s.db.QueryRow("SELECT COUNT(*) FROM inventory_movements WHERE sock_variant_id = $1", sockVariantID).Scan(&count)
+	if err != nil {
+		log.Printf("Error counting inventory movements for sock variant ID %v: %v", sockVariantID, err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// adminRef returns the admin ID to record, or nil for the system.
+func adminRef(adminID int) *int {
+	if adminID == types.SystemAdminID {
+		return nil
+	}
+	return &adminID
+}
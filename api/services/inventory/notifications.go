@@ -0,0 +1,21 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/sockify/sockify/services/email/templates"
+	"github.com/sockify/sockify/types"
+)
+
+// buildLowStockDigest renders the digest sent to a subscribed admin about variants that dropped below their
+// reorder threshold.
+func buildLowStockDigest(admin types.Admin, variants []types.LowStockVariant) types.OutboundEmail {
+	plainText, htmlContent := templates.CreateLowStockDigestTemplate(admin, variants)
+	return types.OutboundEmail{
+		ToName:      fmt.Sprintf("%s %s", admin.FirstName, admin.LastName),
+		ToEmail:     admin.Email,
+		Subject:     templates.LowStockDigestSubject(variants),
+		PlainText:   plainText,
+		HTMLContent: htmlContent,
+	}
+}
@@ -2,9 +2,10 @@ package inventory
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
-
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/sockify/sockify/middleware"
@@ -14,24 +15,32 @@ import (
 
 type SockHandler struct {
 	store types.SockStore
+	blobs types.BlobStore
 }
 
-func NewSockHandler(store types.SockStore) *SockHandler {
-	return &SockHandler{store: store}
+func NewSockHandler(store types.SockStore, blobs types.BlobStore) *SockHandler {
+	return &SockHandler{store: store, blobs: blobs}
 }
 
 func (h *SockHandler) RegisterRoutes(router *mux.Router, adminStore types.AdminStore) {
-	router.HandleFunc("/socks", middleware.WithJWTAuth(adminStore, h.handleCreateSock)).Methods(http.MethodPost)
-	router.HandleFunc("/socks/{sock_id}", middleware.WithJWTAuth(adminStore, h.handleDeleteSock)).Methods(http.MethodDelete)
+	router.HandleFunc("/socks", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleCreateSock)).Methods(http.MethodPost)
+	router.HandleFunc("/socks/{sock_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteSock)).Methods(http.MethodDelete)
 	router.HandleFunc("/socks", h.handleGetAllSocks).Methods(http.MethodGet)
 	router.HandleFunc("/socks/{sock_id}", h.handleGetSockDetails).Methods(http.MethodGet)
-	router.HandleFunc("/socks/{sock_id}", middleware.WithJWTAuth(adminStore, h.handleUpdateSock)).Methods(http.MethodPatch)
+	router.HandleFunc("/socks/{sock_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUpdateSock)).Methods(http.MethodPatch)
 	router.HandleFunc("/socks/{sock_id}/similar-socks", h.handleGetSimilarSocks).Methods(http.MethodGet)
+	router.HandleFunc("/socks/{sock_id}/images", h.handleGetSockImages).Methods(http.MethodGet)
+	router.HandleFunc("/socks/{sock_id}/images", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleUploadSockImage)).Methods(http.MethodPost)
+	router.HandleFunc("/socks/{sock_id}/images", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleReorderSockImages)).Methods(http.MethodPatch)
+	router.HandleFunc("/socks/{sock_id}/images/{image_id}", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleDeleteSockImage)).Methods(http.MethodDelete)
+	router.HandleFunc("/socks/{sock_id}/variants/{variant_id}/movements", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetInventoryMovements)).Methods(http.MethodGet)
+	router.HandleFunc("/socks/{sock_id}/variants/{variant_id}/movements", middleware.WithRole(adminStore, middleware.InventoryManagers, h.handleAdjustVariantStock)).Methods(http.MethodPost)
+	router.HandleFunc("/inventory/low-stock", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetLowStockVariants)).Methods(http.MethodGet)
 }
 
 // CreateSock handles the HTTP request to create a new sock with its variants
 // @Summary Create a new sock
-// @Description Adds a new sock to the store with its variants
+// @Description Adds a new sock to the store with its variants. Variant options must be allowed values of existing attributes (see `GET /attributes`), and each combination of options can only be used once.
 // @Tags Inventory
 // @Accept json
 // @Produce json
@@ -70,8 +79,12 @@ func (h *SockHandler) handleCreateSock(w http.ResponseWriter, r *http.Request) {
 
 	sock := toSock(req.Sock)
 	variants := toSockVariantArray(req.Variants)
-	sockID, err := h.store.CreateSock(sock, variants)
+	sockID, err := h.store.CreateSock(sock, variants, middleware.GetUserIDFromContext(r.Context()))
 	if err != nil {
+		if isSockInputError(err) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -116,23 +129,39 @@ func (h *SockHandler) handleDeleteSock(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get all socks
-// @Description Returns a list of paginated socks sorted in descending order by created date
+// @Description Returns a list of paginated socks matching the filters. Sorted in descending order by created date by default, or by relevance when searching.
 // @Tags Inventory
 // @Produce json
 // @Param limit query int false "Limit the number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
+// @Param q query string false "Search the sock name and description"
+// @Param option query []string false "Only socks with a variant having every one of these options, formatted as code:value, e.g. color:Red" collectionFormat(multi)
+// @Param size query string false "Shorthand for option=size:<value>"
+// @Param min_price query number false "Only socks with a variant priced at or above this amount"
+// @Param max_price query number false "Only socks with a variant priced at or below this amount"
+// @Param in_stock query bool false "Only socks with a variant in stock"
+// @Param category_id query int false "Only socks of this category or one of its subcategories"
+// @Param tag query []string false "Only socks with every one of these tags" collectionFormat(multi)
+// @Param collection_id query int false "Only socks of this collection, in the collection order unless another sort is requested"
+// @Param sort query string false "Sort order" Enums(relevance, newest, price_asc, price_desc, name_asc, name_desc)
 // @Success 200 {object} types.SocksPaginatedResponse
 // @Router /socks [get]
 func (h *SockHandler) handleGetAllSocks(w http.ResponseWriter, r *http.Request) {
 	limit, offset := utils.GetLimitOffset(r, 50, 0)
 
-	socks, err := h.store.GetSocks(limit, offset)
+	filters, err := parseSockFilters(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	socks, err := h.store.GetSocks(limit, offset, filters)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	total, err := h.store.CountSocks()
+	total, err := h.store.CountSocks(filters)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -147,7 +176,7 @@ func (h *SockHandler) handleGetAllSocks(w http.ResponseWriter, r *http.Request)
 }
 
 // @Summary Get details of a specific sock
-// @Description Retrieve the details of a sock by its ID
+// @Description Retrieve the details of a sock by its ID. The "ETag" header identifies the current version of the sock and its variants, to be sent back in "If-Match" when updating it.
 // @Tags Inventory
 // @Produce json
 // @Param sock_id path int true "Sock ID"
@@ -174,16 +203,18 @@ func (h *SockHandler) handleGetSockDetails(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	utils.SetETag(w, SockVersion(*sock))
 	utils.WriteJson(w, http.StatusOK, sock)
 }
 
 // @Summary Updates the details of a sock
-// @Description Updates all of the details for a sock given a sock ID
+// @Description Updates all of the details for a sock given a sock ID. Variants are matched by options and replace the current ones: variants left out are removed, or archived when they were already ordered. The "If-Match" header must be set to the "ETag" returned when the sock was fetched; the update is rejected with a 412 if the sock or its stock changed since, and with a 428 if the header is missing.
 // @Tags Inventory
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param sock_id path int true "Sock ID"
+// @Param If-Match header string true "ETag of the sock being edited"
 // @Param details body types.UpdateSockRequest true "Updated sock details"
 // @Success 200 {object} types.Message
 // @Router /socks/{sock_id} [patch]
@@ -207,6 +238,12 @@ func (h *SockHandler) handleUpdateSock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := utils.IfMatch(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusPreconditionRequired, err)
+		return
+	}
+
 	var req types.UpdateSockRequest
 	if err := utils.ParseJson(r, &req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
@@ -219,7 +256,15 @@ func (h *SockHandler) handleUpdateSock(w http.ResponseWriter, r *http.Request) {
 
 	sock := toSock(req.Sock)
 	variants := toSockVariantArray(req.Variants)
-	if err := h.store.UpdateSock(sockID, sock, variants); err != nil {
+	if err := h.store.UpdateSock(sockID, version, sock, variants, middleware.GetUserIDFromContext(r.Context())); err != nil {
+		if errors.Is(err, types.ErrVersionConflict) {
+			utils.WriteError(w, http.StatusPreconditionFailed, err)
+			return
+		}
+		if isSockInputError(err) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -228,7 +273,7 @@ func (h *SockHandler) handleUpdateSock(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Retrieves the related products for a particular sock
-// @Description For now, it will retrieve the top 6 products that are not matching the sock_id passed in.
+// @Description Retrieves up to 6 in-stock socks ranked by the number of tags they share with the sock, then by category: the same category first, then a parent, child or sibling category. Newer socks break ties.
 // @Tags Inventory
 // @Accept json
 // @Param sock_id path int true "Sock ID"
@@ -268,6 +313,8 @@ func toSock(dto types.SockDTO) types.Sock {
 		Name:            dto.Name,
 		Description:     dto.Description,
 		PreviewImageURL: dto.PreviewImageURL,
+		CategoryID:      dto.CategoryID,
+		Tags:            dto.Tags,
 	}
 }
 
@@ -277,9 +324,10 @@ func toSockVariant(dto types.SockVariantDTO) types.SockVariant {
 		quantity = *dto.Quantity
 	}
 	return types.SockVariant{
-		Size:     dto.Size,
-		Price:    dto.Price,
-		Quantity: quantity,
+		Options:          dto.Options,
+		Price:            dto.Price,
+		Quantity:         quantity,
+		ReorderThreshold: dto.ReorderThreshold,
 	}
 }
 
@@ -290,3 +338,85 @@ func toSockVariantArray(dtos []types.SockVariantDTO) []types.SockVariant {
 	}
 	return v
 }
+
+// parseSockFilters reads the sock filters from the request's query params.
+func parseSockFilters(r *http.Request) (types.SockFilters, error) {
+	query := r.URL.Query()
+	filters := types.SockFilters{
+		Query: strings.TrimSpace(query.Get("q")),
+		Sort:  query.Get("sort"),
+	}
+
+	var err error
+	if filters.MinPrice, err = utils.ParseOptionalFloat(query.Get("min_price")); err != nil {
+		return filters, fmt.Errorf("invalid min_price: %v", err)
+	}
+	if filters.MaxPrice, err = utils.ParseOptionalFloat(query.Get("max_price")); err != nil {
+		return filters, fmt.Errorf("invalid max_price: %v", err)
+	}
+	if filters.MinPrice != nil && filters.MaxPrice != nil && *filters.MinPrice > *filters.MaxPrice {
+		return filters, errors.New("min_price must be less than or equal to max_price")
+	}
+
+	if inStock := query.Get("in_stock"); inStock != "" {
+		if filters.InStock, err = strconv.ParseBool(inStock); err != nil {
+			return filters, fmt.Errorf("invalid in_stock: expected true or false")
+		}
+	}
+
+	if filters.Options, err = parseVariantOptions(query["option"], query.Get("size")); err != nil {
+		return filters, err
+	}
+	if filters.CategoryID, err = parseOptionalID(query.Get("category_id")); err != nil {
+		return filters, fmt.Errorf("invalid category_id: %v", err)
+	}
+	if filters.CollectionID, err = parseOptionalID(query.Get("collection_id")); err != nil {
+		return filters, fmt.Errorf("invalid collection_id: %v", err)
+	}
+	for _, tag := range query["tag"] {
+		if tag = utils.Normalize(tag); tag != "" {
+			filters.Tags = append(filters.Tags, tag)
+		}
+	}
+
+	if err := utils.Validate.Struct(filters); err != nil {
+		return filters, err
+	}
+	return filters, nil
+}
+
+// parseVariantOptions parses `code:value` option filters. A size is a shorthand for the "size" option.
+func parseVariantOptions(values []string, size string) (map[string]string, error) {
+	options := make(map[string]string, len(values)+1)
+	for _, v := range values {
+		code, value, ok := strings.Cut(v, ":")
+		code, value = utils.Normalize(code), strings.TrimSpace(value)
+		if !ok || code == "" || value == "" {
+			return nil, fmt.Errorf("invalid option %q: expected code:value", v)
+		}
+		options[code] = value
+	}
+	if size = strings.TrimSpace(size); size != "" {
+		options["size"] = size
+	}
+	return options, nil
+}
+
+// isSockInputError reports whether a sock store error is caused by invalid sock details.
+func isSockInputError(err error) bool {
+	return errors.Is(err, types.ErrCategoryNotFound) ||
+		errors.Is(err, types.ErrInvalidVariantOption) ||
+		errors.Is(err, types.ErrDuplicateVariant)
+}
+
+// parseOptionalID parses an optional integer ID, returning nil when the value is empty.
+func parseOptionalID(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, errors.New("expected an integer")
+	}
+	return &id, nil
+}
@@ -0,0 +1,270 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// multipartOverheadInBytes is allowed on top of the maximum image size for the rest of the multipart form.
+const multipartOverheadInBytes = 1 << 20
+
+// @Summary Get the images of a sock
+// @Description Retrieves the gallery images of a sock in display order. The first image is the sock preview.
+// @Tags Inventory
+// @Produce json
+// @Param sock_id path int true "Sock ID"
+// @Success 200 {array} types.SockImage
+// @Router /socks/{sock_id}/images [get]
+func (h *SockHandler) handleGetSockImages(w http.ResponseWriter, r *http.Request) {
+	sockID, ok := h.sockIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	images, err := h.store.GetSockImages(sockID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, images)
+}
+
+// @Summary Upload an image for a sock
+// @Description Uploads a JPEG or PNG image and adds it at the end of the gallery of a sock, along with a resized thumbnail. The first image of the gallery is used as the sock preview. Images larger than the configured maximum size are rejected with a 413 and other file types with a 415.
+// @Tags Inventory
+// @Accept mpfd
+// @Produce json
+// @Security Bearer
+// @Param sock_id path int true "Sock ID"
+// @Param image formData file true "JPEG or PNG image"
+// @Success 201 {object} types.SockImage
+// @Router /socks/{sock_id}/images [post]
+func (h *SockHandler) handleUploadSockImage(w http.ResponseWriter, r *http.Request) {
+	sockID, ok := h.sockIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := readUploadedImage(w, r)
+	if !ok {
+		return
+	}
+
+	processed, err := processImage(data, int(config.Envs.ThumbnailSizeInPixels))
+	if err != nil {
+		switch {
+		case errors.Is(err, errUnsupportedImageType):
+			utils.WriteError(w, http.StatusUnsupportedMediaType, err)
+		case isImageError(err):
+			utils.WriteError(w, http.StatusBadRequest, err)
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	name, err := utils.GenerateUUID()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	image := types.SockImage{
+		ContentType:  processed.ContentType,
+		Width:        processed.Width,
+		Height:       processed.Height,
+		Key:          fmt.Sprintf("socks/%d/%s%s", sockID, name, processed.Extension),
+		ThumbnailKey: fmt.Sprintf("socks/%d/%s_thumb%s", sockID, name, processed.Extension),
+	}
+
+	if err := h.blobs.Put(image.Key, data, image.ContentType); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := h.blobs.Put(image.ThumbnailKey, processed.Thumbnail, image.ContentType); err != nil {
+		h.deleteImageBlobs(image)
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	imageID, err := h.store.AddSockImage(sockID, image)
+	if err != nil {
+		h.deleteImageBlobs(image)
+		if errors.Is(err, types.ErrTooManyImages) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	created, err := h.store.GetSockImage(sockID, imageID)
+	if err != nil || created == nil {
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("unable to fetch created sock image ID %v: %v", imageID, err))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, created)
+}
+
+// @Summary Reorder the images of a sock
+// @Description Sets the display order of the gallery of a sock. Every image of the sock must be listed exactly once. The first image becomes the sock preview.
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param sock_id path int true "Sock ID"
+// @Param order body types.ReorderSockImagesRequest true "Image IDs in display order"
+// @Success 200 {array} types.SockImage
+// @Router /socks/{sock_id}/images [patch]
+func (h *SockHandler) handleReorderSockImages(w http.ResponseWriter, r *http.Request) {
+	sockID, ok := h.sockIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req types.ReorderSockImagesRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.store.ReorderSockImages(sockID, req.ImageIDs); err != nil {
+		if errors.Is(err, types.ErrInvalidImageOrder) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	images, err := h.store.GetSockImages(sockID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, images)
+}
+
+// @Summary Delete an image of a sock
+// @Description Removes an image and its thumbnail from the gallery of a sock. When it was the preview, the next image takes its place.
+// @Tags Inventory
+// @Produce json
+// @Security Bearer
+// @Param sock_id path int true "Sock ID"
+// @Param image_id path int true "Image ID"
+// @Success 200 {object} types.Message
+// @Router /socks/{sock_id}/images/{image_id} [delete]
+func (h *SockHandler) handleDeleteSockImage(w http.ResponseWriter, r *http.Request) {
+	sockID, ok := h.sockIDFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	imageID, err := strconv.Atoi(mux.Vars(r)["image_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid image ID"))
+		return
+	}
+
+	image, err := h.store.GetSockImage(sockID, imageID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if image == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("sock image not found"))
+		return
+	}
+
+	if err := h.store.DeleteSockImage(sockID, imageID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The image is already gone from the gallery, so failing to delete the files only leaves orphans behind
+	h.deleteImageBlobs(*image)
+
+	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Sock image deleted successfully"})
+}
+
+// sockIDFromRequest returns the ID of an existing sock from the request path, writing an error response otherwise.
+func (h *SockHandler) sockIDFromRequest(w http.ResponseWriter, r *http.Request) (sockID int, ok bool) {
+	sockID, err := strconv.Atoi(mux.Vars(r)["sock_id"])
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid sock ID"))
+		return 0, false
+	}
+
+	exists, err := h.store.SockExistsByID(sockID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return 0, false
+	}
+	if !exists {
+		utils.WriteError(w, http.StatusNotFound, errors.New("sock not found"))
+		return 0, false
+	}
+
+	return sockID, true
+}
+
+// readUploadedImage reads the "image" file of a multipart form, enforcing the maximum image size.
+func readUploadedImage(w http.ResponseWriter, r *http.Request) (data []byte, ok bool) {
+	maxSize := config.Envs.MaxImageUploadSizeInBytes
+	tooLarge := fmt.Errorf("image must be at most %.1f MB", float64(maxSize)/(1<<20))
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverheadInBytes)
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, tooLarge)
+			return nil, false
+		}
+		utils.WriteError(w, http.StatusBadRequest, errors.New(`invalid multipart form, the image must be sent in the "image" field`))
+		return nil, false
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New(`missing "image" file`))
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err = io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("unable to read the image"))
+		return nil, false
+	}
+	if int64(len(data)) > maxSize {
+		utils.WriteError(w, http.StatusRequestEntityTooLarge, tooLarge)
+		return nil, false
+	}
+
+	return data, true
+}
+
+// deleteImageBlobs deletes the files of an image. Failures are only logged.
+func (h *SockHandler) deleteImageBlobs(image types.SockImage) {
+	for _, key := range []string{image.Key, image.ThumbnailKey} {
+		if err := h.blobs.Delete(key); err != nil {
+			log.Printf("Error deleting image blob %v: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package inventory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/sockify/sockify/config"
+	"github.com/sockify/sockify/types"
+)
+
+// maxSockImages is the maximum number of images in the gallery of a sock.
+const maxSockImages = 12
+
+// sockImageColumns are the columns read by `scanRowIntoSockImage`, in order.
+const sockImageColumns = "sock_image_id, image_key, thumbnail_key, content_type, width, height, position, created_at"
+
+// GetSockImages retrieves the gallery images of a sock in display order.
+func (s *SockStore) GetSockImages(sockID int) ([]types.SockImage, error) {
+	rows, err := s.db.Query(`
+    SELECT `+sockImageColumns+`
+    FROM sock_images
+    WHERE sock_id = $1
+    ORDER BY position ASC, sock_image_id ASC
+  `, sockID)
+	if err != nil {
+		log.Printf("Error fetching images for sockID %d: %v", sockID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make([]types.SockImage, 0)
+	for rows.Next() {
+		image, err := scanRowIntoSockImage(rows)
+		if err != nil {
+			log.Printf("Error scanning sock image: %v", err)
+			return nil, err
+		}
+		images = append(images, *image)
+	}
+
+	return images, nil
+}
+
+func (s *SockStore) GetSockImage(sockID int, imageID int) (*types.SockImage, error) {
+	image, err := scanRowIntoSockImage(s.db.QueryRow(
+		"SELECT "+sockImageColumns+" FROM sock_images WHERE sock_id = $1 AND sock_image_id = $2",
+		sockID, imageID,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch sock image with ID %d: %w", imageID, err)
+	}
+	return image, nil
+}
+
+// AddSockImage adds an image at the end of the gallery of a sock. The first image of a gallery becomes the sock preview.
+func (s *SockStore) AddSockImage(sockID int, image types.SockImage) (imageID int, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return 0, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the sock so concurrent uploads get distinct positions
+	if err = lockSockTx(tx, sockID); err != nil {
+		return 0, err
+	}
+
+	var count, position int
+	err = tx.QueryRow("SELECT COUNT(*), COALESCE(MAX(position) + 1, 0) FROM sock_images WHERE sock_id = $1", sockID).Scan(&count, &position)
+	if err != nil {
+		log.Printf("Error counting images for sock ID %v: %v", sockID, err)
+		return 0, err
+	}
+	if count >= maxSockImages {
+		err = types.ErrTooManyImages
+		return 0, err
+	}
+
+	err = tx.QueryRow(`
+    INSERT INTO sock_images (sock_id, image_key, thumbnail_key, content_type, width, height, position)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    RETURNING sock_image_id
+  `, sockID, image.Key, image.ThumbnailKey, image.ContentType, image.Width, image.Height, position).Scan(&imageID)
+	if err != nil {
+		log.Printf("Error inserting sock image: %v", err)
+		return 0, err
+	}
+
+	if err = syncPreviewImageTx(tx, sockID); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return 0, err
+	}
+
+	return imageID, nil
+}
+
+// DeleteSockImage removes an image from the gallery of a sock. The caller is responsible for deleting its blobs.
+func (s *SockStore) DeleteSockImage(sockID int, imageID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockSockTx(tx, sockID); err != nil {
+		return err
+	}
+
+	var key string
+	err = tx.QueryRow("DELETE FROM sock_images WHERE sock_id = $1 AND sock_image_id = $2 RETURNING image_key", sockID, imageID).Scan(&key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("sock image with ID %v does not exist", imageID)
+		}
+		log.Printf("Error deleting sock image: %v", err)
+		return err
+	}
+
+	// Never leave the preview pointing to a deleted image, even when it was the last one
+	_, err = tx.Exec("UPDATE socks SET preview_image_url = '', version = version + 1 WHERE sock_id = $1 AND preview_image_url = $2", sockID, imageURL(key))
+	if err != nil {
+		log.Printf("Error clearing preview image of sock ID %v: %v", sockID, err)
+		return err
+	}
+
+	if err = syncPreviewImageTx(tx, sockID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ReorderSockImages sets the display order of the gallery of a sock.
+func (s *SockStore) ReorderSockImages(sockID int, imageIDs []int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = lockSockTx(tx, sockID); err != nil {
+		return err
+	}
+
+	var count int
+	if err = tx.QueryRow("SELECT COUNT(*) FROM sock_images WHERE sock_id = $1", sockID).Scan(&count); err != nil {
+		log.Printf("Error counting images for sock ID %v: %v", sockID, err)
+		return err
+	}
+	if count != len(imageIDs) {
+		err = types.ErrInvalidImageOrder
+		return err
+	}
+
+	for position, imageID := range imageIDs {
+		var res sql.Result
+		res, err = tx.Exec("UPDATE sock_images SET position = $1 WHERE sock_id = $2 AND sock_image_id = $3", position, sockID, imageID)
+		if err != nil {
+			log.Printf("Error updating position of sock image ID %v: %v", imageID, err)
+			return err
+		}
+
+		var updated int64
+		if updated, err = res.RowsAffected(); err != nil {
+			return err
+		}
+		if updated == 0 {
+			err = types.ErrInvalidImageOrder
+			return err
+		}
+	}
+
+	if err = syncPreviewImageTx(tx, sockID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// lockSockTx locks a sock row for the rest of the transaction.
+func lockSockTx(tx *sql.Tx, sockID int) error {
+	var id int
+	err := tx.QueryRow("SELECT sock_id FROM socks WHERE sock_id = $1 FOR UPDATE", sockID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("sock with ID %v does not exist", sockID)
+		}
+		log.Printf("Error locking sock ID %v: %v", sockID, err)
+		return err
+	}
+	return nil
+}
+
+// syncPreviewImageTx sets the preview of a sock to its first gallery image. Socks without images keep their current preview.
+func syncPreviewImageTx(tx *sql.Tx, sockID int) error {
+	var key string
+	err := tx.QueryRow(`
+    SELECT image_key FROM sock_images
+    WHERE sock_id = $1
+    ORDER BY position ASC, sock_image_id ASC
+    LIMIT 1
+  `, sockID).Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Error fetching first image of sock ID %v: %v", sockID, err)
+		return err
+	}
+
+	_, err = tx.Exec(`
+    UPDATE socks SET preview_image_url = $1, version = version + 1
+    WHERE sock_id = $2 AND preview_image_url IS DISTINCT FROM $1
+  `, imageURL(key), sockID)
+	if err != nil {
+		log.Printf("Error updating preview image of sock ID %v: %v", sockID, err)
+		return err
+	}
+	return nil
+}
+
+// imageURL returns the public URL of an image stored under the given blob key.
+func imageURL(key string) string {
+	return strings.TrimSuffix(config.Envs.ImagesBaseURL, "/") + "/" + key
+}
+
+func scanRowIntoSockImage(row rowScanner) (*types.SockImage, error) {
+	image := &types.SockImage{}
+	err := row.Scan(&image.ID, &image.Key, &image.ThumbnailKey, &image.ContentType, &image.Width, &image.Height, &image.Position, &image.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	image.URL = imageURL(image.Key)
+	image.ThumbnailURL = imageURL(image.ThumbnailKey)
+	return image, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
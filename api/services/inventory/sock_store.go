@@ -1,13 +1,18 @@
 package inventory
 
 import (
+	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/database"
 	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
 )
 
 type SockStore struct {
@@ -18,37 +23,100 @@ func NewSockStore(db *sql.DB) types.SockStore {
 	return &SockStore{db: db}
 }
 
-// CreateSock inserts a new sock and its variants into the database and returns generated ID
-func (s *SockStore) CreateSock(sock types.Sock, variants []types.SockVariant) (int, error) {
-	var sockID int
-	err := s.db.QueryRow(`
-		INSERT INTO socks (name, description, preview_image_url) 
-		VALUES ($1, $2, $3) 
+// CreateSock inserts a new sock with its variants and tags in a single transaction and returns generated ID.
+// Returns `types.ErrCategoryNotFound` if the category does not exist.
+func (s *SockStore) CreateSock(sock types.Sock, variants []types.SockVariant, adminID int) (sockID int, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return 0, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(`
+		INSERT INTO socks (name, description, preview_image_url, category_id) 
+		VALUES ($1, $2, $3, $4) 
 		RETURNING sock_id`,
-		sock.Name, sock.Description, sock.PreviewImageURL).Scan(&sockID)
+		sock.Name, sock.Description, sock.PreviewImageURL, sock.CategoryID).Scan(&sockID)
 	if err != nil {
+		err = mapCategoryError(err)
 		log.Printf("Error inserting sock: %v", err)
 		return 0, err
 	}
 
 	// Insert variants
+	seen := make(map[string]bool, len(variants))
 	for _, variant := range variants {
-		log.Printf("Inserting variant with sockID: %d, price: %.2f, quantity: %d, size: %s",
-			sockID, variant.Price, variant.Quantity, variant.Size)
+		log.Printf("Inserting variant with sockID: %d, price: %.2f, quantity: %d, options: %v",
+			sockID, variant.Price, variant.Quantity, variant.Options)
 
-		_, err := s.db.Exec(`
-			INSERT INTO sock_variants (sock_id, price, quantity, size) 
-			VALUES ($1, $2, $3, $4)`,
-			sockID, variant.Price, variant.Quantity, variant.Size)
-		if err != nil {
+		if _, err = upsertVariantTx(tx, sockID, variant, adminID, seen); err != nil {
 			log.Printf("Error inserting variant: %v", err)
 			return 0, err
 		}
 	}
 
+	if err = setSockTagsTx(tx, sockID, sock.Tags); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return 0, err
+	}
+
 	return sockID, nil
 }
 
+// setSockTagsTx replaces the tags of a sock, creating the tags that do not exist yet. Tag names are normalized.
+func setSockTagsTx(tx *sql.Tx, sockID int, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM sock_tags WHERE sock_id = $1", sockID); err != nil {
+		return fmt.Errorf("failed to clear sock tags: %w", err)
+	}
+
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name := utils.Normalize(tag)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		// The no-op update makes `RETURNING` work for existing tags too
+		var tagID int
+		err := tx.QueryRow(`
+			INSERT INTO tags (name) VALUES ($1)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING tag_id`, name).Scan(&tagID)
+		if err != nil {
+			return fmt.Errorf("failed to upsert tag %q: %w", name, err)
+		}
+
+		if _, err := tx.Exec("INSERT INTO sock_tags (sock_id, tag_id) VALUES ($1, $2)", sockID, tagID); err != nil {
+			return fmt.Errorf("failed to tag sock: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// mapCategoryError maps a foreign key violation on the sock category to `types.ErrCategoryNotFound`.
+func mapCategoryError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return types.ErrCategoryNotFound
+	}
+	return err
+}
+
 // SockExists checks if a sock with the same name already exists in the database
 func (s *SockStore) SockExists(name string) (bool, error) {
 	var exists bool
@@ -91,7 +159,7 @@ func (s *SockStore) DeleteSock(sockID int) error {
 
 	result, err := s.db.Exec(`
     UPDATE socks
-    SET is_deleted = true
+    SET is_deleted = true, version = version + 1
     WHERE sock_id = $1
   `, sockID)
 	if err != nil {
@@ -110,16 +178,27 @@ func (s *SockStore) DeleteSock(sockID int) error {
 	return nil
 }
 
-// GetSocks retrieves socks from the database with pagination and sorted by created date
-func (s *SockStore) GetSocks(limit int, offset int) ([]types.Sock, error) {
-	rows, err := s.db.Query(`
-    SELECT sock_id, name, description, preview_image_url, created_at
-    FROM socks
-    WHERE is_deleted = false
-    ORDER BY created_at DESC
-    LIMIT $1 OFFSET $2
-  `, limit, offset)
+// sockTagsColumn selects the tag names of a sock, sorted by name. Sock table must be aliased as `s`.
+const sockTagsColumn = `COALESCE((
+      SELECT array_agg(t.name ORDER BY t.name)
+      FROM sock_tags st
+      JOIN tags t ON t.tag_id = st.tag_id
+      WHERE st.sock_id = s.sock_id
+    ), '{}')`
+
+// GetSocks retrieves socks from the database with pagination, matching the given filters.
+// Results are sorted by created date unless another sort is requested.
+func (s *SockStore) GetSocks(limit int, offset int, filters types.SockFilters) ([]types.Sock, error) {
+	where := buildSockFilters(filters)
+	query := fmt.Sprintf(`
+    SELECT s.sock_id, s.name, s.description, s.preview_image_url, s.category_id, `+sockTagsColumn+`, s.version, s.created_at
+    FROM socks s
+    %s
+    ORDER BY %s
+    LIMIT %s OFFSET %s
+  `, where.Clause(), sockSortOrder(filters, where), where.Arg(limit), where.Arg(offset))
 
+	rows, err := s.db.Query(query, where.Args()...)
 	if err != nil {
 		log.Printf("Error fetching socks: %v", err)
 		return nil, err
@@ -129,10 +208,12 @@ func (s *SockStore) GetSocks(limit int, offset int) ([]types.Sock, error) {
 	socks := make([]types.Sock, 0)
 	for rows.Next() {
 		var sock types.Sock
-		if err := rows.Scan(&sock.ID, &sock.Name, &sock.Description, &sock.PreviewImageURL, &sock.CreatedAt); err != nil {
+		var tags pq.StringArray
+		if err := rows.Scan(&sock.ID, &sock.Name, &sock.Description, &sock.PreviewImageURL, &sock.CategoryID, &tags, &sock.Version, &sock.CreatedAt); err != nil {
 			log.Printf("Error scanning sock: %v", err)
 			return nil, err
 		}
+		sock.Tags = tags
 
 		// Fetch variants for each sock
 		sock.Variants, err = s.GetSockVariants(sock.ID)
@@ -146,10 +227,12 @@ func (s *SockStore) GetSocks(limit int, offset int) ([]types.Sock, error) {
 	return socks, nil
 }
 
-// CountSocks returns the total number of socks in the database for pagination purposes.
-func (s *SockStore) CountSocks() (int, error) {
+// CountSocks returns the total number of socks matching the filters for pagination purposes.
+func (s *SockStore) CountSocks(filters types.SockFilters) (int, error) {
+	where := buildSockFilters(filters)
+
 	var count int
-	err := s.db.QueryRow(`SELECT COUNT(*) FROM socks WHERE is_deleted = false`).Scan(&count)
+	err := s.db.QueryRow("SELECT COUNT(*) FROM socks s "+where.Clause(), where.Args()...).Scan(&count)
 	if err != nil {
 		log.Printf("Error counting socks: %v", err)
 		return 0, err
@@ -157,12 +240,115 @@ func (s *SockStore) CountSocks() (int, error) {
 	return count, nil
 }
 
-// GetSockVariants retrieves the variants for a specific sock
+// buildSockFilters builds the conditions shared by `GetSocks` and `CountSocks`. Sock table must be aliased as `s`.
+func buildSockFilters(filters types.SockFilters) *database.WhereBuilder {
+	where := &database.WhereBuilder{}
+	where.Where("s.is_deleted = false")
+
+	if filters.Query != "" {
+		where.Where(fmt.Sprintf("s.search_vector @@ websearch_to_tsquery('english', %s)", where.Arg(filters.Query)))
+	}
+
+	if filters.CategoryID != nil {
+		where.Where(fmt.Sprintf(`s.category_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT category_id FROM categories WHERE category_id = %s
+				UNION
+				SELECT c.category_id FROM categories c JOIN subtree ON c.parent_id = subtree.category_id
+			)
+			SELECT category_id FROM subtree
+		)`, where.Arg(*filters.CategoryID)))
+	}
+	for _, tag := range filters.Tags {
+		where.Where(fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM sock_tags st JOIN tags t ON t.tag_id = st.tag_id WHERE st.sock_id = s.sock_id AND t.name = %s)",
+			where.Arg(tag),
+		))
+	}
+	if filters.CollectionID != nil {
+		where.Where(fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM collection_socks cs WHERE cs.sock_id = s.sock_id AND cs.collection_id = %s)",
+			where.Arg(*filters.CollectionID),
+		))
+	}
+
+	// Variant filters must all match the same variant, e.g. a size M that is in stock
+	variantConditions := []string{"sv.is_deleted = false"}
+	codes := make([]string, 0, len(filters.Options))
+	for code := range filters.Options {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		variantConditions = append(variantConditions, fmt.Sprintf(`EXISTS (
+			SELECT 1
+			FROM sock_variant_options o
+			JOIN attribute_values av ON av.attribute_value_id = o.attribute_value_id
+			JOIN attributes a ON a.attribute_id = av.attribute_id
+			WHERE o.sock_variant_id = sv.sock_variant_id AND a.code = %s AND lower(av.value) = lower(%s)
+		)`, where.Arg(code), where.Arg(filters.Options[code])))
+	}
+	if filters.MinPrice != nil {
+		variantConditions = append(variantConditions, "sv.price >= "+where.Arg(*filters.MinPrice))
+	}
+	if filters.MaxPrice != nil {
+		variantConditions = append(variantConditions, "sv.price <= "+where.Arg(*filters.MaxPrice))
+	}
+	if filters.InStock {
+		variantConditions = append(variantConditions, "sv.quantity > 0")
+	}
+	if len(variantConditions) > 1 {
+		where.Where(fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM sock_variants sv WHERE sv.sock_id = s.sock_id AND %s)",
+			strings.Join(variantConditions, " AND "),
+		))
+	}
+
+	return where
+}
+
+// sockSortOrder returns the `ORDER BY` expression for the requested sort. The sock ID is used as a tie-breaker to keep pagination stable.
+func sockSortOrder(filters types.SockFilters, where *database.WhereBuilder) string {
+	sort := filters.Sort
+	if sort == "" {
+		sort = "newest"
+		if filters.Query != "" {
+			sort = "relevance"
+		} else if filters.CollectionID != nil {
+			return fmt.Sprintf(
+				"(SELECT cs.position FROM collection_socks cs WHERE cs.sock_id = s.sock_id AND cs.collection_id = %s) ASC, s.sock_id DESC",
+				where.Arg(*filters.CollectionID),
+			)
+		}
+	}
+
+	minPrice := "(SELECT MIN(sv.price) FROM sock_variants sv WHERE sv.sock_id = s.sock_id AND sv.is_deleted = false)"
+	switch sort {
+	case "relevance":
+		if filters.Query == "" {
+			return "s.created_at DESC, s.sock_id DESC"
+		}
+		return fmt.Sprintf("ts_rank(s.search_vector, websearch_to_tsquery('english', %s)) DESC, s.sock_id DESC", where.Arg(filters.Query))
+	case "price_asc":
+		return minPrice + " ASC NULLS LAST, s.sock_id DESC"
+	case "price_desc":
+		return minPrice + " DESC NULLS LAST, s.sock_id DESC"
+	case "name_asc":
+		return "s.name ASC, s.sock_id DESC"
+	case "name_desc":
+		return "s.name DESC, s.sock_id DESC"
+	default:
+		return "s.created_at DESC, s.sock_id DESC"
+	}
+}
+
+// GetSockVariants retrieves the variants for a specific sock, excluding archived ones
 func (s *SockStore) GetSockVariants(sockID int) ([]types.SockVariant, error) {
 	rows, err := s.db.Query(`
-    SELECT sock_variant_id, price, quantity, size, created_at
+    SELECT sock_variant_id, price, quantity, reorder_threshold, version, created_at
     FROM sock_variants
-    WHERE sock_id = $1
+    WHERE sock_id = $1 AND is_deleted = false
+    ORDER BY sock_variant_id ASC
   `, sockID)
 
 	if err != nil {
@@ -174,98 +360,193 @@ func (s *SockStore) GetSockVariants(sockID int) ([]types.SockVariant, error) {
 	variants := make([]types.SockVariant, 0)
 	for rows.Next() {
 		var sv types.SockVariant
-		if err := rows.Scan(&sv.ID, &sv.Price, &sv.Quantity, &sv.Size, &sv.CreatedAt); err != nil {
+		if err := rows.Scan(&sv.ID, &sv.Price, &sv.Quantity, &sv.ReorderThreshold, &sv.Version, &sv.CreatedAt); err != nil {
 			log.Printf("Error scanning variant: %v", err)
 			return nil, err
 		}
+		sv.SockID = sockID
 		variants = append(variants, sv)
 	}
 
+	if err := s.loadVariantOptions(variants); err != nil {
+		return nil, err
+	}
 	return variants, nil
 }
 
 func (s *SockStore) GetSockByID(sockID int) (*types.Sock, error) {
 	var sock types.Sock
+	var tags pq.StringArray
 	err := s.db.QueryRow(`
-    SELECT sock_id, name, description, preview_image_url, created_at
-    FROM socks
-    WHERE sock_id = $1 AND is_deleted = false
-  `, sockID).Scan(&sock.ID, &sock.Name, &sock.Description, &sock.PreviewImageURL, &sock.CreatedAt)
+    SELECT s.sock_id, s.name, s.description, s.preview_image_url, s.category_id, `+sockTagsColumn+`, s.version, s.created_at
+    FROM socks s
+    WHERE s.sock_id = $1 AND s.is_deleted = false
+  `, sockID).Scan(&sock.ID, &sock.Name, &sock.Description, &sock.PreviewImageURL, &sock.CategoryID, &tags, &sock.Version, &sock.CreatedAt)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, fmt.Errorf("failed to fetch sock with ID %d: %w", sockID, err)
 	}
+	sock.Tags = tags
 
 	sock.Variants, err = s.GetSockVariants(sockID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch variants for sock with ID %d: %w", sockID, err)
 	}
 
+	sock.Images, err = s.GetSockImages(sockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch images for sock with ID %d: %w", sockID, err)
+	}
+
 	return &sock, nil
 }
 
-func (s *SockStore) UpdateSock(sockID int, sock types.Sock, variants []types.SockVariant) error {
-	_, err := s.db.Exec(`
-		UPDATE socks
-		SET name = $1, description = $2, preview_image_url = $3
-		WHERE sock_id = $4 AND is_deleted = false`,
-		sock.Name, sock.Description, sock.PreviewImageURL, sockID)
+// UpdateSock updates a sock and reconciles its variants with the given ones by options, in a single transaction.
+// Variants are created, updated or restored as needed. Variants missing from the list are deleted, or archived when
+// past orders reference them. Tags are replaced with the given ones. The update only happens if the sock is still at the given version (see `SockVersion`), otherwise `types.ErrVersionConflict` is returned.
+func (s *SockStore) UpdateSock(sockID int, version string, sock types.Sock, variants []types.SockVariant, adminID int) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
 
+	current, err := lockSockVersionTx(tx, sockID)
 	if err != nil {
-		return fmt.Errorf("failed to update sock: %w", err)
+		return err
+	}
+	if current != version {
+		err = types.ErrVersionConflict
+		return err
 	}
 
-	for _, variant := range variants {
-		exists, err := s.SockVariantExists(sockID, variant.Size)
-		if err != nil {
+	_, err = tx.Exec(`
+		UPDATE socks
+		SET name = $1, description = $2, preview_image_url = $3, category_id = $4, version = version + 1
+		WHERE sock_id = $5`,
+		sock.Name, sock.Description, sock.PreviewImageURL, sock.CategoryID, sockID)
+	if err != nil {
+		if err = mapCategoryError(err); errors.Is(err, types.ErrCategoryNotFound) {
 			return err
 		}
+		return fmt.Errorf("failed to update sock: %w", err)
+	}
+
+	if err = setSockTagsTx(tx, sockID, sock.Tags); err != nil {
+		return err
+	}
+
+	// Uploaded images take precedence over the given preview URL
+	if err = syncPreviewImageTx(tx, sockID); err != nil {
+		return err
+	}
 
-		if exists {
-			_, err := s.db.Exec(`
-				UPDATE sock_variants
-				SET price = $1, quantity = $2
-				WHERE sock_id = $3 AND size = $4`,
-				variant.Price, variant.Quantity, sockID, variant.Size)
-
-			if err != nil {
-				return fmt.Errorf("failed to update variant: %w", err)
-			}
-		} else {
-			_, err := s.db.Exec(`
-				INSERT INTO sock_variants (sock_id, price, quantity, size)
-				VALUES ($1, $2, $3, $4)`,
-				sockID, variant.Price, variant.Quantity, variant.Size)
-
-			if err != nil {
-				return fmt.Errorf("failed to insert variant: %w", err)
-			}
+	keys := make([]string, len(variants))
+	seen := make(map[string]bool, len(variants))
+	for i, variant := range variants {
+		if keys[i], err = upsertVariantTx(tx, sockID, variant, adminID, seen); err != nil {
+			return err
 		}
 	}
 
+	// Order items and the stock ledger keep referencing their variant, so only variants without history can be deleted
+	_, err = tx.Exec(`
+		DELETE FROM sock_variants sv
+		WHERE sv.sock_id = $1 AND NOT (sv.options_key = ANY($2))
+			AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.sock_variant_id = sv.sock_variant_id)
+			AND NOT EXISTS (SELECT 1 FROM inventory_movements m WHERE m.sock_variant_id = sv.sock_variant_id)`,
+		sockID, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to delete variants: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE sock_variants
+		SET is_deleted = true, version = version + 1
+		WHERE sock_id = $1 AND is_deleted = false AND NOT (options_key = ANY($2))`,
+		sockID, pq.Array(keys))
+	if err != nil {
+		return fmt.Errorf("failed to archive variants: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+
 	return nil
 }
 
-// sockVariantExists checks if a sock variant exists for the given sock ID and size
-func (s *SockStore) SockVariantExists(sockID int, size string) (bool, error) {
-	var exists bool
-	query := `SELECT EXISTS (SELECT 1 FROM sock_variants WHERE sock_id = $1 AND size = $2)`
-	err := s.db.QueryRow(query, sockID, size).Scan(&exists)
+// lockSockVersionTx locks a sock and its variants for the rest of the transaction and returns their current `SockVersion`.
+// A deleted or missing sock is reported as a version conflict.
+func lockSockVersionTx(tx *sql.Tx, sockID int) (string, error) {
+	var sock types.Sock
+	err := tx.QueryRow(`SELECT sock_id, version FROM socks WHERE sock_id = $1 AND is_deleted = false FOR UPDATE`, sockID).
+		Scan(&sock.ID, &sock.Version)
 	if err != nil {
-		return false, fmt.Errorf("error checking if sock variant exists: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", types.ErrVersionConflict
+		}
+		log.Printf("Error locking sock ID %v: %v", sockID, err)
+		return "", err
 	}
-	return exists, nil
+
+	rows, err := tx.Query(`
+    SELECT sock_variant_id, version
+    FROM sock_variants
+    WHERE sock_id = $1 AND is_deleted = false
+    ORDER BY sock_variant_id ASC
+    FOR UPDATE
+  `, sockID)
+	if err != nil {
+		log.Printf("Error locking variants for sock ID %v: %v", sockID, err)
+		return "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sv types.SockVariant
+		if err := rows.Scan(&sv.ID, &sv.Version); err != nil {
+			return "", err
+		}
+		sock.Variants = append(sock.Variants, sv)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	return SockVersion(sock), nil
+}
+
+// SockVersion returns the version of a sock used as its ETag. It covers the sock and all of its variants,
+// so it also changes when only stock does (e.g. after a checkout). Variants must be sorted by ID.
+func SockVersion(sock types.Sock) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%d", sock.ID, sock.Version)
+	for _, v := range sock.Variants {
+		fmt.Fprintf(h, ";%d:%d", v.ID, v.Version)
+	}
+	return fmt.Sprintf("%d-%x", sock.Version, h.Sum(nil)[:8])
 }
 
 func (s *SockStore) GetSockVariantByID(sockVariantID int) (*types.SockVariant, error) {
 	var sv types.SockVariant
 	err := s.db.QueryRow(`
-    SELECT sock_variant_id, price, quantity, size, created_at
+    SELECT sock_variant_id, sock_id, price, quantity, reorder_threshold, version, created_at
     FROM sock_variants
     WHERE sock_variant_id = $1
-  `, sockVariantID).Scan(&sv.ID, &sv.Price, &sv.Quantity, &sv.Size, &sv.CreatedAt)
+  `, sockVariantID).Scan(&sv.ID, &sv.SockID, &sv.Price, &sv.Quantity, &sv.ReorderThreshold, &sv.Version, &sv.CreatedAt)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -274,7 +555,11 @@ func (s *SockStore) GetSockVariantByID(sockVariantID int) (*types.SockVariant, e
 		return nil, fmt.Errorf("failed to fetch sock variant with ID %d: %w", sockVariantID, err)
 	}
 
-	return &sv, nil
+	variants := []types.SockVariant{sv}
+	if err := s.loadVariantOptions(variants); err != nil {
+		return nil, err
+	}
+	return &variants[0], nil
 }
 
 func (s *SockStore) GetSockVariantsByID(sockVariantIDs []int) ([]types.SockVariant, error) {
@@ -287,7 +572,7 @@ func (s *SockStore) GetSockVariantsByID(sockVariantIDs []int) ([]types.SockVaria
 		placeholders[i] = fmt.Sprintf("$%d", i+1)
 	}
 
-	query := fmt.Sprintf("SELECT sock_variant_id, size, price, quantity, created_at FROM sock_variants WHERE sock_variant_id IN (%s)", strings.Join(placeholders, ", "))
+	query := fmt.Sprintf("SELECT sock_variant_id, sock_id, price, quantity, reorder_threshold, version, created_at FROM sock_variants WHERE sock_variant_id IN (%s) AND is_deleted = false", strings.Join(placeholders, ", "))
 	args := make([]any, len(sockVariantIDs))
 	for i, svID := range sockVariantIDs {
 		args[i] = svID
@@ -302,48 +587,51 @@ func (s *SockStore) GetSockVariantsByID(sockVariantIDs []int) ([]types.SockVaria
 	sockVariants := make([]types.SockVariant, 0)
 	for rows.Next() {
 		var sv types.SockVariant
-		if err := rows.Scan(&sv.ID, &sv.Size, &sv.Price, &sv.Quantity, &sv.CreatedAt); err != nil {
+		if err := rows.Scan(&sv.ID, &sv.SockID, &sv.Price, &sv.Quantity, &sv.ReorderThreshold, &sv.Version, &sv.CreatedAt); err != nil {
 			return nil, err
 		}
 		sockVariants = append(sockVariants, sv)
 	}
 
-	return sockVariants, nil
-}
-
-func (s *SockStore) UpdateSockVariantQuantity(sockVariantID int, newQuantity int) error {
-	sv, err := s.GetSockVariantByID(sockVariantID)
-	if err != nil {
-		return err
-	}
-	if sv == nil {
-		return fmt.Errorf("sock variant with ID %v does not exist", sockVariantID)
-	}
-
-	res, err := s.db.Exec("UPDATE sock_variants SET quantity = $1 WHERE sock_variant_id = $2", newQuantity, sockVariantID)
-	if err != nil {
-		return err
-	}
-
-	val, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if val == 0 {
-		return fmt.Errorf("no rows were affected")
+	if err := s.loadVariantOptions(sockVariants); err != nil {
+		return nil, err
 	}
-
-	return nil
+	return sockVariants, nil
 }
 
+// GetSimilarSocks returns up to 6 in-stock socks related to the given one. Socks sharing more tags rank higher,
+// followed by socks of the same category, then of a parent, child or sibling category. Newer socks break ties.
 func (s *SockStore) GetSimilarSocks(sockID int) ([]types.SimilarSock, error) {
 	query := `
-    SELECT s.sock_id, s.name, s.preview_image_url, MIN(sv.price) AS price, s.created_at
+    WITH target AS (
+      SELECT s.sock_id, s.category_id, c.parent_id
+      FROM socks s
+      LEFT JOIN categories c ON c.category_id = s.category_id
+      WHERE s.sock_id = $1
+    )
+    SELECT s.sock_id, s.name, s.preview_image_url, p.price, s.created_at
     FROM socks s
-    JOIN sock_variants sv ON sv.sock_id = s.sock_id
-    WHERE s.sock_id != $1 AND s.is_deleted = false AND sv.quantity > 0
-    GROUP BY s.sock_id
-    HAVING COUNT(sv.quantity) > 0
+    CROSS JOIN target
+    LEFT JOIN categories c ON c.category_id = s.category_id
+    CROSS JOIN LATERAL (
+      SELECT MIN(sv.price) AS price
+      FROM sock_variants sv
+      WHERE sv.sock_id = s.sock_id AND sv.is_deleted = false AND sv.quantity > 0
+    ) p
+    WHERE s.sock_id != target.sock_id AND s.is_deleted = false AND p.price IS NOT NULL
+    ORDER BY
+      2 * (
+        SELECT COUNT(*)
+        FROM sock_tags st
+        JOIN sock_tags tt ON tt.tag_id = st.tag_id AND tt.sock_id = target.sock_id
+        WHERE st.sock_id = s.sock_id
+      )
+      + CASE
+          WHEN s.category_id = target.category_id THEN 3
+          WHEN c.category_id = target.parent_id OR c.parent_id = target.category_id OR c.parent_id = target.parent_id THEN 1
+          ELSE 0
+        END DESC,
+      s.created_at DESC, s.sock_id DESC
     LIMIT 6
   `
 	rows, err := s.db.Query(query, sockID)
@@ -0,0 +1,198 @@
+package inventory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+// upsertVariantTx creates the variant of a sock matching the options, or updates and restores the existing one.
+// Stock changes are recorded in the ledger: as a restock for a new variant, or as a correction otherwise.
+// It returns the options key of the variant, which is unique per sock. Keys already in `seen` are reported as
+// `types.ErrDuplicateVariant`.
+func upsertVariantTx(tx *sql.Tx, sockID int, variant types.SockVariant, adminID int, seen map[string]bool) (string, error) {
+	key, valueIDs, err := resolveVariantOptionsTx(tx, variant.Options)
+	if err != nil {
+		return "", err
+	}
+	if seen[key] {
+		return "", types.ErrDuplicateVariant
+	}
+	seen[key] = true
+
+	reason := types.MovementCorrection
+	var previousQuantity int
+	err = tx.QueryRow(
+		"SELECT quantity FROM sock_variants WHERE sock_id = $1 AND options_key = $2 FOR UPDATE",
+		sockID, key,
+	).Scan(&previousQuantity)
+	if errors.Is(err, sql.ErrNoRows) {
+		reason = types.MovementRestock
+	} else if err != nil {
+		return "", fmt.Errorf("failed to fetch variant stock: %w", err)
+	}
+
+	// Re-adding the options of an archived variant restores it
+	var variantID int
+	err = tx.QueryRow(`
+		INSERT INTO sock_variants (sock_id, price, quantity, reorder_threshold, options_key)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (sock_id, options_key) DO UPDATE
+		SET price = EXCLUDED.price, quantity = EXCLUDED.quantity, reorder_threshold = EXCLUDED.reorder_threshold,
+			is_deleted = false, version = sock_variants.version + 1
+		RETURNING sock_variant_id`,
+		sockID, variant.Price, variant.Quantity, variant.ReorderThreshold, key).Scan(&variantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to upsert variant: %w", err)
+	}
+
+	for _, valueID := range valueIDs {
+		_, err = tx.Exec(`
+			INSERT INTO sock_variant_options (sock_variant_id, attribute_value_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`,
+			variantID, valueID)
+		if err != nil {
+			return "", fmt.Errorf("failed to set variant options: %w", err)
+		}
+	}
+
+	if delta := variant.Quantity - previousQuantity; delta != 0 {
+		err = RecordMovementTx(tx, types.InventoryMovement{
+			SockVariantID: variantID,
+			Delta:         delta,
+			QuantityAfter: variant.Quantity,
+			Reason:        reason,
+			AdminID:       adminRef(adminID),
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return key, nil
+}
+
+// resolveVariantOptionsTx looks up the attribute values of the given options. Attribute codes and values are matched
+// case insensitively. The key is made of the sorted value IDs, so the same options always give the same key.
+func resolveVariantOptionsTx(tx *sql.Tx, options map[string]string) (key string, valueIDs []int, err error) {
+	for code, value := range options {
+		var valueID int
+		err := tx.QueryRow(`
+			SELECT av.attribute_value_id
+			FROM attribute_values av
+			JOIN attributes a ON a.attribute_id = av.attribute_id
+			WHERE a.code = $1 AND lower(av.value) = lower($2)`,
+			utils.Normalize(code), strings.TrimSpace(value)).Scan(&valueID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", nil, fmt.Errorf("%w: %s %q", types.ErrInvalidVariantOption, code, value)
+			}
+			return "", nil, fmt.Errorf("failed to resolve variant option %s: %w", code, err)
+		}
+		valueIDs = append(valueIDs, valueID)
+	}
+
+	sort.Ints(valueIDs)
+	parts := make([]string, len(valueIDs))
+	for i, id := range valueIDs {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, ","), valueIDs, nil
+}
+
+type variantOptions struct {
+	values      map[string]string
+	description string
+}
+
+// getVariantOptions returns the options of the given variants by variant ID, in attribute order.
+func (s *SockStore) getVariantOptions(sockVariantIDs []int) (map[int]variantOptions, error) {
+	result := make(map[int]variantOptions, len(sockVariantIDs))
+	if len(sockVariantIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.db.Query(`
+    SELECT o.sock_variant_id, a.code, a.name, av.value
+    FROM sock_variant_options o
+    JOIN attribute_values av ON av.attribute_value_id = o.attribute_value_id
+    JOIN attributes a ON a.attribute_id = av.attribute_id
+    WHERE o.sock_variant_id = ANY($1)
+    ORDER BY a.position ASC, a.attribute_id ASC
+  `, pq.Array(sockVariantIDs))
+	if err != nil {
+		log.Printf("Error fetching variant options: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var variantID int
+		var code, name, value string
+		if err := rows.Scan(&variantID, &code, &name, &value); err != nil {
+			log.Printf("Error scanning variant option: %v", err)
+			return nil, err
+		}
+
+		options, ok := result[variantID]
+		if !ok {
+			options.values = make(map[string]string)
+		} else {
+			options.description += ", "
+		}
+		options.values[code] = value
+		options.description += name + ": " + value
+		result[variantID] = options
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// loadVariantOptions sets the options and description of the given variants.
+func (s *SockStore) loadVariantOptions(variants []types.SockVariant) error {
+	ids := make([]int, len(variants))
+	for i, v := range variants {
+		ids[i] = v.ID
+	}
+
+	options, err := s.getVariantOptions(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range variants {
+		o := options[variants[i].ID]
+		variants[i].Options = o.values
+		if variants[i].Options == nil {
+			variants[i].Options = map[string]string{}
+		}
+		variants[i].Description = o.description
+	}
+	return nil
+}
+
+// GetVariantDescriptions returns the description of each variant by ID. Variants without options have an empty description.
+func (s *SockStore) GetVariantDescriptions(sockVariantIDs []int) (map[int]string, error) {
+	options, err := s.getVariantOptions(sockVariantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions := make(map[int]string, len(options))
+	for id, o := range options {
+		descriptions[id] = o.description
+	}
+	return descriptions, nil
+}
@@ -21,7 +21,7 @@ func NewHandler(store types.NewsletterStore) *Handler {
 func (h *Handler) RegisterRoutes(router *mux.Router, adminStore types.AdminStore) {
 	router.HandleFunc("/newsletter/subscribe", h.handleSubscribe).Methods(http.MethodPost)
 	router.HandleFunc("/newsletter/unsubscribe", h.handleUnsubscribe).Methods(http.MethodPost)
-	router.HandleFunc("/newsletter/emails", middleware.WithJWTAuth(adminStore, h.handleGetEmails)).Methods(http.MethodGet)
+	router.HandleFunc("/newsletter/emails", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetEmails)).Methods(http.MethodGet)
 }
 
 // @Summary Subscribes an email to the newsletter
@@ -0,0 +1,83 @@
+package orders
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+const dateOnlyLayout = "2006-01-02"
+
+// parseOrderFilters reads the order filters from the request's query params.
+func parseOrderFilters(r *http.Request) (types.OrderFilters, error) {
+	query := r.URL.Query()
+	filters := types.OrderFilters{
+		Name:          strings.TrimSpace(query.Get("name")),
+		Email:         strings.TrimSpace(query.Get("email")),
+		Phone:         strings.TrimSpace(query.Get("phone")),
+		InvoicePrefix: strings.TrimSpace(query.Get("invoice")),
+		SortDirection: strings.ToLower(query.Get("sort")),
+	}
+
+	// Accept both `?status=a,b` and `?status=a&status=b`
+	for _, value := range query["status"] {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filters.Statuses = append(filters.Statuses, status)
+			}
+		}
+	}
+
+	var err error
+	if filters.CreatedFrom, _, err = parseDateParam(query.Get("created_from")); err != nil {
+		return filters, fmt.Errorf("invalid created_from: %v", err)
+	}
+
+	createdTo, dateOnly, err := parseDateParam(query.Get("created_to"))
+	if err != nil {
+		return filters, fmt.Errorf("invalid created_to: %v", err)
+	}
+	if createdTo != nil && dateOnly {
+		// Include the whole day
+		endOfDay := createdTo.AddDate(0, 0, 1)
+		createdTo = &endOfDay
+	}
+	filters.CreatedTo = createdTo
+
+	if filters.MinTotal, err = utils.ParseOptionalFloat(query.Get("min_total")); err != nil {
+		return filters, fmt.Errorf("invalid min_total: %v", err)
+	}
+	if filters.MaxTotal, err = utils.ParseOptionalFloat(query.Get("max_total")); err != nil {
+		return filters, fmt.Errorf("invalid max_total: %v", err)
+	}
+	if filters.MinTotal != nil && filters.MaxTotal != nil && *filters.MinTotal > *filters.MaxTotal {
+		return filters, errors.New("min_total must be less than or equal to max_total")
+	}
+
+	if err := utils.Validate.Struct(filters); err != nil {
+		return filters, err
+	}
+	return filters, nil
+}
+
+// parseDateParam parses a date (YYYY-MM-DD, as UTC) or an RFC 3339 timestamp. Returns nil when the value is empty.
+func parseDateParam(value string) (t *time.Time, dateOnly bool, err error) {
+	if value == "" {
+		return nil, false, nil
+	}
+
+	if parsed, err := time.Parse(dateOnlyLayout, value); err == nil {
+		return &parsed, true, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, false, errors.New("expected a YYYY-MM-DD date or an RFC 3339 timestamp")
+	}
+	return &parsed, false, nil
+}
@@ -0,0 +1,44 @@
+package orders
+
+import (
+	"fmt"
+
+	"github.com/sockify/sockify/services/email/templates"
+	"github.com/sockify/sockify/types"
+)
+
+// buildStatusNotification renders the email sent to the customer when their order moves to `newStatus`.
+// Returns nil if customers are not notified about the status.
+func buildStatusNotification(order types.Order, newStatus string, message string) *types.OutboundEmail {
+	if !templates.HasOrderStatusUpdateTemplate(newStatus) {
+		return nil
+	}
+
+	plainText, htmlContent := templates.CreateOrderStatusUpdateTemplate(order, newStatus, message)
+	return &types.OutboundEmail{
+		ToName:      fmt.Sprintf("%s %s", order.Contact.FirstName, order.Contact.LastName),
+		ToEmail:     order.Contact.Email,
+		Subject:     templates.OrderStatusUpdateSubject(order.InvoiceNumber, newStatus),
+		PlainText:   plainText,
+		HTMLContent: htmlContent,
+	}
+}
+
+// buildOrderConfirmation renders the email sent to the customer once their order is paid.
+func buildOrderConfirmation(order types.Order) types.OutboundEmail {
+	plainText, htmlContent := templates.CreateOrderConfirmationTemplate(types.OrderConfirmation{
+		InvoiceNumber: order.InvoiceNumber,
+		Status:        order.Status,
+		Total:         order.Total,
+		Address:       order.Address,
+		Items:         order.Items,
+		CreatedAt:     order.CreatedAt,
+	})
+	return types.OutboundEmail{
+		ToName:      fmt.Sprintf("%s %s", order.Contact.FirstName, order.Contact.LastName),
+		ToEmail:     order.Contact.Email,
+		Subject:     fmt.Sprintf("Order confirmation (%s)", order.InvoiceNumber),
+		PlainText:   plainText,
+		HTMLContent: htmlContent,
+	}
+}
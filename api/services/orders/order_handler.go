@@ -1,57 +1,81 @@
 package orders
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/config"
 	"github.com/sockify/sockify/middleware"
 	"github.com/sockify/sockify/types"
 	"github.com/sockify/sockify/utils"
 )
 
 type OrderHandler struct {
-	store types.OrderStore
+	store    types.OrderStore
+	payments types.PaymentGateway
 }
 
-func NewOrderHandler(store types.OrderStore) *OrderHandler {
-	return &OrderHandler{store: store}
+func NewOrderHandler(store types.OrderStore, payments types.PaymentGateway) *OrderHandler {
+	return &OrderHandler{store: store, payments: payments}
 }
 
 func (h *OrderHandler) RegisterRoutes(router *mux.Router, adminStore types.AdminStore) {
-	router.HandleFunc("/orders", middleware.WithJWTAuth(adminStore, h.handleGetOrders)).Methods(http.MethodGet)
-	router.HandleFunc("/orders/{order_id}", middleware.WithJWTAuth(adminStore, h.handleGetOrderById)).Methods(http.MethodGet)
-	router.HandleFunc("/orders/{order_id}/updates", middleware.WithJWTAuth(adminStore, h.handleGetOrderUpdates)).Methods(http.MethodGet)
-	router.HandleFunc("/orders/{order_id}/updates", middleware.WithJWTAuth(adminStore, h.handleCreateOrderUpdate)).Methods(http.MethodPost)
-	router.HandleFunc("/orders/{order_id}/address", middleware.WithJWTAuth(adminStore, h.handleUpdateOrderAddress)).Methods(http.MethodPatch)
-	router.HandleFunc("/orders/{order_id}/status", middleware.WithJWTAuth(adminStore, h.handleUpdateOrderStatus)).Methods(http.MethodPatch)
-	router.HandleFunc("/orders/{order_id}/contact", middleware.WithJWTAuth(adminStore, h.handleUpdateOrderContact)).Methods(http.MethodPatch)
-	router.HandleFunc("/orders/invoice/{invoice_number}", middleware.WithJWTAuth(adminStore, h.handleGetOrderByInvoice)).Methods(http.MethodGet)
+	router.HandleFunc("/orders", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetOrders)).Methods(http.MethodGet)
+	router.HandleFunc("/orders/{order_id}", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetOrderById)).Methods(http.MethodGet)
+	router.HandleFunc("/orders/{order_id}/updates", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetOrderUpdates)).Methods(http.MethodGet)
+	router.HandleFunc("/orders/{order_id}/updates", middleware.WithRole(adminStore, middleware.FulfillmentStaff, h.handleCreateOrderUpdate)).Methods(http.MethodPost)
+	router.HandleFunc("/orders/{order_id}/address", middleware.WithRole(adminStore, middleware.FulfillmentStaff, h.handleUpdateOrderAddress)).Methods(http.MethodPatch)
+	router.HandleFunc("/orders/{order_id}/status", middleware.WithRole(adminStore, middleware.FulfillmentStaff, h.handleUpdateOrderStatus)).Methods(http.MethodPatch)
+	router.HandleFunc("/orders/{order_id}/contact", middleware.WithRole(adminStore, middleware.FulfillmentStaff, h.handleUpdateOrderContact)).Methods(http.MethodPatch)
+	router.HandleFunc("/orders/{order_id}/refunds", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetRefunds)).Methods(http.MethodGet)
+	router.HandleFunc("/orders/{order_id}/refunds", middleware.WithRole(adminStore, middleware.OwnerOnly, h.handleCreateRefund)).Methods(http.MethodPost)
+	router.HandleFunc("/orders/invoice/{invoice_number}", middleware.WithRole(adminStore, middleware.AllAdminRoles, h.handleGetOrderByInvoice)).Methods(http.MethodGet)
+
+	trackingLimiter := middleware.NewRateLimiter(int(config.Envs.TrackingRateLimitPerMinute), time.Minute)
+	router.HandleFunc("/orders/tracking", middleware.WithRateLimit(trackingLimiter, h.handleTrackOrder)).Methods(http.MethodPost)
 }
 
 // @Summary Retrieve all orders
-// @Description Retrieves all orders from the database with optional filters. Results are returned oldest to newest by created date.
+// @Description Retrieves all orders from the database with optional filters. Results are returned oldest to newest by created date unless `sort=desc`.
 // @Tags Orders
 // @Produce json
 // @Security Bearer
 // @Param limit query int false "Limit the number of results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
-// @Param status query string false "Status of the order"
+// @Param status query []string false "Statuses of the order (comma separated or repeated)" collectionFormat(csv)
+// @Param name query string false "Partial customer name"
+// @Param email query string false "Partial customer email"
+// @Param phone query string false "Partial customer phone"
+// @Param invoice query string false "Invoice number prefix"
+// @Param created_from query string false "Created on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before this date (YYYY-MM-DD, inclusive) or before this time (RFC 3339)"
+// @Param min_total query number false "Minimum order total"
+// @Param max_total query number false "Maximum order total"
+// @Param sort query string false "Sort direction by created date" Enums(asc, desc) default(asc)
 // @Success 200 {object} types.OrdersPaginatedResponse
 // @Router /orders [get]
 func (h *OrderHandler) handleGetOrders(w http.ResponseWriter, r *http.Request) {
 	limit, offset := utils.GetLimitOffset(r, 50, 0)
-	status := r.URL.Query().Get("status")
 
-	orders, err := h.store.GetOrders(limit, offset, status)
+	filters, err := parseOrderFilters(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	orders, err := h.store.GetOrders(limit, offset, filters)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	total, err := h.store.CountOrders(status)
+	total, err := h.store.CountOrders(filters)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -66,7 +90,7 @@ func (h *OrderHandler) handleGetOrders(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Retrieve details for an order
-// @Description Retrieves all the details for a particular order.
+// @Description Retrieves all the details for a particular order. The "ETag" header identifies the current version of the order, to be sent back in "If-Match" when editing it.
 // @Tags Orders
 // @Produce json
 // @Security Bearer
@@ -89,10 +113,11 @@ func (h *OrderHandler) handleGetOrderById(w http.ResponseWriter, r *http.Request
 		return
 	}
 	if order == nil {
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("order with ID %v does not exist", orderID))
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order with ID %v does not exist", orderID))
 		return
 	}
 
+	utils.SetETag(w, strconv.Itoa(order.Version))
 	utils.WriteJson(w, http.StatusOK, order)
 }
 
@@ -177,7 +202,8 @@ func (h *OrderHandler) handleCreateOrderUpdate(w http.ResponseWriter, r *http.Re
 	}
 
 	adminID := middleware.GetUserIDFromContext(r.Context())
-	if err := h.store.CreateOrderUpdate(orderID, adminID, req.Message); err != nil {
+	isInternal := req.IsInternal == nil || *req.IsInternal
+	if err := h.store.CreateOrderUpdate(orderID, adminID, req.Message, isInternal); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -186,12 +212,13 @@ func (h *OrderHandler) handleCreateOrderUpdate(w http.ResponseWriter, r *http.Re
 }
 
 // @Summary Update the address of an existing order
-// @Description Updates the address for a specific order by ID
+// @Description Updates the address for a specific order by ID. The "If-Match" header must be set to the "ETag" returned when the order was fetched; the update is rejected with a 412 if the order changed since, and with a 428 if the header is missing.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param order_id path int true "Order ID"
+// @Param If-Match header string true "ETag of the order being edited"
 // @Param address body types.UpdateAddressRequest true "New Address Data"
 // @Success 200 {object} types.Message
 // @Router /orders/{order_id}/address [patch]
@@ -216,6 +243,11 @@ func (h *OrderHandler) handleUpdateOrderAddress(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	version, ok := orderVersionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
 	var req types.UpdateAddressRequest
 	if err := utils.ParseJson(r, &req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
@@ -228,7 +260,11 @@ func (h *OrderHandler) handleUpdateOrderAddress(w http.ResponseWriter, r *http.R
 	}
 
 	adminID := middleware.GetUserIDFromContext(r.Context())
-	if err := h.store.UpdateOrderAddress(orderID, req, adminID); err != nil {
+	if err := h.store.UpdateOrderAddress(orderID, version, req, adminID); err != nil {
+		if errors.Is(err, types.ErrVersionConflict) {
+			utils.WriteError(w, http.StatusPreconditionFailed, err)
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -237,7 +273,7 @@ func (h *OrderHandler) handleUpdateOrderAddress(w http.ResponseWriter, r *http.R
 }
 
 // @Summary Update the status of an existing order
-// @Description Updates the status for a specific order by ID
+// @Description Updates the status for a specific order by ID. The customer is emailed when the order is shipped, delivered, canceled or returned. Canceled orders return their items to stock. Rejected with a 409 when the status was changed by someone else in the meantime.
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -258,7 +294,11 @@ func (h *OrderHandler) handleUpdateOrderStatus(w http.ResponseWriter, r *http.Re
 
 	currentStatus, err := h.store.GetOrderStatusByID(orderID)
 	if err != nil {
-		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("no order status found for orderID %v: %v", orderID, err))
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order with ID %v not found", orderID))
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -279,21 +319,34 @@ func (h *OrderHandler) handleUpdateOrderStatus(w http.ResponseWriter, r *http.Re
 	}
 
 	adminID := middleware.GetUserIDFromContext(r.Context())
-	if err := h.store.UpdateOrderStatus(orderID, adminID, req.NewStatus, req.Message); err != nil {
+	// Only applied if nobody else changed the status since it was checked
+	var updated bool
+	if req.NewStatus == "canceled" {
+		// The stock reserved at checkout goes back on sale
+		updated, err = h.store.CancelOrder(orderID, adminID, currentStatus, req.Message)
+	} else {
+		updated, err = h.store.UpdateOrderStatusIf(orderID, adminID, currentStatus, req.NewStatus, req.Message)
+	}
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
+	if !updated {
+		utils.WriteError(w, http.StatusConflict, types.ErrVersionConflict)
+		return
+	}
 
 	utils.WriteJson(w, http.StatusOK, types.Message{Message: "Order status updated successfully"})
 }
 
 // @Summary Update the contact information of an existing order
-// @Description Updates the contact information (name, email, phone) for a specific order by ID
+// @Description Updates the contact information (name, email, phone) for a specific order by ID. The "If-Match" header must be set to the "ETag" returned when the order was fetched; the update is rejected with a 412 if the order changed since, and with a 428 if the header is missing.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param order_id path int true "Order ID"
+// @Param If-Match header string true "ETag of the order being edited"
 // @Param contact body types.UpdateContactRequest true "New Contact Information"
 // @Success 200 {object} types.Message
 // @Router /orders/{order_id}/contact [patch]
@@ -318,6 +371,11 @@ func (h *OrderHandler) handleUpdateOrderContact(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	version, ok := orderVersionFromRequest(w, r)
+	if !ok {
+		return
+	}
+
 	var req types.UpdateContactRequest
 	if err := utils.ParseJson(r, &req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
@@ -330,7 +388,11 @@ func (h *OrderHandler) handleUpdateOrderContact(w http.ResponseWriter, r *http.R
 	}
 
 	adminID := middleware.GetUserIDFromContext(r.Context())
-	if err := h.store.UpdateOrderContact(orderID, req, adminID); err != nil {
+	if err := h.store.UpdateOrderContact(orderID, version, req, adminID); err != nil {
+		if errors.Is(err, types.ErrVersionConflict) {
+			utils.WriteError(w, http.StatusPreconditionFailed, err)
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -360,9 +422,129 @@ func (h *OrderHandler) handleGetOrderByInvoice(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	utils.SetETag(w, strconv.Itoa(order.Version))
 	utils.WriteJson(w, http.StatusOK, order)
 }
 
+// @Summary Retrieve refunds for an order
+// @Description Retrieves all refunds issued for a particular order. Results are sorted descending by createdAt.
+// @Tags Orders
+// @Produce json
+// @Security Bearer
+// @Param order_id path int true "Order ID"
+// @Success 200 {array} types.OrderRefund
+// @Router /orders/{order_id}/refunds [get]
+func (h *OrderHandler) handleGetRefunds(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderIDstr := vars["order_id"]
+
+	orderID, err := strconv.Atoi(orderIDstr)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid order ID"))
+		return
+	}
+
+	exists, err := h.store.OrderExistsByID(orderID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !exists {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order with ID %v not found", orderID))
+		return
+	}
+
+	refunds, err := h.store.GetRefunds(orderID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, refunds)
+}
+
+// @Summary Refunds an order
+// @Description Refunds an entire order (no items provided) or specific items through the payment provider. Refunded items can optionally be returned to stock. Fully refunded orders are moved to "canceled" (if not shipped yet) or "returned" (if delivered). The refund is recorded as pending before it is issued, so its items can not be refunded twice (409); it is marked as failed when the payment provider declines it (502).
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param order_id path int true "Order ID"
+// @Param refund body types.CreateRefundRequest true "Refund details"
+// @Success 201 {object} types.OrderRefund
+// @Router /orders/{order_id}/refunds [post]
+func (h *OrderHandler) handleCreateRefund(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orderIDstr := vars["order_id"]
+
+	orderID, err := strconv.Atoi(orderIDstr)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid order ID"))
+		return
+	}
+
+	var req types.CreateRefundRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	order, err := h.store.GetOrderById(orderID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if order == nil {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("order with ID %v not found", orderID))
+		return
+	}
+
+	refund, err := buildRefund(*order, req)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The refund is reserved before calling the payment provider, so concurrent or retried requests can not refund
+	// the same items twice.
+	adminID := middleware.GetUserIDFromContext(r.Context())
+	refund.ID, err = h.store.CreateRefund(orderID, adminID, *refund)
+	if err != nil {
+		if errors.Is(err, types.ErrAlreadyRefunded) {
+			utils.WriteError(w, http.StatusConflict, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	refund.ProviderRefundID, err = h.payments.Refund(*order.PaymentReference, refund.Amount, refundIdempotencyKey(refund.ID))
+	if err != nil {
+		log.Printf("Unable to refund $%.2f for order ID %v through the payment provider: %v", refund.Amount, orderID, err)
+		if err := h.store.FailRefund(refund.ID); err != nil {
+			log.Printf("[ERROR] refund ID %v was declined by the payment provider but is still pending: %v", refund.ID, err)
+		}
+		utils.WriteError(w, http.StatusBadGateway, fmt.Errorf("unable to issue the refund through the payment provider"))
+		return
+	}
+
+	err = h.store.CompleteRefund(orderID, adminID, *refund)
+	if err != nil {
+		log.Printf("[ERROR] refund %v of $%.2f was issued for order ID %v but refund ID %v is still pending: %v", refund.ProviderRefundID, refund.Amount, orderID, refund.ID, err)
+		utils.WriteError(w, http.StatusInternalServerError, fmt.Errorf("the refund was issued but could not be recorded"))
+		return
+	}
+	refund.Status = types.RefundStatusCompleted
+
+	utils.WriteJson(w, http.StatusCreated, refund)
+}
+
 func isValidStatusUpdate(currentStatus string, newStatus string) error {
 	if newStatus == "" {
 		return fmt.Errorf("the new status can not be empty")
@@ -384,5 +566,82 @@ func isValidStatusUpdate(currentStatus string, newStatus string) error {
 		return nil
 	}
 
-	return fmt.Errorf("order status can not change from '%v' to '%v'", currentStatus, newStatus)
+	return types.NewError("invalid_status_transition", fmt.Sprintf("order status can not change from '%v' to '%v'", currentStatus, newStatus))
+}
+
+// @Summary Track an order
+// @Description Retrieves a customer-safe view of an order using the invoice number and the contact email on the order. Internal order updates are omitted. Requests are rate limited per IP.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param tracking body types.TrackOrderRequest true "Invoice number and contact email"
+// @Success 200 {object} types.OrderTracking
+// @Router /orders/tracking [post]
+func (h *OrderHandler) handleTrackOrder(w http.ResponseWriter, r *http.Request) {
+	var req types.TrackOrderRequest
+	if err := utils.ParseJson(r, &req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	order, err := h.store.GetOrderByInvoiceAndEmail(utils.Normalize(req.InvoiceNumber), req.Email)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// Same response whether the invoice or the email is wrong, so neither can be probed on its own
+	if order == nil {
+		utils.WriteError(w, http.StatusNotFound, errors.New("no order found for the given invoice number and email"))
+		return
+	}
+
+	updates, err := h.store.GetOrderUpdates(order.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, buildOrderTracking(*order, updates))
+}
+
+func buildOrderTracking(order types.Order, updates []types.OrderUpdate) types.OrderTracking {
+	publicUpdates := []types.OrderTrackingUpdate{}
+	for _, u := range updates {
+		if u.IsInternal {
+			continue
+		}
+		publicUpdates = append(publicUpdates, types.OrderTrackingUpdate{Message: u.Message, CreatedAt: u.CreatedAt})
+	}
+
+	return types.OrderTracking{
+		InvoiceNumber: order.InvoiceNumber,
+		Status:        order.Status,
+		Total:         order.Total,
+		Address:       order.Address,
+		Items:         order.Items,
+		Updates:       publicUpdates,
+		CreatedAt:     order.CreatedAt,
+	}
+}
+
+// orderVersionFromRequest returns the order version from the `If-Match` header, writing an error response if it is missing.
+// A tag that is not a version can never match, so it is rejected the same way as an outdated one.
+func orderVersionFromRequest(w http.ResponseWriter, r *http.Request) (version int, ok bool) {
+	tag, err := utils.IfMatch(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusPreconditionRequired, err)
+		return 0, false
+	}
+
+	version, err = strconv.Atoi(tag)
+	if err != nil {
+		utils.WriteError(w, http.StatusPreconditionFailed, types.ErrVersionConflict)
+		return 0, false
+	}
+	return version, true
 }
@@ -0,0 +1,59 @@
+package orders
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sockify/sockify/types"
+)
+
+// fakeOrderStore keeps a single order in memory. Only the methods used for tracking are implemented.
+type fakeOrderStore struct {
+	types.OrderStore
+	order types.Order
+}
+
+func (s *fakeOrderStore) GetOrderByInvoiceAndEmail(invoiceNumber string, email string) (*types.Order, error) {
+	// Invoice numbers are stored lowercase, and emails are compared case-insensitively
+	if invoiceNumber != s.order.InvoiceNumber || !strings.EqualFold(email, s.order.Contact.Email) {
+		return nil, nil
+	}
+	o := s.order
+	return &o, nil
+}
+
+func (s *fakeOrderStore) GetOrderUpdates(orderID int) ([]types.OrderUpdate, error) {
+	return []types.OrderUpdate{}, nil
+}
+
+func trackOrder(h *OrderHandler, req types.TrackOrderRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	rr := httptest.NewRecorder()
+	h.handleTrackOrder(rr, httptest.NewRequest(http.MethodPost, "/orders/tracking", bytes.NewReader(body)))
+	return rr
+}
+
+func TestTrackOrderNormalizesInvoiceNumber(t *testing.T) {
+	store := &fakeOrderStore{order: types.Order{
+		ID:            42,
+		InvoiceNumber: "3f1c9a2e-inv",
+		Status:        "shipped",
+		Contact:       types.Contact{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"},
+	}}
+	h := NewOrderHandler(store, nil)
+
+	for _, invoiceNumber := range []string{"3f1c9a2e-inv", "3F1C9A2E-INV", "  3f1c9a2e-inv "} {
+		rr := trackOrder(h, types.TrackOrderRequest{InvoiceNumber: invoiceNumber, Email: "Jane@Example.com"})
+		if rr.Code != http.StatusOK {
+			t.Errorf("invoice number %q: expected status %d, got %d: %s", invoiceNumber, http.StatusOK, rr.Code, rr.Body.String())
+		}
+	}
+
+	if rr := trackOrder(h, types.TrackOrderRequest{InvoiceNumber: "00000000-inv", Email: "jane@example.com"}); rr.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown invoice number, got %d", http.StatusNotFound, rr.Code)
+	}
+}
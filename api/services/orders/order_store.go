@@ -5,11 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"log"
-
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sockify/sockify/database"
+	"github.com/sockify/sockify/services/email"
+	"github.com/sockify/sockify/services/email/templates"
+	"github.com/sockify/sockify/services/inventory"
 	"github.com/sockify/sockify/types"
 	"github.com/sockify/sockify/utils"
 )
 
+// orderColumns are the columns read by `scanRowIntoOrder`, in order.
+const orderColumns = `order_id, invoice_number, total_price, status,
+    firstname, lastname, email, phone,
+    street, apt_unit, city, state, zipcode,
+    payment_reference, customer_id, version, created_at`
+
 type OrderStore struct {
 	db        *sql.DB
 	sockStore types.SockStore
@@ -19,39 +34,30 @@ func NewOrderStore(db *sql.DB, ss types.SockStore) types.OrderStore {
 	return &OrderStore{db: db, sockStore: ss}
 }
 
-// GetOrders retrieves orders filtered by status (optional) from the database.
-func (s *OrderStore) GetOrders(limit int, offset int, status string) ([]types.Order, error) {
+// GetOrders retrieves orders matching the filters from the database. Results are sorted by created date, oldest first by default.
+func (s *OrderStore) GetOrders(limit int, offset int, filters types.OrderFilters) ([]types.Order, error) {
 	var orders []types.Order
-	var query string
-	var rows *sql.Rows
-	var err error
 
-	if status == "" {
-		query = "SELECT * FROM orders ORDER BY created_at ASC LIMIT $1 OFFSET $2"
-		rows, err = s.db.Query(query, limit, offset)
-	} else {
-		query = "SELECT * FROM orders WHERE status = $1 ORDER BY created_at ASC LIMIT $2 OFFSET $3"
-		rows, err = s.db.Query(query, status, limit, offset)
+	direction := "ASC"
+	if filters.SortDirection == "desc" {
+		direction = "DESC"
 	}
 
+	where := buildOrderFilters(filters)
+	query := fmt.Sprintf(
+		"SELECT %s FROM orders %s ORDER BY created_at %s, order_id %s LIMIT %s OFFSET %s",
+		orderColumns, where.Clause(), direction, direction, where.Arg(limit), where.Arg(offset),
+	)
+	rows, err := s.db.Query(query, where.Args()...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return orders, nil
-		}
-		log.Printf("Error fetching orders by status: %v", err)
+		log.Printf("Error fetching orders: %v", err)
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var order types.Order
-
-		if err := rows.Scan(
-			&order.ID, &order.InvoiceNumber, &order.Total, &order.Status,
-			&order.Contact.FirstName, &order.Contact.LastName, &order.Contact.Email, &order.Contact.Phone,
-			&order.Address.Street, &order.Address.AptUnit, &order.Address.City, &order.Address.State, &order.Address.Zipcode,
-			&order.CreatedAt,
-		); err != nil {
+		order, err := scanRowIntoOrder(rows)
+		if err != nil {
 			return nil, err
 		}
 
@@ -61,21 +67,14 @@ func (s *OrderStore) GetOrders(limit int, offset int, status string) ([]types.Or
 		}
 
 		order.Items = items
-		orders = append(orders, order)
+		orders = append(orders, *order)
 	}
 
 	return orders, nil
 }
 
 func (s *OrderStore) GetOrderById(orderID int) (*types.Order, error) {
-	var order types.Order
-	err := s.db.QueryRow("SELECT * FROM orders WHERE order_id = $1",
-		orderID).Scan(&order.ID, &order.InvoiceNumber, &order.Total, &order.Status,
-		&order.Contact.FirstName, &order.Contact.LastName, &order.Contact.Email, &order.Contact.Phone,
-		&order.Address.Street, &order.Address.AptUnit, &order.Address.City, &order.Address.State, &order.Address.Zipcode,
-		&order.CreatedAt,
-	)
-
+	order, err := scanRowIntoOrder(s.db.QueryRow("SELECT "+orderColumns+" FROM orders WHERE order_id = $1", orderID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -89,16 +88,19 @@ func (s *OrderStore) GetOrderById(orderID int) (*types.Order, error) {
 	}
 	order.Items = items
 
-	return &order, nil
+	return order, nil
 }
 
+// GetOrderItems returns the items of an order. Variant descriptions are the ones at checkout, so later changes to the
+// variant options never rewrite past orders.
 func (s *OrderStore) GetOrderItems(orderID int) ([]types.OrderItem, error) {
 	rows, err := s.db.Query(`
-		SELECT oi.price, oi.quantity, sv.size, sv.sock_variant_id, s.name
+		SELECT oi.order_item_id, oi.price, oi.quantity, sv.sock_variant_id, s.name, oi.variant_description, `+refundedQuantityQuery+`
 		FROM order_items oi
 		JOIN sock_variants sv ON sv.sock_variant_id = oi.sock_variant_id
 		JOIN socks s ON s.sock_id = sv.sock_id
 		WHERE oi.order_id = $1
+		ORDER BY oi.order_item_id ASC
 	`, orderID)
 
 	if err != nil {
@@ -111,22 +113,21 @@ func (s *OrderStore) GetOrderItems(orderID int) ([]types.OrderItem, error) {
 	for rows.Next() {
 		var oi types.OrderItem
 
-		if err := rows.Scan(&oi.Price, &oi.Quantity, &oi.Size, &oi.SockVariantID, &oi.Name); err != nil {
+		if err := rows.Scan(&oi.ID, &oi.Price, &oi.Quantity, &oi.SockVariantID, &oi.Name, &oi.VariantDescription, &oi.RefundedQuantity); err != nil {
 			return nil, err
 		}
 		items = append(items, oi)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
 	return items, nil
 }
 
-func (s *OrderStore) CountOrders(status string) (total int, err error) {
-	if status == "" {
-		err = s.db.QueryRow(`SELECT COUNT(*) FROM orders`).Scan(&total)
-	} else {
-		err = s.db.QueryRow(`SELECT COUNT(*) FROM orders WHERE status = $1`, status).Scan(&total)
-	}
-
+func (s *OrderStore) CountOrders(filters types.OrderFilters) (total int, err error) {
+	where := buildOrderFilters(filters)
+	err = s.db.QueryRow("SELECT COUNT(*) FROM orders "+where.Clause(), where.Args()...).Scan(&total)
 	if err != nil {
 		log.Printf("Error counting orders: %v", err)
 		return 0, err
@@ -134,7 +135,48 @@ func (s *OrderStore) CountOrders(status string) (total int, err error) {
 	return total, nil
 }
 
-func (s *OrderStore) UpdateOrderAddress(orderID int, address types.UpdateAddressRequest, adminID int) error {
+// buildOrderFilters builds the conditions shared by `GetOrders` and `CountOrders`.
+func buildOrderFilters(filters types.OrderFilters) *database.WhereBuilder {
+	where := &database.WhereBuilder{}
+
+	if filters.CustomerID != nil {
+		where.Where("customer_id = " + where.Arg(*filters.CustomerID))
+	}
+	if len(filters.Statuses) > 0 {
+		where.Where(fmt.Sprintf("status = ANY(%s::order_status[])", where.Arg(pq.Array(filters.Statuses))))
+	}
+	if filters.Name != "" {
+		where.Where(fmt.Sprintf("(firstname || ' ' || lastname) ILIKE %s", where.Arg("%"+database.EscapeLike(filters.Name)+"%")))
+	}
+	if filters.Email != "" {
+		where.Where(fmt.Sprintf("email ILIKE %s", where.Arg("%"+database.EscapeLike(filters.Email)+"%")))
+	}
+	if filters.Phone != "" {
+		where.Where(fmt.Sprintf("phone LIKE %s", where.Arg("%"+database.EscapeLike(filters.Phone)+"%")))
+	}
+	if filters.InvoicePrefix != "" {
+		// Invoice numbers are lowercase UUIDs
+		where.Where(fmt.Sprintf("invoice_number LIKE %s", where.Arg(database.EscapeLike(strings.ToLower(filters.InvoicePrefix))+"%")))
+	}
+	if filters.CreatedFrom != nil {
+		where.Where("created_at >= " + where.Arg(filters.CreatedFrom.UTC()))
+	}
+	if filters.CreatedTo != nil {
+		where.Where("created_at < " + where.Arg(filters.CreatedTo.UTC()))
+	}
+	if filters.MinTotal != nil {
+		where.Where("total_price >= " + where.Arg(*filters.MinTotal))
+	}
+	if filters.MaxTotal != nil {
+		where.Where("total_price <= " + where.Arg(*filters.MaxTotal))
+	}
+
+	return where
+}
+
+// UpdateOrderAddress updates the shipping address of an order and logs the update.
+// Returns `types.ErrVersionConflict` if the order is no longer at the given version.
+func (s *OrderStore) UpdateOrderAddress(orderID int, version int, address types.UpdateAddressRequest, adminID int) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		log.Printf("Error starting transaction: %v", err)
@@ -151,15 +193,22 @@ func (s *OrderStore) UpdateOrderAddress(orderID int, address types.UpdateAddress
 		}
 	}()
 
-	updateQuery := `UPDATE orders SET street = $1, apt_unit = $2, city = $3, state = $4, zipcode = $5 WHERE order_id = $6`
-	_, err = tx.Exec(updateQuery, address.Street, address.AptUnit, address.City, address.State, address.Zipcode, orderID)
+	updateQuery := `
+		UPDATE orders
+		SET street = $1, apt_unit = $2, city = $3, state = $4, zipcode = $5, version = version + 1
+		WHERE order_id = $6 AND version = $7
+	`
+	res, err := tx.Exec(updateQuery, address.Street, address.AptUnit, address.City, address.State, address.Zipcode, orderID, version)
 	if err != nil {
 		log.Printf("Error updating order address: %v", err)
 		return err
 	}
+	if err = requireUpdated(res); err != nil {
+		return err
+	}
 
-	logQuery := `INSERT INTO order_updates (order_id, admin_id, message) VALUES ($1, $2, $3)`
-	_, err = tx.Exec(logQuery, orderID, adminID, "Updated order address")
+	logQuery := `INSERT INTO order_updates (order_id, admin_id, message, is_internal) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(logQuery, orderID, toNullableAdminID(adminID), "Updated order address", true)
 	if err != nil {
 		log.Printf("Error logging order update: %v", err)
 		return err
@@ -184,14 +233,16 @@ func (s *OrderStore) OrderExistsByID(orderID int) (bool, error) {
 	return exists, nil
 }
 
-func (s *OrderStore) UpdateOrderStatus(orderID int, adminID int, newStatus string, message string) error {
+// UpdateOrderStatusIf changes the status of an order and logs the update only when the order currently has the
+// expected status, see `transitionStatusTx`. Returns false if the order was not in the expected status, which makes
+// repeated calls safe.
+func (s *OrderStore) UpdateOrderStatusIf(orderID int, adminID int, currentStatus string, newStatus string, message string) (updated bool, err error) {
 	tx, err := s.db.Begin()
 	if err != nil {
 		log.Printf("Error starting transaction: %v", err)
-		return err
+		return false, err
 	}
 
-	// Defer rollback in case of error
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
@@ -201,35 +252,110 @@ func (s *OrderStore) UpdateOrderStatus(orderID int, adminID int, newStatus strin
 		}
 	}()
 
-	_, err = tx.Exec("UPDATE orders SET status = $1 WHERE order_id = $2", newStatus, orderID)
+	updated, err = s.transitionStatusTx(tx, orderID, adminID, newStatus, message, currentStatus)
 	if err != nil {
-		log.Printf("Error updating order status: %v", err)
-		return err
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return false, err
+	}
+
+	return updated, nil
+}
+
+// transitionStatusTx moves an order to a new status within the transaction and logs the update. Every status change
+// goes through it, so customers are always notified: the status email is queued in the email outbox as part of the
+// same transaction and delivered later, so email failures never block the update. Customers are not emailed about
+// orders they never paid for ("pending").
+// When expected statuses are given, the order is only moved from one of them; returns false otherwise.
+func (s *OrderStore) transitionStatusTx(tx *sql.Tx, orderID int, adminID int, newStatus string, message string, expectedStatuses ...string) (bool, error) {
+	var currentStatus string
+	err := tx.QueryRow("SELECT status FROM orders WHERE order_id = $1 FOR UPDATE", orderID).Scan(&currentStatus)
+	if err != nil {
+		log.Printf("Error locking order ID %v: %v", orderID, err)
+		return false, err
+	}
+	if len(expectedStatuses) > 0 && !slices.Contains(expectedStatuses, currentStatus) {
+		return false, nil
 	}
 
-	logQuery := `INSERT INTO order_updates (order_id, admin_id, message) VALUES ($1, $2, $3)`
-	_, err = tx.Exec(logQuery, orderID, adminID, message)
+	order, err := scanRowIntoOrder(tx.QueryRow("UPDATE orders SET status = $1, version = version + 1 WHERE order_id = $2 RETURNING "+orderColumns, newStatus, orderID))
+	if err != nil {
+		log.Printf("Error updating order ID '%v' status from '%v' to '%v': %v", orderID, currentStatus, newStatus, err)
+		return false, err
+	}
+
+	logQuery := `INSERT INTO order_updates (order_id, admin_id, message, is_internal) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(logQuery, orderID, toNullableAdminID(adminID), message, false)
 	if err != nil {
 		log.Printf("Error logging order update: %v", err)
-		return err
+		return false, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		log.Printf("Error committing transaction: %v", err)
-		return err
+	if currentStatus == "pending" || !templates.HasOrderStatusUpdateTemplate(newStatus) {
+		return true, nil
 	}
 
-	return nil
+	order.Items, err = s.GetOrderItems(orderID)
+	if err != nil {
+		return false, err
+	}
+	if err = email.EnqueueTx(tx, *buildStatusNotification(*order, newStatus, message)); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-// UpdateOrderStatusNoLogs updates an order status without generating an OrderUpdate log. Use with caution.
-func (s *OrderStore) UpdateOrderStatusNoLogs(orderID int, newStatus string) error {
-	_, err := s.db.Exec("UPDATE orders SET status = $1 WHERE order_id = $2", newStatus, orderID)
+// MarkOrderPaid moves a "pending" order to "received", stores the payment reference from the payment provider and
+// queues the order confirmation email in the email outbox, all in a single transaction.
+// Returns false if the order was no longer "pending" (e.g. it was already marked as paid).
+func (s *OrderStore) MarkOrderPaid(orderID int, paymentReference string) (updated bool, err error) {
+	tx, err := s.db.Begin()
 	if err != nil {
-		log.Printf("Error updating order ID '%v' status: %v", orderID, err)
-		return err
+		log.Printf("Error starting transaction: %v", err)
+		return false, err
 	}
-	return nil
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	order, err := scanRowIntoOrder(tx.QueryRow(`
+    UPDATE orders
+    SET status = 'received', payment_reference = NULLIF($1, ''), version = version + 1
+    WHERE order_id = $2 AND status = 'pending'
+    RETURNING `+orderColumns, paymentReference, orderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = tx.Rollback()
+			return false, err
+		}
+		log.Printf("Error marking order ID '%v' as paid: %v", orderID, err)
+		return false, err
+	}
+
+	order.Items, err = s.GetOrderItems(orderID)
+	if err != nil {
+		return false, err
+	}
+	if err = email.EnqueueTx(tx, buildOrderConfirmation(*order)); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return false, err
+	}
+
+	return true, nil
 }
 
 func (s *OrderStore) GetOrderStatusByID(orderID int) (status string, err error) {
@@ -243,9 +369,10 @@ func (s *OrderStore) GetOrderStatusByID(orderID int) (status string, err error)
 
 func (s *OrderStore) GetOrderUpdates(orderID int) ([]types.OrderUpdate, error) {
 	rows, err := s.db.Query(`
-    SELECT ou.order_update_id, ou.message, ou.created_at, a.firstname, a.lastname, a.username
+    SELECT ou.order_update_id, ou.message, ou.is_internal, ou.created_at,
+      COALESCE(a.firstname, 'Sockify'), COALESCE(a.lastname, 'System'), COALESCE(a.username, 'system')
     FROM order_updates ou
-    JOIN admins a ON a.admin_id = ou.admin_id
+    LEFT JOIN admins a ON a.admin_id = ou.admin_id
     WHERE ou.order_id = $1
     ORDER BY created_at DESC
   `, orderID)
@@ -259,7 +386,7 @@ func (s *OrderStore) GetOrderUpdates(orderID int) ([]types.OrderUpdate, error) {
 	for rows.Next() {
 		var u types.OrderUpdate
 
-		if err := rows.Scan(&u.ID, &u.Message, &u.CreatedAt, &u.CreatedBy.FirstName, &u.CreatedBy.LastName, &u.CreatedBy.Username); err != nil {
+		if err := rows.Scan(&u.ID, &u.Message, &u.IsInternal, &u.CreatedAt, &u.CreatedBy.FirstName, &u.CreatedBy.LastName, &u.CreatedBy.Username); err != nil {
 			return nil, err
 		}
 		updates = append(updates, u)
@@ -268,11 +395,12 @@ func (s *OrderStore) GetOrderUpdates(orderID int) ([]types.OrderUpdate, error) {
 	return updates, nil
 }
 
-func (s *OrderStore) CreateOrderUpdate(orderID int, adminID int, message string) error {
+// CreateOrderUpdate logs an update for an order. Internal updates are never shown to customers.
+func (s *OrderStore) CreateOrderUpdate(orderID int, adminID int, message string, isInternal bool) error {
 	res, err := s.db.Exec(`
-    INSERT INTO order_updates (order_id, admin_id, message)
-    VALUES ($1, $2, $3)
-  `, orderID, adminID, message)
+    INSERT INTO order_updates (order_id, admin_id, message, is_internal)
+    VALUES ($1, $2, $3, $4)
+  `, orderID, toNullableAdminID(adminID), message, isInternal)
 
 	if err != nil {
 		return err
@@ -289,7 +417,9 @@ func (s *OrderStore) CreateOrderUpdate(orderID int, adminID int, message string)
 	return nil
 }
 
-func (s *OrderStore) UpdateOrderContact(orderID int, contact types.UpdateContactRequest, adminID int) error {
+// UpdateOrderContact updates the contact information of an order and logs the update.
+// Returns `types.ErrVersionConflict` if the order is no longer at the given version.
+func (s *OrderStore) UpdateOrderContact(orderID int, version int, contact types.UpdateContactRequest, adminID int) error {
 	tx, err := s.db.Begin()
 	if err != nil {
 		log.Printf("Error starting transaction: %v", err)
@@ -307,17 +437,20 @@ func (s *OrderStore) UpdateOrderContact(orderID int, contact types.UpdateContact
 
 	query := `
 		UPDATE orders 
-		SET firstname = $1, lastname = $2, email = $3, phone = $4
-		WHERE order_id = $5
+		SET firstname = $1, lastname = $2, email = $3, phone = $4, version = version + 1
+		WHERE order_id = $5 AND version = $6
 	`
-	_, err = tx.Exec(query, contact.FirstName, contact.LastName, contact.Email, contact.Phone, orderID)
+	res, err := tx.Exec(query, contact.FirstName, contact.LastName, contact.Email, contact.Phone, orderID, version)
 	if err != nil {
 		log.Printf("Error updating order contact: %v", err)
 		return err
 	}
+	if err = requireUpdated(res); err != nil {
+		return err
+	}
 
-	logQuery := `INSERT INTO order_updates (order_id, admin_id, message) VALUES ($1, $2, $3)`
-	_, err = tx.Exec(logQuery, orderID, adminID, "Updated order contact information")
+	logQuery := `INSERT INTO order_updates (order_id, admin_id, message, is_internal) VALUES ($1, $2, $3, $4)`
+	_, err = tx.Exec(logQuery, orderID, toNullableAdminID(adminID), "Updated order contact information", true)
 	if err != nil {
 		log.Printf("Error logging order update: %v", err)
 		return err
@@ -332,18 +465,11 @@ func (s *OrderStore) UpdateOrderContact(orderID int, contact types.UpdateContact
 }
 
 func (s *OrderStore) GetOrderByInvoice(invoiceNumber string) (*types.Order, error) {
-	var order types.Order
 	query := `
-		SELECT * FROM orders 
+		SELECT ` + orderColumns + ` FROM orders 
 		WHERE invoice_number = $1
 	`
-	err := s.db.QueryRow(query, invoiceNumber).Scan(
-		&order.ID, &order.InvoiceNumber, &order.Total, &order.Status,
-		&order.Contact.FirstName, &order.Contact.LastName, &order.Contact.Email, &order.Contact.Phone,
-		&order.Address.Street, &order.Address.AptUnit, &order.Address.City, &order.Address.State, &order.Address.Zipcode,
-		&order.CreatedAt,
-	)
-
+	order, err := scanRowIntoOrder(s.db.QueryRow(query, invoiceNumber))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -358,46 +484,616 @@ func (s *OrderStore) GetOrderByInvoice(invoiceNumber string) (*types.Order, erro
 	}
 	order.Items = items
 
-	return &order, nil
+	return order, nil
 }
 
-func (s *OrderStore) CreateOrder(items []types.CheckoutItem, total float64, addr types.Address, contact types.Contact) (orderID int, err error) {
-	invoiceNumber, err := utils.GenerateUUID()
+// GetExpiredPendingOrderIDs returns the IDs of all "pending" orders created more than `maxAge` ago.
+func (s *OrderStore) GetExpiredPendingOrderIDs(maxAge time.Duration) ([]int, error) {
+	rows, err := s.db.Query(`
+    SELECT order_id
+    FROM orders
+    WHERE status = 'pending' AND created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'
+    ORDER BY created_at ASC
+  `, int64(maxAge.Seconds()))
+	if err != nil {
+		log.Printf("Error fetching expired pending orders: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// CancelPendingOrder cancels a "pending" order and returns all of its items back to stock in a single transaction.
+// Returns false if the order was no longer "pending", so it is safe to call concurrently with payment confirmations.
+func (s *OrderStore) CancelPendingOrder(orderID int, adminID int, message string) (canceled bool, err error) {
+	return s.CancelOrder(orderID, adminID, "pending", message)
+}
+
+// CancelOrder cancels an order that is still in `currentStatus` and returns its items back to stock in a single
+// transaction, since their stock was reserved at checkout. Items already returned to stock by a refund are skipped.
+// Returns false if the order was no longer in `currentStatus`.
+func (s *OrderStore) CancelOrder(orderID int, adminID int, currentStatus string, message string) (canceled bool, err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return false, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	canceled, err = s.transitionStatusTx(tx, orderID, adminID, "canceled", message, currentStatus)
+	if err != nil {
+		return false, err
+	}
+	if !canceled {
+		err = tx.Rollback()
+		return false, err
+	}
+
+	rows, err := tx.Query(`
+    UPDATE sock_variants sv
+    SET quantity = sv.quantity + oi.quantity, version = sv.version + 1
+    FROM (
+      SELECT oi.sock_variant_id, SUM(oi.quantity - `+restockedQuantityQuery+`) AS quantity
+      FROM order_items oi
+      WHERE oi.order_id = $1
+      GROUP BY oi.sock_variant_id
+    ) oi
+    WHERE sv.sock_variant_id = oi.sock_variant_id AND oi.quantity > 0
+    RETURNING sv.sock_variant_id, oi.quantity, sv.quantity
+  `, orderID)
+	if err != nil {
+		log.Printf("Error restocking items for order ID %v: %v", orderID, err)
+		return false, err
+	}
+
+	var movements []types.InventoryMovement
+	for rows.Next() {
+		m := types.InventoryMovement{Reason: types.MovementCanceled, AdminID: toAdminRef(adminID), OrderID: &orderID}
+		if err = rows.Scan(&m.SockVariantID, &m.Delta, &m.QuantityAfter); err != nil {
+			rows.Close()
+			log.Printf("Error scanning restocked item for order ID %v: %v", orderID, err)
+			return false, err
+		}
+		movements = append(movements, m)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		log.Printf("Error restocking items for order ID %v: %v", orderID, err)
+		return false, err
+	}
+
+	for _, m := range movements {
+		if err = inventory.RecordMovementTx(tx, m); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetRefunds returns all refunds issued for an order, newest first.
+func (s *OrderStore) GetRefunds(orderID int) ([]types.OrderRefund, error) {
+	rows, err := s.db.Query(`
+    SELECT r.order_refund_id, r.amount, r.reason, r.restocked, r.provider_refund_id, r.status, r.created_at,
+      COALESCE(a.firstname, 'Sockify'), COALESCE(a.lastname, 'System'), COALESCE(a.username, 'system')
+    FROM order_refunds r
+    LEFT JOIN admins a ON a.admin_id = r.admin_id
+    WHERE r.order_id = $1
+    ORDER BY r.created_at DESC
+  `, orderID)
+	if err != nil {
+		log.Printf("Unable to get the refunds for orderID %v: %v", orderID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	refunds := make([]types.OrderRefund, 0)
+	for rows.Next() {
+		var r types.OrderRefund
+		if err := rows.Scan(&r.ID, &r.Amount, &r.Reason, &r.Restocked, &r.ProviderRefundID, &r.Status, &r.CreatedAt,
+			&r.CreatedBy.FirstName, &r.CreatedBy.LastName, &r.CreatedBy.Username,
+		); err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, r)
+	}
+
+	for i := range refunds {
+		refunds[i].Items, err = s.getRefundItems(refunds[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return refunds, nil
+}
+
+func (s *OrderStore) getRefundItems(refundID int) ([]types.OrderRefundItem, error) {
+	rows, err := s.db.Query(`
+    SELECT ori.order_item_id, oi.sock_variant_id, ori.quantity, ori.amount
+    FROM order_refund_items ori
+    JOIN order_items oi ON oi.order_item_id = ori.order_item_id
+    WHERE ori.order_refund_id = $1
+  `, refundID)
+	if err != nil {
+		log.Printf("Unable to get the items for refund ID %v: %v", refundID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]types.OrderRefundItem, 0)
+	for rows.Next() {
+		var item types.OrderRefundItem
+		if err := rows.Scan(&item.OrderItemID, &item.SockVariantID, &item.Quantity, &item.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// refundedQuantityQuery sums the refunded quantity of an order item `oi`. Pending refunds are included, since the
+// payment provider may already be refunding them.
+const refundedQuantityQuery = `
+  COALESCE((
+    SELECT SUM(ori.quantity)
+    FROM order_refund_items ori
+    JOIN order_refunds r ON r.order_refund_id = ori.order_refund_id
+    WHERE ori.order_item_id = oi.order_item_id AND r.status <> 'failed'
+  ), 0)`
+
+// restockedQuantityQuery sums the quantity of an order item `oi` returned to stock by refunds. Pending refunds are
+// included, since they return their items to stock once they complete.
+const restockedQuantityQuery = `
+  COALESCE((
+    SELECT SUM(ori.quantity)
+    FROM order_refund_items ori
+    JOIN order_refunds r ON r.order_refund_id = ori.order_refund_id
+    WHERE ori.order_item_id = oi.order_item_id AND r.restocked = true AND r.status <> 'failed'
+  ), 0)`
+
+// CreateRefund records a pending refund for an order in a single transaction, before it is issued through the payment
+// provider. The order is locked while its refundable quantities are checked, so concurrent refunds can never refund
+// the same items twice. Returns `types.ErrAlreadyRefunded` if any of the items were already refunded.
+func (s *OrderStore) CreateRefund(orderID int, adminID int, refund types.OrderRefund) (refundID int, err error) {
+	tx, err := s.db.Begin()
 	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
 		return 0, err
 	}
 
-	err = s.db.QueryRow(`
-    INSERT INTO orders (invoice_number, total_price, firstname, lastname, email, phone, street, apt_unit, city, state, zipcode)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-    RETURNING order_id
-  `, invoiceNumber, total, contact.FirstName, contact.LastName, contact.Email, contact.Phone, addr.Street, addr.AptUnit, addr.City, addr.State, addr.Zipcode,
-	).Scan(&orderID)
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
 
+	// Locking the order serializes concurrent refunds for the same order.
+	_, err = tx.Exec("SELECT 1 FROM orders WHERE order_id = $1 FOR UPDATE", orderID)
 	if err != nil {
+		log.Printf("Error locking order ID %v: %v", orderID, err)
 		return 0, err
 	}
 
-	return orderID, nil
+	err = tx.QueryRow(`
+    INSERT INTO order_refunds (order_id, admin_id, amount, reason, restocked, provider_refund_id, status)
+    VALUES ($1, $2, $3, $4, $5, '', $6)
+    RETURNING order_refund_id
+  `, orderID, toNullableAdminID(adminID), refund.Amount, refund.Reason, refund.Restocked, types.RefundStatusPending).Scan(&refundID)
+	if err != nil {
+		log.Printf("Error creating refund for order ID %v: %v", orderID, err)
+		return 0, err
+	}
+
+	for _, item := range refund.Items {
+		var refundable int
+		err = tx.QueryRow(`
+      SELECT oi.quantity - `+refundedQuantityQuery+`
+      FROM order_items oi
+      WHERE oi.order_item_id = $1 AND oi.order_id = $2
+    `, item.OrderItemID, orderID).Scan(&refundable)
+		if err != nil {
+			log.Printf("Error checking refundable quantity for order item ID %v: %v", item.OrderItemID, err)
+			return 0, err
+		}
+		if item.Quantity > refundable {
+			err = fmt.Errorf("%w: order item with ID %v only has %v item(s) left to refund", types.ErrAlreadyRefunded, item.OrderItemID, refundable)
+			return 0, err
+		}
+
+		_, err = tx.Exec(`
+      INSERT INTO order_refund_items (order_refund_id, order_item_id, quantity, amount)
+      VALUES ($1, $2, $3, $4)
+    `, refundID, item.OrderItemID, item.Quantity, item.Amount)
+		if err != nil {
+			log.Printf("Error creating refund item for order item ID %v: %v", item.OrderItemID, err)
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return 0, err
+	}
+
+	return refundID, nil
 }
 
-func (s *OrderStore) CreateOrderItem(orderID int, sockVariantID int, price float64, quantity int) error {
-	res, err := s.db.Exec(`
-    INSERT INTO order_items (order_id, sock_variant_id, price, quantity)
-    VALUES ($1, $2, $3, $4)
-  `, orderID, sockVariantID, price, quantity)
+// CompleteRefund marks a pending refund as issued through the payment provider in a single transaction.
+// Refunded items are optionally returned to stock, and fully refunded orders are moved to "canceled" or "returned".
+// Every step is logged within the order updates.
+func (s *OrderStore) CompleteRefund(orderID int, adminID int, refund types.OrderRefund) (err error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return err
+	}
 
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var status string
+	err = tx.QueryRow("SELECT status FROM orders WHERE order_id = $1 FOR UPDATE", orderID).Scan(&status)
 	if err != nil {
+		log.Printf("Error locking order ID %v: %v", orderID, err)
 		return err
 	}
 
-	val, err := res.RowsAffected()
+	res, err := tx.Exec(`
+    UPDATE order_refunds SET status = $1, provider_refund_id = $2
+    WHERE order_refund_id = $3 AND order_id = $4 AND status = $5
+  `, types.RefundStatusCompleted, refund.ProviderRefundID, refund.ID, orderID, types.RefundStatusPending)
 	if err != nil {
+		log.Printf("Error completing refund ID %v: %v", refund.ID, err)
 		return err
 	}
-	if val == 0 {
-		return fmt.Errorf("no rows were affected")
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		err = fmt.Errorf("refund with ID %v is not pending", refund.ID)
+		return err
+	}
+
+	logQuery := `INSERT INTO order_updates (order_id, admin_id, message, is_internal) VALUES ($1, $2, $3, $4)`
+	restockedCount := 0
+	if refund.Restocked {
+		for _, item := range refund.Items {
+			var quantityAfter int
+			err = tx.QueryRow("UPDATE sock_variants SET quantity = quantity + $1, version = version + 1 WHERE sock_variant_id = $2 RETURNING quantity", item.Quantity, item.SockVariantID).Scan(&quantityAfter)
+			if err != nil {
+				log.Printf("Error restocking sock variant ID %v: %v", item.SockVariantID, err)
+				return err
+			}
+
+			err = inventory.RecordMovementTx(tx, types.InventoryMovement{
+				SockVariantID: item.SockVariantID,
+				Delta:         item.Quantity,
+				QuantityAfter: quantityAfter,
+				Reason:        types.MovementReturn,
+				AdminID:       toAdminRef(adminID),
+				OrderID:       &orderID,
+			})
+			if err != nil {
+				return err
+			}
+			restockedCount += item.Quantity
+		}
+	}
+
+	_, err = tx.Exec(logQuery, orderID, toNullableAdminID(adminID), fmt.Sprintf("Refunded $%.2f (%v item(s)): %v", refund.Amount, countRefundItems(refund.Items), refund.Reason), false)
+	if err != nil {
+		log.Printf("Error logging order update: %v", err)
+		return err
 	}
 
+	if restockedCount > 0 {
+		_, err = tx.Exec(logQuery, orderID, toNullableAdminID(adminID), fmt.Sprintf("Returned %v item(s) to stock", restockedCount), true)
+		if err != nil {
+			log.Printf("Error logging order update: %v", err)
+			return err
+		}
+	}
+
+	// Only completed refunds count, so the order is not moved while another refund is still pending
+	var remaining int
+	err = tx.QueryRow(`
+    SELECT COALESCE(SUM(oi.quantity), 0) - COALESCE((
+      SELECT SUM(ori.quantity)
+      FROM order_refund_items ori
+      JOIN order_refunds r ON r.order_refund_id = ori.order_refund_id
+      WHERE r.order_id = $1 AND r.status = $2
+    ), 0)
+    FROM order_items oi
+    WHERE oi.order_id = $1
+  `, orderID, types.RefundStatusCompleted).Scan(&remaining)
+	if err != nil {
+		log.Printf("Error checking remaining items for order ID %v: %v", orderID, err)
+		return err
+	}
+
+	if newStatus := getFullyRefundedStatus(status); remaining == 0 && newStatus != status {
+		_, err = s.transitionStatusTx(tx, orderID, adminID, newStatus, fmt.Sprintf("Order fully refunded, status changed to '%v'", newStatus))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// FailRefund marks a pending refund the payment provider declined as failed, so its items can be refunded again.
+func (s *OrderStore) FailRefund(refundID int) error {
+	_, err := s.db.Exec("UPDATE order_refunds SET status = $1 WHERE order_refund_id = $2 AND status = $3",
+		types.RefundStatusFailed, refundID, types.RefundStatusPending)
+	if err != nil {
+		log.Printf("Error failing refund ID %v: %v", refundID, err)
+		return err
+	}
 	return nil
 }
+
+// GetOrderByInvoiceAndEmail retrieves an order by its invoice number, only if it matches the contact email (case insensitive).
+func (s *OrderStore) GetOrderByInvoiceAndEmail(invoiceNumber string, email string) (*types.Order, error) {
+	order, err := scanRowIntoOrder(s.db.QueryRow(
+		"SELECT "+orderColumns+" FROM orders WHERE invoice_number = $1 AND LOWER(email) = LOWER($2)",
+		invoiceNumber, email,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching order by invoice number %s and email: %v", invoiceNumber, err)
+		return nil, err
+	}
+
+	items, err := s.GetOrderItems(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	return order, nil
+}
+
+func (s *OrderStore) GetOrderByPaymentReference(paymentReference string) (*types.Order, error) {
+	order, err := scanRowIntoOrder(s.db.QueryRow("SELECT "+orderColumns+" FROM orders WHERE payment_reference = $1", paymentReference))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		log.Printf("Error fetching order by payment reference %s: %v", paymentReference, err)
+		return nil, err
+	}
+
+	items, err := s.GetOrderItems(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	return order, nil
+}
+
+// CreateOrder creates a "pending" order along with its items in a single transaction.
+// Stock is reserved through conditional updates, so concurrent checkouts can never oversell a sock variant.
+// The order is linked to the customer when a customer ID is given. Returns `types.ErrInsufficientStock` if any of the items can not be reserved.
+func (s *OrderStore) CreateOrder(items []types.CheckoutItem, addr types.Address, contact types.Contact, customerID *int) (orderID int, err error) {
+	invoiceNumber, err := utils.GenerateUUID()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Printf("Error starting transaction: %v", err)
+		return 0, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Rows are always locked in the same (ascending ID) order to avoid deadlocks between concurrent checkouts.
+	items = mergeCheckoutItems(items)
+
+	// Descriptions are stored with the items, so the order keeps showing what was bought
+	variantIDs := make([]int, len(items))
+	for i, item := range items {
+		variantIDs[i] = item.SockVariantID
+	}
+	descriptions, err := s.sockStore.GetVariantDescriptions(variantIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	prices := make(map[int]float64, len(items))
+	remaining := make(map[int]int, len(items))
+	var total float64
+	for _, item := range items {
+		var price float64
+		var quantityAfter int
+		err = tx.QueryRow(`
+      UPDATE sock_variants
+      SET quantity = quantity - $1, version = version + 1
+      WHERE sock_variant_id = $2 AND quantity >= $1 AND is_deleted = false
+      RETURNING price, quantity
+    `, item.Quantity, item.SockVariantID).Scan(&price, &quantityAfter)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = fmt.Errorf("%w for sock variant with ID %v", types.ErrInsufficientStock, item.SockVariantID)
+				return 0, err
+			}
+			log.Printf("Error reserving stock for sock variant ID %v: %v", item.SockVariantID, err)
+			return 0, err
+		}
+
+		prices[item.SockVariantID] = price
+		remaining[item.SockVariantID] = quantityAfter
+		total += price * float64(item.Quantity)
+	}
+
+	err = tx.QueryRow(`
+    INSERT INTO orders (invoice_number, total_price, firstname, lastname, email, phone, street, apt_unit, city, state, zipcode, customer_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    RETURNING order_id
+  `, invoiceNumber, total, contact.FirstName, contact.LastName, contact.Email, contact.Phone, addr.Street, addr.AptUnit, addr.City, addr.State, addr.Zipcode, customerID,
+	).Scan(&orderID)
+	if err != nil {
+		log.Printf("Error creating order: %v", err)
+		return 0, err
+	}
+
+	for _, item := range items {
+		_, err = tx.Exec(`
+      INSERT INTO order_items (order_id, sock_variant_id, price, quantity, variant_description)
+      VALUES ($1, $2, $3, $4, $5)
+    `, orderID, item.SockVariantID, prices[item.SockVariantID], item.Quantity, descriptions[item.SockVariantID])
+		if err != nil {
+			log.Printf("Error creating order item for order ID %v: %v", orderID, err)
+			return 0, err
+		}
+
+		err = inventory.RecordMovementTx(tx, types.InventoryMovement{
+			SockVariantID: item.SockVariantID,
+			Delta:         -item.Quantity,
+			QuantityAfter: remaining[item.SockVariantID],
+			Reason:        types.MovementSale,
+			OrderID:       &orderID,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Printf("Error committing transaction: %v", err)
+		return 0, err
+	}
+
+	return orderID, nil
+}
+
+// mergeCheckoutItems combines items for the same sock variant and sorts them by sock variant ID.
+func mergeCheckoutItems(items []types.CheckoutItem) []types.CheckoutItem {
+	quantities := make(map[int]int)
+	for _, item := range items {
+		quantities[item.SockVariantID] += item.Quantity
+	}
+
+	merged := make([]types.CheckoutItem, 0, len(quantities))
+	for id, quantity := range quantities {
+		merged = append(merged, types.CheckoutItem{SockVariantID: id, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].SockVariantID < merged[j].SockVariantID
+	})
+
+	return merged
+}
+
+// getFullyRefundedStatus returns the status an order moves to once all of its items are refunded.
+func getFullyRefundedStatus(status string) string {
+	switch status {
+	case "received":
+		return "canceled"
+	case "delivered":
+		return "returned"
+	default:
+		return status
+	}
+}
+
+func countRefundItems(items []types.OrderRefundItem) (count int) {
+	for _, item := range items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// toNullableAdminID maps `types.SystemAdminID` to NULL for order updates created by the system.
+func toNullableAdminID(adminID int) any {
+	if adminID == types.SystemAdminID {
+		return nil
+	}
+	return adminID
+}
+
+// toAdminRef maps `types.SystemAdminID` to nil for stock movements made by the system.
+func toAdminRef(adminID int) *int {
+	if adminID == types.SystemAdminID {
+		return nil
+	}
+	return &adminID
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// requireUpdated returns `types.ErrVersionConflict` when a conditional update on the order version matched no rows.
+func requireUpdated(res sql.Result) error {
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return types.ErrVersionConflict
+	}
+	return nil
+}
+
+func scanRowIntoOrder(row rowScanner) (*types.Order, error) {
+	order := &types.Order{}
+	err := row.Scan(
+		&order.ID, &order.InvoiceNumber, &order.Total, &order.Status,
+		&order.Contact.FirstName, &order.Contact.LastName, &order.Contact.Email, &order.Contact.Phone,
+		&order.Address.Street, &order.Address.AptUnit, &order.Address.City, &order.Address.State, &order.Address.Zipcode,
+		&order.PaymentReference, &order.CustomerID, &order.Version, &order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -0,0 +1,65 @@
+package orders
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sockify/sockify/types"
+)
+
+// Extra time given to in-flight payment confirmations before an expired checkout is canceled.
+const reaperGracePeriod = 5 * time.Minute
+
+// PendingOrderReaper periodically cancels "pending" orders whose checkout has expired and returns their items to stock.
+type PendingOrderReaper struct {
+	store    types.OrderStore
+	interval time.Duration
+	maxAge   time.Duration
+}
+
+func NewPendingOrderReaper(store types.OrderStore, interval time.Duration, checkoutExpiration time.Duration) *PendingOrderReaper {
+	return &PendingOrderReaper{
+		store:    store,
+		interval: interval,
+		maxAge:   checkoutExpiration + reaperGracePeriod,
+	}
+}
+
+// Run reaps abandoned orders every interval until the context is canceled.
+func (r *PendingOrderReaper) Run(ctx context.Context) {
+	log.Printf("Pending order reaper started (interval: %v, max age: %v)", r.interval, r.maxAge)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reap()
+
+		select {
+		case <-ctx.Done():
+			log.Println("Pending order reaper stopped.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *PendingOrderReaper) reap() {
+	orderIDs, err := r.store.GetExpiredPendingOrderIDs(r.maxAge)
+	if err != nil {
+		log.Printf("Pending order reaper was unable to fetch expired orders: %v", err)
+		return
+	}
+
+	for _, orderID := range orderIDs {
+		canceled, err := r.store.CancelPendingOrder(orderID, types.SystemAdminID, "Order canceled automatically since the payment was not completed in time.")
+		if err != nil {
+			log.Printf("Pending order reaper was unable to cancel order ID %v: %v", orderID, err)
+			continue
+		}
+		if canceled {
+			log.Printf("Pending order reaper canceled abandoned order ID %v", orderID)
+		}
+	}
+}
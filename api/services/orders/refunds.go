@@ -0,0 +1,74 @@
+package orders
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sockify/sockify/types"
+)
+
+// buildRefund determines the items and amount to refund for an order.
+// When no items are requested, everything that has not been refunded yet is refunded.
+func buildRefund(order types.Order, req types.CreateRefundRequest) (*types.OrderRefund, error) {
+	if order.PaymentReference == nil || *order.PaymentReference == "" {
+		return nil, fmt.Errorf("order with ID %v has not been paid", order.ID)
+	}
+
+	requested := make(map[int]int)
+	if len(req.Items) == 0 {
+		for _, item := range order.Items {
+			if remaining := item.Quantity - item.RefundedQuantity; remaining > 0 {
+				requested[item.ID] = remaining
+			}
+		}
+	}
+	for _, item := range req.Items {
+		requested[item.OrderItemID] += item.Quantity
+	}
+
+	refund := &types.OrderRefund{
+		Reason:    req.Reason,
+		Restocked: req.Restock,
+		Items:     make([]types.OrderRefundItem, 0, len(requested)),
+	}
+	// Follow the order's item order so refunds are deterministic.
+	for _, oi := range order.Items {
+		quantity, ok := requested[oi.ID]
+		if !ok {
+			continue
+		}
+		delete(requested, oi.ID)
+
+		if remaining := oi.Quantity - oi.RefundedQuantity; quantity > remaining {
+			return nil, fmt.Errorf("order item with ID %v only has %v item(s) left to refund", oi.ID, remaining)
+		}
+
+		amount := roundToCents(oi.Price * float64(quantity))
+		refund.Items = append(refund.Items, types.OrderRefundItem{
+			OrderItemID:   oi.ID,
+			SockVariantID: oi.SockVariantID,
+			Quantity:      quantity,
+			Amount:        amount,
+		})
+		refund.Amount += amount
+	}
+
+	for orderItemID := range requested {
+		return nil, fmt.Errorf("order item with ID %v does not belong to order with ID %v", orderItemID, order.ID)
+	}
+	if len(refund.Items) == 0 {
+		return nil, fmt.Errorf("there is nothing left to refund for order with ID %v", order.ID)
+	}
+
+	refund.Amount = roundToCents(refund.Amount)
+	return refund, nil
+}
+
+// refundIdempotencyKey identifies a refund within the payment provider, so retrying it never refunds twice.
+func refundIdempotencyKey(refundID int) string {
+	return fmt.Sprintf("sockify-refund-%d", refundID)
+}
+
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,141 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sockify/sockify/types"
+)
+
+// FakeGateway is an in-memory `types.PaymentGateway` for tests and local development without a payment provider.
+// Checkout sessions are paid as soon as they are created, so the payment URL sends customers straight to the success URL.
+//
+// Webhook payloads are JSON encoded `types.PaymentEvent`s signed with the hex encoded HMAC-SHA256 of the payload.
+type FakeGateway struct {
+	mu            sync.Mutex
+	webhookSecret string
+	sessions      map[string]*types.CheckoutSessionStatus
+	// Amount paid (and still refundable) by payment reference
+	payments map[string]float64
+	// Refund IDs by idempotency key
+	refunds map[string]string
+	nextID  int
+}
+
+func NewFakeGateway(webhookSecret string) *FakeGateway {
+	return &FakeGateway{
+		webhookSecret: webhookSecret,
+		sessions:      make(map[string]*types.CheckoutSessionStatus),
+		payments:      make(map[string]float64),
+		refunds:       make(map[string]string),
+	}
+}
+
+func (g *FakeGateway) CreateCheckoutSession(req types.CheckoutSessionRequest) (*types.CheckoutSession, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var total float64
+	for _, item := range req.Items {
+		total += item.Price * float64(item.Quantity)
+	}
+
+	sessionID := g.newID("fake_cs")
+	paymentReference := g.newID("fake_pi")
+	g.sessions[sessionID] = &types.CheckoutSessionStatus{
+		SessionID:        sessionID,
+		OrderID:          req.OrderID,
+		Status:           types.CheckoutSessionStatusComplete,
+		Paid:             true,
+		PaymentReference: paymentReference,
+	}
+	g.payments[paymentReference] = total
+
+	return &types.CheckoutSession{
+		ID:         sessionID,
+		PaymentURL: strings.ReplaceAll(req.SuccessURL, "{SESSION_ID}", sessionID),
+	}, nil
+}
+
+func (g *FakeGateway) GetSessionStatus(sessionID string) (*types.CheckoutSessionStatus, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("checkout session %v does not exist", sessionID)
+	}
+
+	status := *s
+	return &status, nil
+}
+
+func (g *FakeGateway) Refund(paymentReference string, amount float64, idempotencyKey string) (refundID string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if refundID, ok := g.refunds[idempotencyKey]; ok {
+		return refundID, nil
+	}
+
+	remaining, ok := g.payments[paymentReference]
+	if !ok {
+		return "", fmt.Errorf("payment %v does not exist", paymentReference)
+	}
+	if amount <= 0 || amount > remaining+0.001 {
+		return "", fmt.Errorf("refund amount $%.2f is invalid for payment %v with $%.2f remaining", amount, paymentReference, remaining)
+	}
+
+	g.payments[paymentReference] = remaining - amount
+	g.refunds[idempotencyKey] = g.newID("fake_re")
+	return g.refunds[idempotencyKey], nil
+}
+
+func (g *FakeGateway) ParseWebhookEvent(payload []byte, signature string) (*types.PaymentEvent, error) {
+	if g.webhookSecret == "" {
+		return nil, types.ErrWebhookNotConfigured
+	}
+
+	expected := SignFakeWebhookPayload(payload, g.webhookSecret)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, fmt.Errorf("webhook signature does not match")
+	}
+
+	var event types.PaymentEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("unable to parse webhook event: %w", err)
+	}
+	return &event, nil
+}
+
+// SetSessionStatus overrides the status of a checkout session (e.g. to simulate an expired or unpaid session).
+func (g *FakeGateway) SetSessionStatus(sessionID string, status string, paid bool) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("checkout session %v does not exist", sessionID)
+	}
+
+	s.Status = status
+	s.Paid = paid
+	return nil
+}
+
+// SignFakeWebhookPayload returns the signature `FakeGateway` expects for a webhook payload.
+func SignFakeWebhookPayload(payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (g *FakeGateway) newID(prefix string) string {
+	g.nextID++
+	return fmt.Sprintf("%s_%d", prefix, g.nextID)
+}
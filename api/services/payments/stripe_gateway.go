@@ -0,0 +1,155 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+	"github.com/stripe/stripe-go/v80"
+	"github.com/stripe/stripe-go/v80/client"
+	"github.com/stripe/stripe-go/v80/webhook"
+)
+
+type StripeGateway struct {
+	client        *client.API
+	webhookSecret string
+}
+
+func NewStripeGateway(apiKey string, webhookSecret string) types.PaymentGateway {
+	return &StripeGateway{client: client.New(apiKey, nil), webhookSecret: webhookSecret}
+}
+
+func (g *StripeGateway) CreateCheckoutSession(req types.CheckoutSessionRequest) (*types.CheckoutSession, error) {
+	var lineItems []*stripe.CheckoutSessionLineItemParams
+	for _, item := range req.Items {
+		productData := &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+			Name: stripe.String(item.Name),
+			// TODO: we can add images here
+		}
+		// Stripe rejects empty descriptions
+		if item.VariantDescription != "" {
+			productData.Description = stripe.String(item.VariantDescription)
+		}
+
+		lineItems = append(lineItems, &stripe.CheckoutSessionLineItemParams{
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency:    stripe.String(string(stripe.CurrencyUSD)),
+				ProductData: productData,
+				UnitAmount:  stripe.Int64(toCents(item.Price)),
+			},
+			Quantity: stripe.Int64(int64(item.Quantity)),
+		})
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		PaymentMethodTypes: stripe.StringSlice([]string{"card"}),
+		LineItems:          lineItems,
+		Mode:               stripe.String("payment"),
+		SuccessURL:         stripe.String(strings.ReplaceAll(req.SuccessURL, "{SESSION_ID}", "{CHECKOUT_SESSION_ID}")),
+		CancelURL:          stripe.String(req.CancelURL),
+		ExpiresAt:          stripe.Int64(req.ExpiresAt.Unix()),
+		Metadata: map[string]string{
+			"orderId": strconv.Itoa(req.OrderID),
+		},
+	}
+
+	s, err := g.client.CheckoutSessions.New(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.CheckoutSession{ID: s.ID, PaymentURL: s.URL}, nil
+}
+
+func (g *StripeGateway) GetSessionStatus(sessionID string) (*types.CheckoutSessionStatus, error) {
+	s, err := g.client.CheckoutSessions.Get(sessionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return toCheckoutSessionStatus(s), nil
+}
+
+func (g *StripeGateway) Refund(paymentReference string, amount float64, idempotencyKey string) (refundID string, err error) {
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(paymentReference),
+		Amount:        stripe.Int64(toCents(amount)),
+	}
+	params.SetIdempotencyKey(idempotencyKey)
+
+	r, err := g.client.Refunds.New(params)
+	if err != nil {
+		return "", err
+	}
+
+	return r.ID, nil
+}
+
+func (g *StripeGateway) ParseWebhookEvent(payload []byte, signature string) (*types.PaymentEvent, error) {
+	if g.webhookSecret == "" {
+		return nil, types.ErrWebhookNotConfigured
+	}
+
+	event, err := webhook.ConstructEventWithOptions(payload, signature, g.webhookSecret,
+		webhook.ConstructEventOptions{IgnoreAPIVersionMismatch: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &types.PaymentEvent{ID: event.ID, Type: string(event.Type)}
+	switch event.Type {
+	case stripe.EventTypeCheckoutSessionCompleted, stripe.EventTypeCheckoutSessionExpired:
+		var s stripe.CheckoutSession
+		if err := json.Unmarshal(event.Data.Raw, &s); err != nil {
+			return nil, fmt.Errorf("unable to parse checkout session: %w", err)
+		}
+
+		e.Type = types.PaymentEventCheckoutExpired
+		if event.Type == stripe.EventTypeCheckoutSessionCompleted {
+			e.Type = types.PaymentEventCheckoutCompleted
+		}
+		e.Session = toCheckoutSessionStatus(&s)
+
+	case stripe.EventTypeChargeRefunded:
+		var c stripe.Charge
+		if err := json.Unmarshal(event.Data.Raw, &c); err != nil {
+			return nil, fmt.Errorf("unable to parse charge: %w", err)
+		}
+
+		e.Type = types.PaymentEventRefunded
+		if c.PaymentIntent != nil {
+			e.PaymentReference = c.PaymentIntent.ID
+		}
+		e.FullyRefunded = c.Refunded
+	}
+
+	return e, nil
+}
+
+func toCheckoutSessionStatus(s *stripe.CheckoutSession) *types.CheckoutSessionStatus {
+	status := &types.CheckoutSessionStatus{
+		SessionID: s.ID,
+		Status:    string(s.Status),
+		// Delayed payment methods complete the session before the funds are captured.
+		Paid: s.PaymentStatus != stripe.CheckoutSessionPaymentStatusUnpaid,
+	}
+
+	// Sessions that were not created by us will not have an order ID.
+	if orderID, err := strconv.Atoi(s.Metadata["orderId"]); err == nil {
+		status.OrderID = orderID
+	}
+	if s.PaymentIntent != nil {
+		status.PaymentReference = s.PaymentIntent.ID
+	}
+
+	return status
+}
+
+// toCents converts a dollar amount to cents since Stripe expects a whole number (e.g. $12.09 -> 1209).
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/utils"
+)
+
+type Handler struct {
+	blobs types.BlobStore
+}
+
+func NewHandler(blobs types.BlobStore) *Handler {
+	return &Handler{blobs: blobs}
+}
+
+func (h *Handler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/images/{key:.+}", h.handleGetImage).Methods(http.MethodGet)
+}
+
+// @Summary Serves an uploaded image
+// @Description Serves an uploaded image (e.g. a sock image or its thumbnail) by key. Keys are never reused, so images can be cached forever.
+// @Tags Images
+// @Produce image/jpeg,image/png
+// @Param key path string true "Image key, e.g. socks/1/image.jpg"
+// @Success 200 {file} binary
+// @Router /images/{key} [get]
+func (h *Handler) handleGetImage(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	if err := validateKey(key); err != nil {
+		utils.WriteError(w, http.StatusNotFound, types.ErrBlobNotFound)
+		return
+	}
+
+	blob, err := h.blobs.Get(key)
+	if err != nil {
+		if errors.Is(err, types.ErrBlobNotFound) {
+			utils.WriteError(w, http.StatusNotFound, err)
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer blob.Body.Close()
+
+	w.Header().Set("Content-Type", blob.ContentType)
+	if blob.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(blob.Size, 10))
+	}
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, blob.Body); err != nil {
+		log.Printf("Error serving image %v: %v", key, err)
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+import "io"
+
+// BlobStore stores files (e.g. product images) by key. Keys are slash separated paths like "socks/1/image.jpg".
+type BlobStore interface {
+	Put(key string, data []byte, contentType string) error
+	// Returns `ErrBlobNotFound` when there is no blob with the key. The caller must close the blob body.
+	Get(key string) (*Blob, error)
+	// Deleting a missing blob is not an error.
+	Delete(key string) error
+}
+
+type Blob struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+var ErrBlobNotFound = NewError("blob_not_found", "file not found")
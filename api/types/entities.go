@@ -2,6 +2,16 @@ package types
 
 import "time"
 
+// AdminRole controls which admin endpoints an admin can use.
+type AdminRole string
+
+const (
+	RoleOwner            AdminRole = "owner"
+	RoleInventoryManager AdminRole = "inventory_manager"
+	RoleFulfillment      AdminRole = "fulfillment"
+	RoleReadOnly         AdminRole = "read_only"
+)
+
 type Admin struct {
 	ID           int       `json:"id"`
 	FirstName    string    `json:"firstname"`
@@ -9,6 +19,31 @@ type Admin struct {
 	Email        string    `json:"email"`
 	Username     string    `json:"username"`
 	PasswordHash string    `json:"-"`
+	Role         AdminRole `json:"role"`
+	MFAEnabled   bool      `json:"mfaEnabled"`
+	MFASecret    *string   `json:"-"`
+	IsActive     bool      `json:"isActive"`
+	// Subscribed to the low-stock digest emails
+	LowStockAlerts bool      `json:"lowStockAlerts"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// AdminRefreshToken is a stored refresh token. Only its hash is kept.
+type AdminRefreshToken struct {
+	ID        int
+	AdminID   int
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+type Customer struct {
+	ID           int       `json:"id"`
+	FirstName    string    `json:"firstname"`
+	LastName     string    `json:"lastname"`
+	Email        string    `json:"email"`
+	Phone        *string   `json:"phone"`
+	PasswordHash string    `json:"-"`
 	CreatedAt    time.Time `json:"createdAt"`
 }
 
@@ -18,34 +53,68 @@ type Sock struct {
 	Description     string        `json:"description"`
 	PreviewImageURL string        `json:"previewImageUrl"`
 	Variants        []SockVariant `json:"variants"`
-	CreatedAt       time.Time     `json:"createdAt"`
+	CategoryID      *int          `json:"categoryId"`
+	Tags            []string      `json:"tags"`
+	// Gallery images in display order, only set for a single sock
+	Images    []SockImage `json:"images,omitempty"`
+	Version   int         `json:"version"`
+	CreatedAt time.Time   `json:"createdAt"`
 }
 
-type SockVariant struct {
-	ID        int       `json:"id"`
-	Size      string    `json:"size"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
+type SockImage struct {
+	ID           int    `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	ContentType  string `json:"contentType"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	// Zero-based position in the gallery, the first image is the sock preview
+	Position  int       `json:"position"`
 	CreatedAt time.Time `json:"createdAt"`
+	// Blob store keys
+	Key          string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
+
+type SockVariant struct {
+	ID     int `json:"id"`
+	SockID int `json:"sockId"`
+	// Attribute code to value, e.g. {"size": "M", "color": "Red"}. Unique per sock.
+	Options map[string]string `json:"options"`
+	// Human readable options in attribute order, e.g. "Size: M, Color: Red"
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	Quantity    int     `json:"quantity"`
+	// The variant is low on stock when its quantity drops below the threshold. 0 disables low-stock alerts.
+	ReorderThreshold int       `json:"reorderThreshold"`
+	Version          int       `json:"version"`
+	CreatedAt        time.Time `json:"createdAt"`
 }
 
 type Order struct {
-	ID            int         `json:"orderId"`
-	InvoiceNumber string      `json:"invoiceNumber"`
-	Total         float64     `json:"total"`
-	Address       Address     `json:"address"`
-	Contact       Contact     `json:"contact"`
-	Items         []OrderItem `json:"items"`
-	CreatedAt     time.Time   `json:"createdAt"`
-	Status        string      `json:"status"`
+	ID               int         `json:"orderId"`
+	InvoiceNumber    string      `json:"invoiceNumber"`
+	Total            float64     `json:"total"`
+	Address          Address     `json:"address"`
+	Contact          Contact     `json:"contact"`
+	Items            []OrderItem `json:"items"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	Status           string      `json:"status"`
+	PaymentReference *string     `json:"paymentReference"`
+	// NULL for guest checkouts
+	CustomerID *int `json:"customerId"`
+	Version    int  `json:"version"`
 }
 
 type OrderItem struct {
-	SockVariantID int     `json:"sockVariantId"`
-	Name          string  `json:"name"`
-	Size          string  `json:"size"`
-	Price         float64 `json:"price"`
-	Quantity      int     `json:"quantity"`
+	ID            int    `json:"orderItemId"`
+	SockVariantID int    `json:"sockVariantId"`
+	Name          string `json:"name"`
+	// Options of the variant, e.g. "Size: M, Color: Red"
+	VariantDescription string  `json:"variantDescription"`
+	Price              float64 `json:"price"`
+	Quantity           int     `json:"quantity"`
+	RefundedQuantity   int     `json:"refundedQuantity"`
 }
 
 type OrderConfirmation struct {
@@ -57,6 +126,22 @@ type OrderConfirmation struct {
 	CreatedAt     time.Time   `json:"createdAt"`
 }
 
+// OutboundEmail is a rendered email that is ready to be sent.
+type OutboundEmail struct {
+	ToName      string
+	ToEmail     string
+	Subject     string
+	PlainText   string
+	HTMLContent string
+}
+
+// OutboxEmail is an email queued in the outbox, waiting to be delivered.
+type OutboxEmail struct {
+	ID       int
+	Attempts int
+	OutboundEmail
+}
+
 type Address struct {
 	Street  string  `json:"street"`
 	AptUnit *string `json:"aptUnit"`
@@ -72,11 +157,54 @@ type Contact struct {
 	Phone     *string `json:"phone"`
 }
 
+// SystemAdminID is the admin ID used for order updates created by the system (e.g. background workers) rather than an admin.
+const SystemAdminID = 0
+
+// Reasons of an inventory movement.
+const (
+	MovementSale       = "sale"
+	MovementCanceled   = "canceled"
+	MovementReturn     = "return"
+	MovementRestock    = "restock"
+	MovementShrinkage  = "shrinkage"
+	MovementCorrection = "correction"
+)
+
+// InventoryMovement is an entry of the append-only stock ledger. Summing the deltas of a variant gives its quantity.
+type InventoryMovement struct {
+	ID            int `json:"id"`
+	SockVariantID int `json:"sockVariantId"`
+	// Positive when stock is added, negative when it is removed
+	Delta         int    `json:"delta"`
+	QuantityAfter int    `json:"quantityAfter"`
+	Reason        string `json:"reason"`
+	// NULL for movements made by the system, e.g. a checkout
+	AdminID       *int      `json:"adminId"`
+	AdminUsername *string   `json:"adminUsername"`
+	OrderID       *int      `json:"orderId"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// LowStockVariant is an active variant whose quantity is below its reorder threshold.
+type LowStockVariant struct {
+	SockVariantID    int    `json:"sockVariantId"`
+	SockID           int    `json:"sockId"`
+	SockName         string `json:"sockName"`
+	Description      string `json:"description"`
+	Quantity         int    `json:"quantity"`
+	ReorderThreshold int    `json:"reorderThreshold"`
+	// When subscribed admins were alerted, NULL until the next low-stock digest
+	AlertedAt *time.Time `json:"alertedAt"`
+}
+
 type OrderUpdate struct {
 	ID        int                `json:"id"`
 	CreatedBy OrderUpdateCreator `json:"createdBy"`
 	Message   string             `json:"message"`
-	CreatedAt time.Time          `json:"createdAt"`
+	// Internal updates are only visible to admins
+	IsInternal bool      `json:"isInternal"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
 type OrderUpdateCreator struct {
@@ -85,6 +213,94 @@ type OrderUpdateCreator struct {
 	Username  string `json:"username"`
 }
 
+// OrderTracking is the customer-safe view of an order.
+type OrderTracking struct {
+	InvoiceNumber string                `json:"invoiceNumber"`
+	Status        string                `json:"status"`
+	Total         float64               `json:"total"`
+	Address       Address               `json:"address"`
+	Items         []OrderItem           `json:"items"`
+	Updates       []OrderTrackingUpdate `json:"updates"`
+	CreatedAt     time.Time             `json:"createdAt"`
+}
+
+type OrderTrackingUpdate struct {
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type OrderRefund struct {
+	ID        int                `json:"refundId"`
+	Amount    float64            `json:"amount"`
+	Reason    string             `json:"reason"`
+	Restocked bool               `json:"restocked"`
+	Items     []OrderRefundItem  `json:"items"`
+	CreatedBy OrderUpdateCreator `json:"createdBy"`
+	CreatedAt time.Time          `json:"createdAt"`
+	// Refund ID within the payment provider, empty until the refund is completed
+	ProviderRefundID string `json:"providerRefundId"`
+	Status           string `json:"status"`
+}
+
+// Statuses of an order refund. Pending refunds are recorded before calling the payment provider, so their items can
+// not be refunded twice.
+const (
+	RefundStatusPending   = "pending"
+	RefundStatusCompleted = "completed"
+	RefundStatusFailed    = "failed"
+)
+
+type OrderRefundItem struct {
+	OrderItemID   int     `json:"orderItemId"`
+	SockVariantID int     `json:"sockVariantId"`
+	Quantity      int     `json:"quantity"`
+	Amount        float64 `json:"amount"`
+}
+
+type Category struct {
+	ID int `json:"id"`
+	// NULL for top-level categories
+	ParentID  *int      `json:"parentId"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Tag struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Number of socks with the tag, deleted socks included
+	SockCount int `json:"sockCount"`
+}
+
+// Attribute is a variant dimension, such as size or color, with the values admins allow for it.
+type Attribute struct {
+	ID int `json:"id"`
+	// Key used in variant options, e.g. "size"
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// Attributes and values are listed by ascending position
+	Position  int              `json:"position"`
+	Values    []AttributeValue `json:"values"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+type AttributeValue struct {
+	ID       int    `json:"id"`
+	Value    string `json:"value"`
+	Position int    `json:"position"`
+}
+
+type Collection struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	// Socks of the collection in display order, only set for a single collection
+	SockIDs   []int     `json:"sockIds,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type SimilarSock struct {
 	SockId          int     `json:"sockId"`
 	Name            string  `json:"name"`
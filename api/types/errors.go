@@ -0,0 +1,87 @@
+package types
+
+// Error is an error with a stable, machine-readable code that is returned to API clients as is.
+type Error struct {
+	Code    string
+	Message string
+	// Optional underlying error, never shown to clients
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NewError(code string, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// ErrInsufficientStock is returned when a sock variant does not have enough stock to fulfill a request.
+var ErrInsufficientStock = NewError("insufficient_stock", "not enough stock available")
+
+var ErrCustomerAlreadyExists = NewError("customer_already_exists", "a customer with this email already exists")
+
+var ErrTooManyImages = NewError("too_many_images", "the sock already has the maximum number of images")
+
+var ErrInvalidImageOrder = NewError("invalid_image_order", "image IDs must list every image of the sock exactly once")
+
+var ErrSlugAlreadyExists = NewError("slug_already_exists", "slug is already used, pick another name or slug")
+
+var ErrCategoryNotFound = NewError("category_not_found", "category does not exist")
+
+var ErrCategoryCycle = NewError("category_cycle", "a category cannot be moved under itself or one of its subcategories")
+
+var ErrCategoryHasChildren = NewError("category_has_children", "category has subcategories, move or delete them first")
+
+var ErrTagAlreadyExists = NewError("tag_already_exists", "a tag with this name already exists")
+
+var ErrSockNotFound = NewError("sock_not_found", "sock does not exist")
+
+var ErrAttributeAlreadyExists = NewError("attribute_already_exists", "an attribute with this code already exists")
+
+var ErrAttributeValueAlreadyExists = NewError("attribute_value_already_exists", "the attribute already has this value")
+
+var ErrAttributeInUse = NewError("attribute_in_use", "the attribute value is used by sock variants")
+
+var ErrInvalidVariantOption = NewError("invalid_variant_option", "variant option is not an allowed attribute value")
+
+var ErrDuplicateVariant = NewError("duplicate_variant", "two variants have the same options")
+
+// ErrAlreadyRefunded is returned when a refund includes more items than are left to refund, e.g. after a concurrent refund.
+var ErrAlreadyRefunded = NewError("already_refunded", "the items were already refunded")
+
+var ErrAdminEmailAlreadyExists = NewError("email_already_exists", "email already exists")
+
+// ErrLastOwner is returned when demoting or deactivating the last active owner, which would lock everyone out of admin management.
+var ErrLastOwner = NewError("last_owner", "the last owner can not be demoted or deactivated")
+
+// ErrWebhookNotConfigured is returned when a webhook is received but no signing secret is configured to verify it.
+var ErrWebhookNotConfigured = NewError("webhook_not_configured", "payment webhooks are not configured")
+
+// APIError is the body of every error response.
+type APIError struct {
+	// Stable code clients can branch on, e.g. "validation_failed" or "not_found"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Set when the request failed validation, one entry per invalid field
+	Details []FieldError `json:"details,omitempty"`
+	// Also returned in the `X-Request-ID` header, include it when reporting a problem
+	RequestID string `json:"requestId,omitempty"`
+}
+
+type FieldError struct {
+	// JSON name (or query param) of the field, e.g. "variants[0].price"
+	Field string `json:"field"`
+	// Validation rule that failed, e.g. "required" or "max"
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ErrVersionConflict is returned when a resource was modified after the client read it, i.e. its `If-Match` header no longer matches.
+var ErrVersionConflict = NewError("version_conflict", "the resource was modified by someone else, reload it and try again")
+
+var ErrPreconditionRequired = NewError("precondition_required", "the If-Match header is required, send the ETag from the last read of the resource")
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type Message struct {
 	Message string `json:"message"`
 }
@@ -18,6 +20,41 @@ type SocksPaginatedResponse struct {
 	Offset int    `json:"offset"`
 }
 
+// SockFilters narrows down and sorts the socks returned by `GET /socks`. Zero values disable a filter.
+type SockFilters struct {
+	// Full-text search over the sock name and description
+	Query string `query:"q" validate:"max=100"`
+	// Attribute code to value, e.g. {"size": "M"}. Matched variants must have every option.
+	Options  map[string]string `query:"option" validate:"max=5,dive,keys,max=50,endkeys,max=50"`
+	MinPrice *float64          `query:"min_price" validate:"omitempty,gte=0"`
+	MaxPrice *float64          `query:"max_price" validate:"omitempty,gte=0"`
+	InStock  bool              `query:"in_stock"`
+	// Includes the socks of every subcategory
+	CategoryID *int `query:"category_id" validate:"omitempty,gt=0"`
+	// Socks must have every tag
+	Tags         []string `query:"tag" validate:"max=10,dive,max=50"`
+	CollectionID *int     `query:"collection_id" validate:"omitempty,gt=0"`
+	// Defaults to the collection order when filtering by collection
+	Sort string `query:"sort" validate:"omitempty,oneof=relevance newest price_asc price_desc name_asc name_desc"`
+}
+
+// OrderFilters narrows down and sorts the orders returned by `GET /orders`. Zero values disable a filter.
+type OrderFilters struct {
+	Statuses []string `query:"status" validate:"dive,oneof=pending received shipped delivered canceled returned"`
+	// Partial, case insensitive match on "firstname lastname"
+	Name          string `query:"name" validate:"max=100"`
+	Email         string `query:"email" validate:"max=100"`
+	Phone         string `query:"phone" validate:"max=16"`
+	InvoicePrefix string `query:"invoice" validate:"max=36"`
+	CustomerID    *int
+	CreatedFrom   *time.Time `query:"created_from"`
+	// Exclusive upper bound
+	CreatedTo     *time.Time `query:"created_to"`
+	MinTotal      *float64   `query:"min_total" validate:"omitempty,gte=0"`
+	MaxTotal      *float64   `query:"max_total" validate:"omitempty,gte=0"`
+	SortDirection string     `query:"sort" validate:"omitempty,oneof=asc desc"`
+}
+
 type OrdersPaginatedResponse struct {
 	Items  []Order `json:"items"`
 	Total  int     `json:"total"`
@@ -30,7 +67,42 @@ type LoginAdminRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 type LoginAdminResponse struct {
+	// Short-lived access token. Empty when MFA is required
 	Token string `json:"token"`
+	// Long-lived token used to get a new access token at `/admins/refresh`. Empty when MFA is required
+	RefreshToken string `json:"refreshToken,omitempty"`
+	MFARequired  bool   `json:"mfaRequired"`
+	// Short-lived token used to complete the login at `/admins/login/mfa`
+	MFAToken string `json:"mfaToken,omitempty"`
+}
+
+type RefreshAdminTokenRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
+}
+
+type LogoutAdminRequest struct {
+	// Optional, also revokes the refresh token so it can't be used to get new access tokens
+	RefreshToken string `json:"refreshToken"`
+}
+
+type LoginAdminMFARequest struct {
+	MFAToken string `json:"mfaToken" validate:"required"`
+	// TOTP code from the authenticator app, or a recovery code
+	Code string `json:"code" validate:"required"`
+}
+
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFARecoveryCodesResponse struct {
+	// Shown only once, each code can be used a single time instead of a TOTP code
+	RecoveryCodes []string `json:"recoveryCodes"`
 }
 
 type RegisterAdminRequest struct {
@@ -39,6 +111,55 @@ type RegisterAdminRequest struct {
 	Email     string `json:"email" validate:"required,email"`
 	UserName  string `json:"username" validate:"required,min=3,max=16"`
 	Password  string `json:"password" validate:"required,min=8,max=16"`
+	// Defaults to "read_only"
+	Role AdminRole `json:"role" validate:"omitempty,oneof=owner inventory_manager fulfillment read_only"`
+}
+
+type UpdateAdminRoleRequest struct {
+	Role AdminRole `json:"role" validate:"required,oneof=owner inventory_manager fulfillment read_only"`
+}
+
+// UpdateAdminRequest only changes the fields that are set.
+type UpdateAdminRequest struct {
+	FirstName *string `json:"firstname" validate:"omitempty,min=2,max=16"`
+	LastName  *string `json:"lastname" validate:"omitempty,min=1,max=16"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+	// Only owners can (de)activate admins. Deactivated admins can not log in
+	IsActive *bool `json:"isActive"`
+	// Subscribes the admin to the low-stock digest emails
+	LowStockAlerts *bool `json:"lowStockAlerts"`
+}
+
+type ChangeAdminPasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,max=16"`
+}
+
+type ForgotAdminPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetAdminPasswordRequest struct {
+	// Token from the password reset email
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"newPassword" validate:"required,min=8,max=16"`
+}
+
+type RegisterCustomerRequest struct {
+	FirstName string `json:"firstname" validate:"required,min=1,max=32"`
+	LastName  string `json:"lastname" validate:"required,min=1,max=32"`
+	Email     string `json:"email" validate:"required,email,max=100"`
+	Phone     string `json:"phone" validate:"omitempty,max=16"`
+	Password  string `json:"password" validate:"required,min=8,max=16"`
+}
+
+type LoginCustomerRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+type LoginCustomerResponse struct {
+	Token    string   `json:"token"`
+	Customer Customer `json:"customer"`
 }
 
 type CreateSockRequest struct {
@@ -50,20 +171,82 @@ type CreateSockResponse struct {
 }
 
 type SockDTO struct {
-	Name            string `json:"name" validate:"required"`
-	Description     string `json:"description" validate:"required"`
-	PreviewImageURL string `json:"previewImageUrl" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"required"`
+	// Optional, replaced by the first gallery image once images are uploaded
+	PreviewImageURL string `json:"previewImageUrl"`
+	CategoryID      *int   `json:"categoryId" validate:"omitempty,gt=0"`
+	// Free-form, new tags are created as needed
+	Tags []string `json:"tags" validate:"max=20,dive,required,max=50"`
 }
 type SockVariantDTO struct {
-	Size  string  `json:"size" validate:"required,oneof=S M LG XL"`
-	Price float64 `json:"price" validate:"required,gt=0"`
+	// Attribute code to value, e.g. {"size": "M", "color": "Red"}. Values must be allowed by the attribute.
+	Options map[string]string `json:"options" validate:"max=5,dive,keys,required,max=50,endkeys,required,max=50"`
+	Price   float64           `json:"price" validate:"required,gt=0"`
 	// Quantity must be a pointer for the "required" validator to work with 0 as an input.
 	Quantity *int `json:"quantity" validate:"required,gte=0"`
+	// Optional, 0 disables low-stock alerts
+	ReorderThreshold int `json:"reorderThreshold" validate:"gte=0,max=100000"`
 }
 
 type UpdateSockRequest struct {
-	Sock     SockDTO          `json:"sock" validate:"required"`
-	Variants []SockVariantDTO `json:"variants" validate:"required,dive"`
+	Sock SockDTO `json:"sock" validate:"required"`
+	// The full list of variants, one per combination of options. Variants that are left out are removed.
+	Variants []SockVariantDTO `json:"variants" validate:"required,min=1,dive"`
+}
+
+type InventoryMovementsPaginatedResponse struct {
+	Items  []InventoryMovement `json:"items"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type AdjustStockRequest struct {
+	// Positive to add stock, negative to remove it
+	Delta  int    `json:"delta" validate:"required,min=-100000,max=100000"`
+	Reason string `json:"reason" validate:"required,oneof=restock shrinkage correction"`
+	Note   string `json:"note" validate:"max=500"`
+}
+
+type CategoryRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Optional, generated from the name when empty
+	Slug     string `json:"slug" validate:"max=100"`
+	ParentID *int   `json:"parentId" validate:"omitempty,gt=0"`
+}
+
+type AttributeRequest struct {
+	// Lowercase letters, digits and underscores, e.g. "shoe_size"
+	Code     string `json:"code" validate:"required,max=50"`
+	Name     string `json:"name" validate:"required,max=50"`
+	Position int    `json:"position" validate:"gte=0"`
+}
+
+type AttributeValueRequest struct {
+	Value    string `json:"value" validate:"required,max=50"`
+	Position int    `json:"position" validate:"gte=0"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type CollectionRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Optional, generated from the name when empty
+	Slug        string `json:"slug" validate:"max=100"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+type SetCollectionSocksRequest struct {
+	// Every sock of the collection, in display order
+	SockIDs []int `json:"sockIds" validate:"required,unique,dive,gt=0"`
+}
+
+type ReorderSockImagesRequest struct {
+	// Every image ID of the sock, in the new display order
+	ImageIDs []int `json:"imageIds" validate:"required,min=1,unique"`
 }
 
 type UpdateAddressRequest struct {
@@ -88,10 +271,29 @@ type UpdateContactRequest struct {
 
 type CreateOrderUpdateRequest struct {
 	Message string `json:"message" validate:"required"`
+	// Internal updates are not shown to customers. Defaults to true.
+	IsInternal *bool `json:"isInternal"`
+}
+
+type TrackOrderRequest struct {
+	InvoiceNumber string `json:"invoiceNumber" validate:"required,max=36"`
+	Email         string `json:"email" validate:"required,email"`
+}
+
+type CreateRefundRequest struct {
+	// Items to refund. Leave empty to refund everything that has not been refunded yet.
+	Items  []RefundItemRequest `json:"items" validate:"dive"`
+	Reason string              `json:"reason" validate:"required,max=255"`
+	// Whether the refunded items should be returned to stock
+	Restock bool `json:"restock"`
+}
+type RefundItemRequest struct {
+	OrderItemID int `json:"orderItemId" validate:"required"`
+	Quantity    int `json:"quantity" validate:"required,gte=1"`
 }
 
 type CheckoutOrderRequest struct {
-	Items   []CheckoutItem `json:"items" validate:"required"`
+	Items   []CheckoutItem `json:"items" validate:"required,min=1,dive"`
 	Address Address        `json:"address" validated:"required"`
 	Contact Contact        `json:"contact" validate:"required"`
 }
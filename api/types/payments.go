@@ -0,0 +1,61 @@
+package types
+
+import "time"
+
+// Checkout session statuses reported by a `PaymentGateway`.
+const (
+	CheckoutSessionStatusOpen     = "open"
+	CheckoutSessionStatusComplete = "complete"
+	CheckoutSessionStatusExpired  = "expired"
+)
+
+// Payment event types reported by a `PaymentGateway` webhook.
+const (
+	PaymentEventCheckoutCompleted = "checkout.completed"
+	PaymentEventCheckoutExpired   = "checkout.expired"
+	PaymentEventRefunded          = "payment.refunded"
+)
+
+// PaymentGateway processes payments through an external payment provider (e.g. Stripe).
+type PaymentGateway interface {
+	CreateCheckoutSession(req CheckoutSessionRequest) (*CheckoutSession, error)
+	GetSessionStatus(sessionID string) (*CheckoutSessionStatus, error)
+	// Refund issues a refund for a payment. Retries with the same idempotency key never refund twice.
+	Refund(paymentReference string, amount float64, idempotencyKey string) (refundID string, err error)
+	// ParseWebhookEvent verifies the signature of a webhook payload and returns the event it contains.
+	// Returns `ErrWebhookNotConfigured` when no webhook secret is set, so unsigned payloads are never trusted.
+	ParseWebhookEvent(payload []byte, signature string) (*PaymentEvent, error)
+}
+
+type CheckoutSessionRequest struct {
+	OrderID int
+	Items   []OrderItem
+	// URL the customer is sent to after paying. "{SESSION_ID}" is replaced with the checkout session ID.
+	SuccessURL string
+	CancelURL  string
+	ExpiresAt  time.Time
+}
+
+type CheckoutSession struct {
+	ID         string
+	PaymentURL string
+}
+
+type CheckoutSessionStatus struct {
+	SessionID string `json:"sessionId"`
+	OrderID   int    `json:"orderId"`
+	Status    string `json:"status"`
+	Paid      bool   `json:"paid"`
+	// Reference to the payment within the payment provider, used for refunds
+	PaymentReference string `json:"paymentReference"`
+}
+
+type PaymentEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Set for checkout events
+	Session *CheckoutSessionStatus `json:"session,omitempty"`
+	// Set for refund events
+	PaymentReference string `json:"paymentReference,omitempty"`
+	FullyRefunded    bool   `json:"fullyRefunded,omitempty"`
+}
@@ -1,46 +1,154 @@
 package types
 
+import "time"
+
 type AdminStore interface {
 	GetAdmins(limit int, offset int) ([]Admin, int, error)
 	GetAdminByID(id int) (*Admin, error)
 	GetAdminByUsername(username string) (*Admin, error)
 	GetAdminByEmail(email string) (*Admin, error)
-	CreateAdmin(firstname string, lastname string, email string, username string, passwordHash string) error
+	CreateAdmin(firstname string, lastname string, email string, username string, passwordHash string, role AdminRole) error
+	// Returns `ErrLastOwner` when demoting the last active owner.
+	UpdateAdminRole(adminID int, role AdminRole) error
+	UpdateAdmin(adminID int, firstname string, lastname string, email string) error
+	UpdateAdminPassword(adminID int, passwordHash string) error
+	// Returns `ErrLastOwner` when deactivating the last active owner.
+	SetAdminActive(adminID int, active bool) error
+	SetMFASecret(adminID int, secret string) error
+	EnableMFA(adminID int, recoveryCodeHashes []string) error
+	DisableMFA(adminID int) error
+	UseMFAStep(adminID int, step int64) (accepted bool, err error)
+	UseRecoveryCode(adminID int, codeHash string) (accepted bool, err error)
+	CreateRefreshToken(adminID int, tokenHash string, expiresAt time.Time) error
+	GetRefreshToken(tokenHash string) (*AdminRefreshToken, error)
+	RotateRefreshToken(oldTokenID int, adminID int, newTokenHash string, expiresAt time.Time) (rotated bool, err error)
+	RevokeRefreshToken(tokenHash string) error
+	RevokeAllRefreshTokens(adminID int) error
+	RevokeTokenID(jti string, expiresAt time.Time) error
+	IsTokenIDRevoked(jti string) (bool, error)
+	CreatePasswordResetToken(adminID int, tokenHash string, expiresAt time.Time, email OutboundEmail) error
+	ResetPasswordWithToken(tokenHash string, passwordHash string) (adminID int, ok bool, err error)
+	SetLowStockAlerts(adminID int, enabled bool) error
+	// Returns the active admins subscribed to the low-stock digest emails.
+	GetLowStockAlertSubscribers() ([]Admin, error)
 }
 
 type SockStore interface {
-	CreateSock(sock Sock, variants []SockVariant) (int, error)
+	// Initial stock is recorded as a restock by the given admin.
+	CreateSock(sock Sock, variants []SockVariant, adminID int) (int, error)
 	SockExists(name string) (bool, error)
 	SockExistsByID(id int) (bool, error)
-	GetSocks(limit, offset int) ([]Sock, error)
+	GetSocks(limit, offset int, filters SockFilters) ([]Sock, error)
 	DeleteSock(sockID int) error
-	CountSocks() (int, error)
+	CountSocks(filters SockFilters) (int, error)
 	GetSockByID(sockID int) (*Sock, error)
 	GetSockVariants(sockID int) ([]SockVariant, error)
-	UpdateSock(sockID int, sock Sock, variants []SockVariant) error
-	SockVariantExists(sockID int, size string) (bool, error)
+	// Returns `ErrVersionConflict` when the sock is no longer at the given version.
+	// Create and update return `ErrInvalidVariantOption` or `ErrDuplicateVariant` for invalid variant options.
+	// Quantity changes are recorded as corrections by the given admin.
+	UpdateSock(sockID int, version string, sock Sock, variants []SockVariant, adminID int) error
 	GetSockVariantByID(sockVariantID int) (*SockVariant, error)
 	GetSockVariantsByID(sockVariantIDs []int) ([]SockVariant, error)
-	UpdateSockVariantQuantity(sockVariantID int, newQuantity int) error
+	// Returns the description of each variant by ID, e.g. "Size: M, Color: Red".
+	GetVariantDescriptions(sockVariantIDs []int) (map[int]string, error)
+	// Adds the movement delta to the stock of a variant and records it. Returns `ErrInsufficientStock` if the stock would go negative.
+	AdjustVariantStock(movement InventoryMovement) (*InventoryMovement, error)
+	// Newest first.
+	GetInventoryMovements(sockVariantID int, limit int, offset int) ([]InventoryMovement, error)
+	CountInventoryMovements(sockVariantID int) (int, error)
+	GetLowStockVariants() ([]LowStockVariant, error)
+	// Marks the low-stock variants that were not alerted yet as alerted, queues a digest of them for each
+	// subscriber and returns them. Variants that were restocked since their last alert are reset first, so each
+	// variant is only alerted once per shortage.
+	ClaimLowStockAlerts(subscribers []Admin) ([]LowStockVariant, error)
 	GetSimilarSocks(sockID int) ([]SimilarSock, error)
+	GetSockImages(sockID int) ([]SockImage, error)
+	GetSockImage(sockID int, imageID int) (*SockImage, error)
+	// Returns `ErrTooManyImages` when the gallery of the sock is full.
+	AddSockImage(sockID int, image SockImage) (imageID int, err error)
+	DeleteSockImage(sockID int, imageID int) error
+	// Returns `ErrInvalidImageOrder` unless the IDs are exactly the images of the sock.
+	ReorderSockImages(sockID int, imageIDs []int) error
+}
+
+type CatalogStore interface {
+	GetCategories() ([]Category, error)
+	GetCategoryByID(categoryID int) (*Category, error)
+	// Returns `ErrSlugAlreadyExists` or `ErrCategoryNotFound` when the parent does not exist.
+	CreateCategory(category Category) (categoryID int, err error)
+	// Returns `ErrSlugAlreadyExists`, `ErrCategoryNotFound` or `ErrCategoryCycle`.
+	UpdateCategory(category Category) error
+	// Socks of the category are left without one. Returns `ErrCategoryHasChildren`.
+	DeleteCategory(categoryID int) error
+	GetTags() ([]Tag, error)
+	GetTagByID(tagID int) (*Tag, error)
+	// Returns `ErrTagAlreadyExists`.
+	RenameTag(tagID int, name string) error
+	DeleteTag(tagID int) error
+	GetCollections() ([]Collection, error)
+	GetCollectionByID(collectionID int) (*Collection, error)
+	// Returns `ErrSlugAlreadyExists`.
+	CreateCollection(collection Collection) (collectionID int, err error)
+	// Returns `ErrSlugAlreadyExists`.
+	UpdateCollection(collection Collection) error
+	DeleteCollection(collectionID int) error
+	// Replaces the socks of a collection. Returns `ErrSockNotFound` if one of the socks does not exist.
+	SetCollectionSocks(collectionID int, sockIDs []int) error
+}
+
+type AttributeStore interface {
+	// Attributes and their values sorted by position.
+	GetAttributes() ([]Attribute, error)
+	GetAttributeByID(attributeID int) (*Attribute, error)
+	// Returns `ErrAttributeAlreadyExists`.
+	CreateAttribute(attribute Attribute) (attributeID int, err error)
+	// Returns `ErrAttributeAlreadyExists`.
+	UpdateAttribute(attribute Attribute) error
+	// Returns `ErrAttributeInUse` if one of its values is used by a variant.
+	DeleteAttribute(attributeID int) error
+	// Returns `ErrAttributeValueAlreadyExists`.
+	CreateAttributeValue(attributeID int, value AttributeValue) (valueID int, err error)
+	// Returns `ErrAttributeValueAlreadyExists`.
+	UpdateAttributeValue(attributeID int, value AttributeValue) error
+	// Returns `ErrAttributeInUse` if the value is used by a variant.
+	DeleteAttributeValue(attributeID int, valueID int) error
 }
 
 type OrderStore interface {
-	GetOrders(limit int, offset int, status string) ([]Order, error)
+	GetOrders(limit int, offset int, filters OrderFilters) ([]Order, error)
 	GetOrderById(orderID int) (*Order, error)
 	GetOrderItems(id int) ([]OrderItem, error)
-	CountOrders(status string) (total int, err error)
+	CountOrders(filters OrderFilters) (total int, err error)
 	GetOrderUpdates(orderID int) ([]OrderUpdate, error)
-	CreateOrderUpdate(orderID int, adminID int, message string) error
-	UpdateOrderAddress(orderID int, address UpdateAddressRequest, adminID int) error
+	CreateOrderUpdate(orderID int, adminID int, message string, isInternal bool) error
+	// Returns `ErrVersionConflict` when the order is no longer at the given version.
+	UpdateOrderAddress(orderID int, version int, address UpdateAddressRequest, adminID int) error
 	OrderExistsByID(orderID int) (bool, error)
-	UpdateOrderStatus(orderID int, adminID int, newStatus string, message string) error
-	UpdateOrderStatusNoLogs(orderID int, newStatus string) error
+	// Status changes queue the status email for the customer in the email outbox, as part of the same transaction.
+	UpdateOrderStatusIf(orderID int, adminID int, currentStatus string, newStatus string, message string) (updated bool, err error)
+	MarkOrderPaid(orderID int, paymentReference string) (updated bool, err error)
+	GetExpiredPendingOrderIDs(maxAge time.Duration) ([]int, error)
+	CancelPendingOrder(orderID int, adminID int, message string) (canceled bool, err error)
+	// Cancels the order if it is still in `currentStatus`, and returns its reserved stock.
+	CancelOrder(orderID int, adminID int, currentStatus string, message string) (canceled bool, err error)
+	GetRefunds(orderID int) ([]OrderRefund, error)
+	// CreateRefund records a pending refund, before it is issued through the payment provider.
+	CreateRefund(orderID int, adminID int, refund OrderRefund) (refundID int, err error)
+	CompleteRefund(orderID int, adminID int, refund OrderRefund) error
+	FailRefund(refundID int) error
 	GetOrderStatusByID(orderID int) (status string, err error)
-	UpdateOrderContact(orderID int, contact UpdateContactRequest, adminID int) error
+	// Returns `ErrVersionConflict` when the order is no longer at the given version.
+	UpdateOrderContact(orderID int, version int, contact UpdateContactRequest, adminID int) error
 	GetOrderByInvoice(invoiceNumber string) (*Order, error)
-	CreateOrder(items []CheckoutItem, total float64, addr Address, contact Contact) (orderID int, err error)
-	CreateOrderItem(orderID int, sockVariantID int, price float64, quantity int) error
+	GetOrderByPaymentReference(paymentReference string) (*Order, error)
+	GetOrderByInvoiceAndEmail(invoiceNumber string, email string) (*Order, error)
+	CreateOrder(items []CheckoutItem, addr Address, contact Contact, customerID *int) (orderID int, err error)
+}
+
+type CustomerStore interface {
+	GetCustomerByID(id int) (*Customer, error)
+	GetCustomerByEmail(email string) (*Customer, error)
+	CreateCustomer(customer Customer) (customerID int, err error)
 }
 
 type NewsletterStore interface {
@@ -49,3 +157,23 @@ type NewsletterStore interface {
 	EmailExists(email string) (bool, error)
 	GetEmails() ([]NewsletterEntry, error)
 }
+
+type EmailOutboxStore interface {
+	Enqueue(email OutboundEmail) error
+	ClaimPendingEmails(limit int, lease time.Duration) ([]OutboxEmail, error)
+	MarkEmailSent(emailID int) error
+	MarkEmailFailed(emailID int, sendErr error, nextAttemptAt *time.Time) error
+}
+
+// LoginThrottleStore tracks failed logins per key (e.g. a username or a client IP).
+type LoginThrottleStore interface {
+	// RecordLoginAttempt atomically counts an attempt for the key before the credentials are verified, and returns the
+	// attempts so far. The count restarts once `window` has passed without attempts. Attempts are not counted while the
+	// key is locked out, `lockedUntil` is returned instead.
+	RecordLoginAttempt(key string, window time.Duration) (attempts int, lockedUntil *time.Time, err error)
+	// ReleaseLoginAttempt stops counting the latest attempt of the key, e.g. because it succeeded.
+	ReleaseLoginAttempt(key string) error
+	LockLogin(key string, until time.Time) error
+	ClearLoginFailures(key string) error
+	RecordFailedAdminLogin(username string, ipAddress string, reason string) error
+}
@@ -1,25 +1,72 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/sockify/sockify/config"
 )
 
-// CreateJwt returns a signed JWT token for a particular user.
-func CreateJWTToken(secret []byte, userId int) (string, error) {
+// Token scopes, so a customer token can never be used as an admin token (and vice versa).
+const (
+	ScopeAdmin    = "admin"
+	ScopeCustomer = "customer"
+	// Issued after an admin's password is verified, only valid to complete the MFA step of the login
+	ScopeMFAPending = "mfa_pending"
+)
+
+// Claims are the claims within our JWT tokens. The user ID is stored in the standard `sub` claim.
+type Claims struct {
+	Scope string `json:"scope"`
+	// Only set on admin tokens
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// UserToken is the identity carried by a validated JWT token.
+type UserToken struct {
+	UserID int
+	Role   string
+	// Unique token ID (`jti` claim), used to revoke the token
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+// CreateJwt returns a signed JWT token for a particular user within a scope.
+func CreateJWTToken(secret []byte, userId int, scope string) (string, error) {
 	// `time.Duration` is in nanoseconds so we have to convert to seconds.
 	expiration := time.Duration(config.Envs.JWTExpirationInSeconds) * time.Second
+	return CreateJWTTokenWithExpiration(secret, userId, scope, expiration)
+}
+
+// CreateJWTTokenWithExpiration returns a signed JWT token for a particular user within a scope, valid for `expiration`.
+func CreateJWTTokenWithExpiration(secret []byte, userId int, scope string, expiration time.Duration) (string, error) {
+	return createToken(secret, userId, Claims{Scope: scope}, expiration)
+}
+
+// CreateAdminJWTToken returns a short-lived signed admin access token that embeds the admin's role.
+func CreateAdminJWTToken(secret []byte, adminID int, role string) (string, error) {
+	expiration := time.Duration(config.Envs.AdminAccessTokenExpirationInSeconds) * time.Second
+	return createToken(secret, adminID, Claims{Scope: ScopeAdmin, Role: role}, expiration)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"userId":    strconv.Itoa(userId),
-		"expiredAt": strconv.FormatInt(time.Now().Add(expiration).Unix(), 10),
-	})
+func createToken(secret []byte, userId int, claims Claims, expiration time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   strconv.Itoa(userId),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiration)),
+	}
 
-	tokenStr, err := token.SignedString(secret)
+	tokenStr, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
 	if err != nil {
 		return "", err
 	}
@@ -27,13 +74,61 @@ func CreateJWTToken(secret []byte, userId int) (string, error) {
 	return tokenStr, nil
 }
 
-// ValidateJWT validates a signed JWT token against our `JWT_SECRET`.
+// ValidateJWT validates a signed JWT token against our `JWT_SECRET`. Expired tokens and tokens without an expiration are rejected.
 func ValidateJWT(tokenStr string) (*jwt.Token, error) {
-	return jwt.Parse(tokenStr, func(token *jwt.Token) (any, error) {
+	return jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
 		return []byte(config.Envs.JWTSecret), nil
-	})
+	}, jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+}
+
+// ValidateUserToken validates the JWT token and returns the identity within it. The token must have been issued for the given scope.
+func ValidateUserToken(tokenStr string, scope string) (*UserToken, error) {
+	token, err := ValidateJWT(tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid JWT token provided")
+	}
+
+	if claims.Scope != scope {
+		return nil, fmt.Errorf("token scope '%v' does not match the expected scope '%v'", claims.Scope, scope)
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert sub to int: %v", err)
+	}
+
+	if claims.ID == "" {
+		return nil, fmt.Errorf("token is missing a jti")
+	}
+
+	return &UserToken{
+		UserID:    userID,
+		Role:      claims.Role,
+		TokenID:   claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+// GenerateOpaqueToken returns a new random, opaque token, e.g. a refresh token or a password reset token.
+func GenerateOpaqueToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashOpaqueToken returns the SHA-256 hash of an opaque token, which is what gets stored in the database.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// The RFC test secret "12345678901234567890", base32 encoded
+const rfcTestSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPMatchesRFC4226(t *testing.T) {
+	// RFC 4226, Appendix D
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range expected {
+		if got := hotp([]byte("12345678901234567890"), int64(counter)); got != code {
+			t.Errorf("counter %d: expected %s, got %s", counter, code, got)
+		}
+	}
+}
+
+func TestTOTPMatchesRFC6238(t *testing.T) {
+	// RFC 6238, Appendix B (SHA1). The RFC uses 8 digits, the last 6 are the 6 digit codes.
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		got, err := GenerateTOTPCode(rfcTestSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.code {
+			t.Errorf("time %d: expected %s, got %s", tt.unix, tt.code, got)
+		}
+	}
+}
+
+func TestValidateTOTPCodeAllowsOneStepOfDrift(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totpStep(now)
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, _ := GenerateTOTPCode(rfcTestSecret, now.Add(time.Duration(offset*totpPeriod)*time.Second))
+		step, valid := ValidateTOTPCode(rfcTestSecret, code, now)
+		if !valid {
+			t.Errorf("offset %d: expected the code to be accepted", offset)
+		}
+		// The step is what the replay guard records, so it has to be the step of the code rather than the current one
+		if step != current+offset {
+			t.Errorf("offset %d: expected step %d, got %d", offset, current+offset, step)
+		}
+	}
+
+	for _, offset := range []int64{-2, 2} {
+		code, _ := GenerateTOTPCode(rfcTestSecret, now.Add(time.Duration(offset*totpPeriod)*time.Second))
+		if _, valid := ValidateTOTPCode(rfcTestSecret, code, now); valid {
+			t.Errorf("offset %d: expected the code to be rejected", offset)
+		}
+	}
+}
+
+func TestValidateTOTPCodeRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	for _, code := range []string{"", "05047", "0504711", "abcdef"} {
+		if _, valid := ValidateTOTPCode(rfcTestSecret, code, now); valid {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+	if _, valid := ValidateTOTPCode("not base32!", "050471", now); valid {
+		t.Error("expected an invalid secret to be rejected")
+	}
+}
+
+func TestHashRecoveryCodeIgnoresFormatting(t *testing.T) {
+	hash := HashRecoveryCode("abcde-12345")
+	for _, code := range []string{" ABCDE-12345 ", "abcde - 12345"} {
+		if HashRecoveryCode(code) != hash {
+			t.Errorf("expected %q to match the recovery code", code)
+		}
+	}
+	if HashRecoveryCode("abcde-12346") == hash {
+		t.Error("expected a different recovery code to have a different hash")
+	}
+}
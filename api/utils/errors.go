@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/sockify/sockify/types"
+)
+
+// RequestIDHeader is set on every response by the request ID middleware, so error responses can include it.
+const RequestIDHeader = "X-Request-ID"
+
+// Error codes used when the error itself does not carry one.
+const (
+	CodeValidationFailed = "validation_failed"
+	CodeInvalidJSON      = "invalid_json"
+	CodeInternalError    = "internal_error"
+)
+
+var statusCodes = map[HttpStatus]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusPaymentRequired:       "payment_required",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusConflict:              "conflict",
+	http.StatusGone:                  "gone",
+	http.StatusPreconditionFailed:    "precondition_failed",
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusUnprocessableEntity:   "unprocessable_entity",
+	http.StatusPreconditionRequired:  "precondition_required",
+	http.StatusTooManyRequests:       "too_many_requests",
+	http.StatusBadGateway:            "bad_gateway",
+	http.StatusServiceUnavailable:    "service_unavailable",
+}
+
+func init() {
+	// Report fields by the name clients send them with
+	Validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "query"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// WriteError writes a HTTP error as a JSON `types.APIError`.
+// Server errors are logged and replaced by a generic message, so internal details (e.g. SQL errors) never reach clients.
+// Validation errors from `Validate` are broken down per field.
+func WriteError(w http.ResponseWriter, status HttpStatus, err error) {
+	apiErr := types.APIError{
+		Code:      codeForStatus(status),
+		Message:   err.Error(),
+		RequestID: w.Header().Get(RequestIDHeader),
+	}
+
+	var validationErrs validator.ValidationErrors
+	var codedErr *types.Error
+	switch {
+	case status >= http.StatusInternalServerError:
+		log.Printf("[ERROR] request %v failed with status %v: %v", apiErr.RequestID, status, err)
+		apiErr.Code = CodeInternalError
+		apiErr.Message = "an unexpected error occurred, please try again later"
+	case errors.As(err, &validationErrs):
+		apiErr.Code = CodeValidationFailed
+		apiErr.Message = "invalid request, check the details for the invalid fields"
+		apiErr.Details = fieldErrors(validationErrs)
+	case errors.As(err, &codedErr):
+		apiErr.Code = codedErr.Code
+	}
+
+	WriteJson(w, status, apiErr)
+}
+
+func codeForStatus(status HttpStatus) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternalError
+	}
+	return "error"
+}
+
+func fieldErrors(validationErrs validator.ValidationErrors) []types.FieldError {
+	details := make([]types.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		details = append(details, types.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: fieldMessage(fe),
+		})
+	}
+	return details
+}
+
+// fieldPath drops the struct name from the namespace, e.g. "CreateSockRequest.variants[0].price" becomes "variants[0].price".
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	isText := fe.Kind() == reflect.String
+	isList := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Array || fe.Kind() == reflect.Map
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(fe.Param()), ", "))
+	case "min", "gte":
+		if isText {
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		}
+		if isList {
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "max", "lte":
+		if isText {
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		}
+		if isList {
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters long", fe.Param())
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "unique":
+		if fe.Param() != "" {
+			return fmt.Sprintf("must not contain duplicate %s values", strings.ToLower(fe.Param()))
+		}
+		return "must not contain duplicates"
+	default:
+		return fmt.Sprintf("failed the '%s' validation", fe.Tag())
+	}
+}
+
+// jsonError turns JSON decoding errors into client friendly errors, without Go type names.
+func jsonError(err error) error {
+	message := "request body could not be parsed"
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		message = "missing request body"
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		message = "request body is not valid JSON"
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		message = fmt.Sprintf("field '%s' must be a %s", typeErr.Field, jsonKind(typeErr.Type))
+	}
+
+	return &types.Error{Code: CodeInvalidJSON, Message: message, Err: err}
+}
+
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "whole number"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	default:
+		return "object"
+	}
+}
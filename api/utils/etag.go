@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/sockify/sockify/types"
+)
+
+// SetETag sets the `ETag` header to the given resource version, so clients can send it back in `If-Match` when editing.
+func SetETag(w http.ResponseWriter, version string) {
+	w.Header().Set("ETag", `"`+version+`"`)
+}
+
+// IfMatch returns the resource version from the `If-Match` header, as previously set by `SetETag`.
+// Edits are rejected without it (the wildcard `*` included), otherwise editors would silently overwrite each other.
+func IfMatch(r *http.Request) (string, error) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	tag = strings.TrimPrefix(tag, "W/")
+	tag = strings.Trim(tag, `"`)
+	if tag == "" || tag == "*" {
+		return "", types.ErrPreconditionRequired
+	}
+	return tag, nil
+}
@@ -3,14 +3,18 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"github.com/sockify/sockify/types"
+	"github.com/sockify/sockify/config"
 )
 
 type HttpStatus int
@@ -18,12 +22,15 @@ type HttpStatus int
 // Validate acts a single, cached validator across the app.
 var Validate = validator.New()
 
-// ParseJson decodes the request body into the payload.
+// ParseJson decodes the request body into the payload. Errors are safe to return to clients.
 func ParseJson(r *http.Request, payload any) error {
 	if r.Body == nil {
-		return fmt.Errorf("missing request body")
+		return jsonError(io.EOF)
 	}
-	return json.NewDecoder(r.Body).Decode(payload)
+	if err := json.NewDecoder(r.Body).Decode(payload); err != nil {
+		return jsonError(err)
+	}
+	return nil
 }
 
 // WriteJson writes the payload as a JSON response.
@@ -33,9 +40,11 @@ func WriteJson(w http.ResponseWriter, status HttpStatus, payload any) error {
 	return json.NewEncoder(w).Encode(payload)
 }
 
-// WriteError writes a HTTP error as a JSON response.
-func WriteError(w http.ResponseWriter, status HttpStatus, err error) {
-	WriteJson(w, status, types.Message{Message: err.Error()})
+// WriteTooManyRequests writes a 429 error response with a `Retry-After` header, rounded up to whole seconds.
+func WriteTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	WriteError(w, http.StatusTooManyRequests, fmt.Errorf("too many requests, try again in %v seconds", seconds))
 }
 
 // GetLimitOffset returns the `limit` and `offset` values from the request's query params.
@@ -75,6 +84,25 @@ func Normalize(str string) string {
 	return strings.TrimSpace(strings.ToLower(str))
 }
 
+// Slugify converts a string to a URL friendly slug, e.g. "Winter Socks!" becomes "winter-socks".
+// Only ASCII letters and digits are kept, so the result may be empty.
+func Slugify(str string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(str) {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
 // TitleCase trims spaces, converts to lowercase, and then converts the first letter to title case.
 func TitleCase(str string) string {
 	str = strings.TrimSpace(strings.ToLower(str))
@@ -97,3 +125,50 @@ func GenerateUUID() (string, error) {
 	}
 	return u.String(), nil
 }
+
+// ParseOptionalFloat parses an optional numeric query param. Returns nil when the value is empty.
+func ParseOptionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("'%v' is not a number", value)
+	}
+	return &f, nil
+}
+
+// GetClientIP returns the IP address of the client. `X-Forwarded-For` is only honored when the API is configured to
+// trust the reverse proxies in front of it.
+func GetClientIP(r *http.Request) string {
+	if config.Envs.TrustedProxyHops > 0 {
+		if ip := clientIPFromForwardedFor(r.Header.Values("X-Forwarded-For"), int(config.Envs.TrustedProxyHops)); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientIPFromForwardedFor returns the address the outermost trusted proxy received the request from. Every proxy
+// appends the address it received the request from, so only the right-most `hops` entries can be trusted; anything
+// before them is set by the client. Returns an empty string when the header can not be trusted.
+func clientIPFromForwardedFor(headers []string, hops int) string {
+	entries := make([]string, 0)
+	for _, header := range headers {
+		for _, entry := range strings.Split(header, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	// Fewer entries than proxies means some of them were bypassed, so none of the entries can be trusted
+	if hops > len(entries) {
+		return ""
+	}
+	return entries[len(entries)-hops]
+}
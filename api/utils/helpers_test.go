@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestClientIPFromForwardedFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  []string
+		hops     int
+		expected string
+	}{
+		{"single proxy", []string{"203.0.113.7"}, 1, "203.0.113.7"},
+		{"spoofed entries are ignored", []string{"1.2.3.4, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"1.2.3.4, 203.0.113.7, 10.0.0.2"}, 2, "203.0.113.7"},
+		{"repeated headers", []string{"1.2.3.4", "203.0.113.7"}, 1, "203.0.113.7"},
+		{"blank entries", []string{" 203.0.113.7 , "}, 1, "203.0.113.7"},
+		{"fewer entries than proxies", []string{"203.0.113.7"}, 2, ""},
+		{"no header", nil, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ip := clientIPFromForwardedFor(tt.headers, tt.hops); ip != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, ip)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ export type AdminLoginRequest = z.infer<typeof adminLoginRequestSchema>;
 
 export const adminLoginResponseSchema = z.object({
   token: z.string(),
+  refreshToken: z.string().optional(),
 });
 export type AdminLoginResponse = z.infer<typeof adminLoginResponseSchema>;
 
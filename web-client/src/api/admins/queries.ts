@@ -61,3 +61,9 @@ export function useLoginAdminMutation(): UseMutationResult<
     },
   });
 }
+
+export function useLogoutAdminMutation(): UseMutationResult<void, Error, void> {
+  return useMutation({
+    mutationFn: () => adminService.logout(),
+  });
+}
@@ -1,5 +1,5 @@
-import axiosInstance from "@/shared/axios";
-import { LOCAL_STORAGE_AUTH_TOKEN_KEY } from "@/shared/constants";
+import axiosInstance, { clearAuthTokens, storeAuthTokens } from "@/shared/axios";
+import { LOCAL_STORAGE_REFRESH_TOKEN_KEY } from "@/shared/constants";
 import { decodeJwtToken } from "@/shared/utils/jwt";
 
 import {
@@ -17,6 +17,7 @@ export interface AdminService {
   getAdmins(limit: number, offset: number): Promise<AdminsPaginatedResponse>;
   getAdminById(adminId: number): Promise<Admin>;
   login(payload: AdminLoginRequest): Promise<AdminLoginResponse>;
+  logout(): Promise<void>;
 }
 
 export class HttpAdminService implements AdminService {
@@ -40,9 +41,9 @@ export class HttpAdminService implements AdminService {
 
   async login(payload: AdminLoginRequest): Promise<AuthResponse> {
     const { data } = await axiosInstance.post("/api/v1/admins/login", payload);
-    const { token } = adminLoginResponseSchema.parse(data);
+    const { token, refreshToken } = adminLoginResponseSchema.parse(data);
     // For `getAdminById` to work, `token` must be in local storage ahead of time.
-    localStorage.setItem(LOCAL_STORAGE_AUTH_TOKEN_KEY, token);
+    storeAuthTokens(token, refreshToken);
 
     const decodedToken = decodeJwtToken(token);
     if (!decodedToken) {
@@ -52,4 +53,14 @@ export class HttpAdminService implements AdminService {
 
     return { token, admin };
   }
+
+  async logout(): Promise<void> {
+    const refreshToken = localStorage.getItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY);
+    try {
+      // Revokes the refresh token too, so it can't be used to get new access tokens
+      await axiosInstance.post("/api/v1/admins/logout", { refreshToken });
+    } finally {
+      clearAuthTokens();
+    }
+  }
 }
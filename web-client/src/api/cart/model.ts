@@ -1,6 +1,5 @@
 import { z } from "zod";
 
-import { sockSizeEnumSchema } from "../inventory/model";
 import {
   orderAddressSchema,
   orderContactSchema,
@@ -24,7 +23,8 @@ export const cartItemSchema = z.object({
   name: z.string(),
   quantity: z.number(),
   price: z.number(),
-  size: sockSizeEnumSchema,
+  // Variant description, e.g. "Size: M, Color: Red"
+  variant: z.string().default(""),
   imageUrl: z.string(),
 });
 export const cartItemListSchema = z.array(cartItemSchema);
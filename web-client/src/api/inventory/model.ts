@@ -1,7 +1,29 @@
 import { z } from "zod";
 
-export const sockSizeEnumSchema = z.enum(["S", "M", "LG", "XL"]);
-export type SockSize = z.infer<typeof sockSizeEnumSchema>;
+// Code of the attribute used for sizes, its values are managed by admins
+export const SIZE_ATTRIBUTE_CODE = "size";
+
+export const attributeValueSchema = z.object({
+  id: z.number(),
+  value: z.string(),
+  position: z.number(),
+});
+export type AttributeValue = z.infer<typeof attributeValueSchema>;
+
+export const attributeSchema = z.object({
+  id: z.number(),
+  code: z.string(),
+  name: z.string(),
+  position: z.number(),
+  values: z.array(attributeValueSchema),
+  createdAt: z.string(),
+});
+export type Attribute = z.infer<typeof attributeSchema>;
+export const attributeListSchema = z.array(attributeSchema);
+
+// Attribute code to value, e.g. { size: "M", color: "Red" }
+export const variantOptionsSchema = z.record(z.string());
+export type VariantOptions = z.infer<typeof variantOptionsSchema>;
 
 export const sockCategoryEnumSchema = z.enum([
   "Sports",
@@ -13,9 +35,13 @@ export type SockCategory = z.infer<typeof sockCategoryEnumSchema>;
 
 export const sockVariantSchema = z.object({
   id: z.number().optional(),
-  size: sockSizeEnumSchema,
+  options: variantOptionsSchema,
+  // e.g. "Size: M, Color: Red", set by the server
+  description: z.string().optional(),
   price: z.number().positive(),
   quantity: z.number().int().min(0),
+  // Low-stock alerts are sent below this quantity, 0 disables them
+  reorderThreshold: z.number().int().min(0).optional(),
   createdAt: z.string().optional(),
 });
 export type SockVariant = z.infer<typeof sockVariantSchema>;
@@ -24,15 +50,36 @@ export const sockMetadataSchema = z.object({
   name: z.string().min(1, "Name is required"),
   description: z.string().min(1, "Description is required"),
   previewImageUrl: z.string().url("Invalid URL format"),
+  categoryId: z.number().nullable().optional(),
+  tags: z.array(z.string()).optional(),
 });
 export type SockMetadata = z.infer<typeof sockMetadataSchema>;
 
+export const sockImageSchema = z.object({
+  id: z.number(),
+  url: z.string(),
+  thumbnailUrl: z.string(),
+  contentType: z.string(),
+  width: z.number(),
+  height: z.number(),
+  position: z.number(),
+  createdAt: z.string(),
+});
+export type SockImage = z.infer<typeof sockImageSchema>;
+export const sockImageListSchema = z.array(sockImageSchema);
+
 export const sockSchema = z.object({
   id: z.number().optional(),
   name: z.string(),
   description: z.string(),
   previewImageUrl: z.string(),
   variants: z.array(sockVariantSchema),
+  categoryId: z.number().nullable().optional(),
+  tags: z.array(z.string()).optional(),
+  // Gallery in display order, only returned for a single sock
+  images: z.array(sockImageSchema).optional(),
+  // Read from the `ETag` header, sent back as `If-Match` when updating the sock
+  etag: z.string().optional(),
 });
 export type Sock = z.infer<typeof sockSchema>;
 
@@ -74,7 +121,7 @@ export const updateSockSchema = z.object({
 export type UpdateSockRequest = z.infer<typeof updateSockSchema>;
 
 export const addEditVariantSchema = z.object({
-  size: sockSizeEnumSchema,
+  options: variantOptionsSchema,
   price: z.number().min(0.01),
   quantity: z.number().min(0),
 });
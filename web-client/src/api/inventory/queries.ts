@@ -12,11 +12,13 @@ import toast from "react-hot-toast";
 
 import {
   AddEditVariantRequest,
+  Attribute,
   CreateSockRequest,
   CreateSockResponse,
   SimilarSock,
   Sock,
   SocksPaginatedResponse,
+  SIZE_ATTRIBUTE_CODE,
   UpdateSockRequest,
   createSockRequestSchema,
 } from "./model";
@@ -69,6 +71,20 @@ export function useGetSimilarSocks(
   return useQuery(useGetSimilarSocksOptions(sockId));
 }
 
+export function useGetAttributes(): UseQueryResult<Attribute[]> {
+  return useQuery({
+    queryKey: ["attributes"],
+    queryFn: () => sockService.getAttributes(),
+  });
+}
+
+/** Returns the allowed sizes, in display order. */
+export function useSizeOptions(): string[] {
+  const { data: attributes } = useGetAttributes();
+  const size = attributes?.find((a) => a.code === SIZE_ATTRIBUTE_CODE);
+  return size?.values.map((v) => v.value) ?? [];
+}
+
 export function useDeleteSockMutation(): UseMutationResult<
   ServerMessage,
   Error,
@@ -116,11 +132,11 @@ export function useCreateSockMutation(): UseMutationResult<
 export function useUpdateSockMutation(): UseMutationResult<
   ServerMessage,
   Error,
-  UpdateSockRequest & { id: number }
+  UpdateSockRequest & { id: number; etag?: string }
 > {
   return useMutation({
-    mutationFn: (updatedSock) =>
-      sockService.updateSockDetails(updatedSock.id, updatedSock),
+    mutationFn: ({ id, etag, ...updatedSock }) =>
+      sockService.updateSockDetails(id, updatedSock, etag),
     onSuccess: () => {
       toast.success("Sock details updated successfully");
     },
@@ -2,14 +2,19 @@ import axiosInstance from "@/shared/axios";
 import { ServerMessage, serverMessageSchema } from "@/shared/types";
 import {
   AddEditVariantRequest,
+  Attribute,
   CreateSockRequest,
   CreateSockResponse,
   SimilarSock,
   Sock,
+  SockImage,
   SocksPaginatedResponse,
   UpdateSockRequest,
+  attributeListSchema,
   createSockResponseSchema,
   similarSockListSchema,
+  sockImageListSchema,
+  sockImageSchema,
   sockSchema,
   socksPaginatedResponseSchema,
 } from "./model";
@@ -22,18 +27,25 @@ export interface InventoryService {
   createSock(payload: CreateSockRequest): Promise<CreateSockResponse>;
   updateSockDetails(
     sockId: number,
-    updatedSock: UpdateSockRequest
+    updatedSock: UpdateSockRequest,
+    etag?: string
   ): Promise<ServerMessage>;
   addEditSockVariant(
     sockId: number,
     variant: AddEditVariantRequest
   ): Promise<ServerMessage>;
+  uploadSockImage(sockId: number, image: File): Promise<SockImage>;
+  reorderSockImages(sockId: number, imageIds: number[]): Promise<SockImage[]>;
+  deleteSockImage(sockId: number, imageId: number): Promise<ServerMessage>;
+  getAttributes(): Promise<Attribute[]>;
 }
 
 export class HttpInventoryService implements InventoryService {
   async getSockById(sockId: number): Promise<Sock> {
-    const { data } = await axiosInstance.get(`/api/v1/socks/${sockId}`);
-    return sockSchema.parse(data);
+    const { data, headers } = await axiosInstance.get(
+      `/api/v1/socks/${sockId}`
+    );
+    return sockSchema.parse({ ...data, etag: headers.etag });
   }
 
   async getSocks(
@@ -65,11 +77,13 @@ export class HttpInventoryService implements InventoryService {
 
   async updateSockDetails(
     sockId: number,
-    updatedSock: UpdateSockRequest
+    updatedSock: UpdateSockRequest,
+    etag?: string
   ): Promise<ServerMessage> {
     const { data } = await axiosInstance.patch(
       `/api/v1/socks/${sockId}`,
-      updatedSock
+      updatedSock,
+      { headers: { "If-Match": etag } }
     );
     return serverMessageSchema.parse(data);
   }
@@ -84,4 +98,40 @@ export class HttpInventoryService implements InventoryService {
     );
     return serverMessageSchema.parse(data);
   }
+
+  async uploadSockImage(sockId: number, image: File): Promise<SockImage> {
+    const form = new FormData();
+    form.append("image", image);
+    const { data } = await axiosInstance.post(
+      `/api/v1/socks/${sockId}/images`,
+      form
+    );
+    return sockImageSchema.parse(data);
+  }
+
+  async reorderSockImages(
+    sockId: number,
+    imageIds: number[]
+  ): Promise<SockImage[]> {
+    const { data } = await axiosInstance.patch(
+      `/api/v1/socks/${sockId}/images`,
+      { imageIds }
+    );
+    return sockImageListSchema.parse(data);
+  }
+
+  async deleteSockImage(
+    sockId: number,
+    imageId: number
+  ): Promise<ServerMessage> {
+    const { data } = await axiosInstance.delete(
+      `/api/v1/socks/${sockId}/images/${imageId}`
+    );
+    return serverMessageSchema.parse(data);
+  }
+
+  async getAttributes(): Promise<Attribute[]> {
+    const { data } = await axiosInstance.get(`/api/v1/attributes`);
+    return attributeListSchema.parse(data);
+  }
 }
@@ -1,7 +1,5 @@
 import { z } from "zod";
 
-import { sockSizeEnumSchema } from "../inventory/model";
-
 export const stateEnumSchema = z.enum([
   "AL",
   "AK",
@@ -78,7 +76,8 @@ export const orderItemSchema = z.object({
   name: z.string(),
   price: z.number(),
   quantity: z.number(),
-  size: sockSizeEnumSchema,
+  // e.g. "Size: M, Color: Red"
+  variantDescription: z.string(),
   sockVariantId: z.number(),
 });
 export const orderItemListSchema = z.array(orderItemSchema);
@@ -103,6 +102,8 @@ export const orderSchema = z.object({
   contact: orderContactSchema,
   items: orderItemListSchema,
   createdAt: z.string().optional(),
+  // Read from the `ETag` header, sent back as `If-Match` when editing the order
+  etag: z.string().optional(),
 });
 export type Order = z.infer<typeof orderSchema>;
 
@@ -155,10 +156,12 @@ export interface CreateOrderUpdateDTO {
 }
 export interface UpdateOrderAddressDTO {
   orderId: number;
+  etag?: string;
   address: OrderAddress;
 }
 export interface UpdateOrderContactDTO {
   orderId: number;
+  etag?: string;
   contact: OrderContact;
 }
 export interface UpdateOrderStatusDTO {
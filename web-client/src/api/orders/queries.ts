@@ -114,8 +114,8 @@ export function useUpdateOrderAddressMutation(): UseMutationResult<
   const queryClient = useQueryClient();
 
   return useMutation({
-    mutationFn: ({ orderId, address }) =>
-      orderService.updateOrderAddress(orderId, address),
+    mutationFn: ({ orderId, address, etag }) =>
+      orderService.updateOrderAddress(orderId, address, etag),
     onSuccess: (_, { orderId }) => {
       toast.success("Order address updated");
 
@@ -140,8 +140,8 @@ export function useUpdateOrderContactMutation(): UseMutationResult<
   const queryClient = useQueryClient();
 
   return useMutation({
-    mutationFn: ({ orderId, contact }) =>
-      orderService.updateOrderContact(orderId, contact),
+    mutationFn: ({ orderId, contact, etag }) =>
+      orderService.updateOrderContact(orderId, contact, etag),
     onSuccess: (_, { orderId }) => {
       toast.success("Order contact updated");
 
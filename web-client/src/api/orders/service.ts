@@ -31,10 +31,12 @@ export interface OrderService {
   updateOrderAddress(
     orderId: number,
     payload: OrderAddress,
+    etag?: string,
   ): Promise<ServerMessage>;
   updateOrderContact(
     orderId: number,
     payload: OrderContact,
+    etag?: string,
   ): Promise<ServerMessage>;
   updateOrderStatus(
     orderId: number,
@@ -44,15 +46,17 @@ export interface OrderService {
 
 export class HttpOrderService implements OrderService {
   async getOrderById(orderId: number): Promise<Order> {
-    const { data } = await axiosInstance.get(`/api/v1/orders/${orderId}`);
-    return orderSchema.parse(data);
+    const { data, headers } = await axiosInstance.get(
+      `/api/v1/orders/${orderId}`,
+    );
+    return orderSchema.parse({ ...data, etag: headers.etag });
   }
 
   async getOrderByInvoice(invoiceNumber: string): Promise<Order> {
-    const { data } = await axiosInstance.get(
+    const { data, headers } = await axiosInstance.get(
       `/api/v1/orders/invoice/${invoiceNumber}`,
     );
-    return orderSchema.parse(data);
+    return orderSchema.parse({ ...data, etag: headers.etag });
   }
 
   async getOrders(
@@ -87,10 +91,12 @@ export class HttpOrderService implements OrderService {
   async updateOrderAddress(
     orderId: number,
     payload: OrderAddress,
+    etag?: string,
   ): Promise<ServerMessage> {
     const { data } = await axiosInstance.patch(
       `/api/v1/orders/${orderId}/address`,
       payload,
+      { headers: { "If-Match": etag } },
     );
     return serverMessageSchema.parse(data);
   }
@@ -98,10 +104,12 @@ export class HttpOrderService implements OrderService {
   async updateOrderContact(
     orderId: number,
     payload: OrderContact,
+    etag?: string,
   ): Promise<ServerMessage> {
     const { data } = await axiosInstance.patch(
       `/api/v1/orders/${orderId}/contact`,
       payload,
+      { headers: { "If-Match": etag } },
     );
     return serverMessageSchema.parse(data);
   }
@@ -1,5 +1,8 @@
-import { CreateSockRequest, sockSizeEnumSchema } from "@/api/inventory/model";
-import { useCreateSockMutation } from "@/api/inventory/queries";
+import { CreateSockRequest } from "@/api/inventory/model";
+import {
+  useCreateSockMutation,
+  useSizeOptions,
+} from "@/api/inventory/queries";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
 import { Plus, Trash2 } from "lucide-react";
@@ -8,8 +11,6 @@ import { useFieldArray, useForm } from "react-hook-form";
 
 import { Textarea } from "./ui/textarea";
 
-const availableSizes = sockSizeEnumSchema.options;
-
 export default function AddSockForm({
   onAddSock,
   onClose,
@@ -18,6 +19,7 @@ export default function AddSockForm({
   onClose?: () => void;
 }) {
   const createSockMutation = useCreateSockMutation();
+  const availableSizes = useSizeOptions();
   const [step, setStep] = useState(1);
 
   const {
@@ -41,7 +43,7 @@ export default function AddSockForm({
     name: "variants",
   });
 
-  const selectedSizes = watch("variants").map((item) => item.size);
+  const selectedSizes = watch("variants").map((item) => item.options.size);
   const nextSizesToSelect = availableSizes.filter(
     (size) => !selectedSizes.includes(size),
   );
@@ -99,7 +101,7 @@ export default function AddSockForm({
           {fields.map((field, index) => (
             <div key={field.id} className="grid grid-cols-4 items-center gap-2">
               <select
-                {...register(`variants.${index}.size`, {
+                {...register(`variants.${index}.options.size`, {
                   required: "Size is required",
                   validate: (value) => {
                     const occurrences = selectedSizes.filter(
@@ -108,7 +110,7 @@ export default function AddSockForm({
                     return occurrences <= 1 || "Size must be unique";
                   },
                 })}
-                defaultValue={field.size || ""}
+                defaultValue={field.options.size || ""}
                 className="rounded-md border p-2.5 text-sm dark:bg-inherit"
               >
                 <option value="" disabled>
@@ -119,7 +121,8 @@ export default function AddSockForm({
                     key={size}
                     value={size}
                     disabled={
-                      selectedSizes.includes(size) && field.size !== size
+                      selectedSizes.includes(size) &&
+                      field.options.size !== size
                     }
                   >
                     {size}
@@ -167,7 +170,11 @@ export default function AddSockForm({
             className="w-full"
             onClick={() => {
               if (nextSizesToSelect.length > 0) {
-                append({ size: nextSizesToSelect[0], quantity: 0, price: 0 });
+                append({
+                  options: { size: nextSizesToSelect[0] },
+                  quantity: 0,
+                  price: 0,
+                });
               } else {
                 console.error(
                   "Unable to add another size, no sizes available.",
@@ -34,9 +34,7 @@ export default function CartItem({
             <Link to={`/socks/${item.sockId}`}>
               <h3 className="mb-1">{item.name}</h3>
             </Link>
-            <p className="mb-1 text-sm text-gray-400">
-              <strong>Size:</strong> {item.size}
-            </p>
+            <p className="mb-1 text-sm text-gray-400">{item.variant}</p>
             <p className="text-base font-bold">${item.price.toFixed(2)}</p>
           </div>
         </div>
@@ -40,16 +40,13 @@ export default function EditVariantModal({
         <form onSubmit={handleSubmit(onSave)} id="edit-variant-form">
           <div className="space-y-4">
             <div>
-              <Label htmlFor="size">Size</Label>
+              <Label htmlFor="variant">Variant</Label>
               <Input
                 type="text"
-                {...register("size", { required: "Size is required" })}
-                id="size"
+                value={variant.description}
+                id="variant"
                 disabled
               />
-              {errors.size && (
-                <p className="text-sm text-red-500">{errors.size.message}</p>
-              )}
             </div>
 
             <div>
@@ -70,6 +67,22 @@ export default function EditVariantModal({
               )}
             </div>
 
+            <div>
+              <Label htmlFor="reorderThreshold">Reorder threshold</Label>
+              <Input
+                type="number"
+                {...register("reorderThreshold", {
+                  min: 0,
+                })}
+                id="reorderThreshold"
+                min={0}
+              />
+              <p className="text-sm text-muted-foreground">
+                Subscribed admins are emailed when the quantity drops below
+                this threshold. Use 0 to disable alerts.
+              </p>
+            </div>
+
             <div>
               <Label htmlFor="price">Price</Label>
               <Input
@@ -7,7 +7,7 @@ const item1: CartItem = {
   name: "Classic Socks",
   quantity: 1,
   price: 19.0,
-  size: "LG",
+  variant: "Size: LG",
   imageUrl: "some url",
 };
 
@@ -16,7 +16,7 @@ const item2: CartItem = {
   name: "Retro Socks",
   quantity: 2,
   price: 30.0,
-  size: "S",
+  variant: "Size: S",
   imageUrl: "some url",
 };
 
@@ -25,7 +25,7 @@ const invalidItem: CartItem = {
   name: "Out of Phase Rockers",
   quantity: -20,
   price: 30.0,
-  size: "XL",
+  variant: "Size: XL",
   imageUrl: "some url",
 };
 
@@ -112,7 +112,7 @@ export default function CartDemo() {
         {items.length > 0
           ? items.map((item) => (
               <div key={item.sockVariantId}>
-                [Q: {item.quantity}] {item.name} ({item.size}) - ${item.price}{" "}
+                [Q: {item.quantity}] {item.name} ({item.variant}) - ${item.price}{" "}
                 <Button
                   variant="destructive"
                   onClick={() =>
@@ -1,6 +1,14 @@
 import { Admin } from "@/api/admins/model";
-import { useGetAdminById, useLoginAdminMutation } from "@/api/admins/queries";
-import { LOCAL_STORAGE_AUTH_TOKEN_KEY } from "@/shared/constants";
+import {
+  useGetAdminById,
+  useLoginAdminMutation,
+  useLogoutAdminMutation,
+} from "@/api/admins/queries";
+import { AUTH_TOKENS_CHANGED_EVENT, clearAuthTokens } from "@/shared/axios";
+import {
+  LOCAL_STORAGE_AUTH_TOKEN_KEY,
+  LOCAL_STORAGE_REFRESH_TOKEN_KEY,
+} from "@/shared/constants";
 import { decodeJwtToken, isJwtTokenExpired } from "@/shared/utils/jwt";
 import {
   ReactNode,
@@ -17,12 +25,12 @@ interface AuthContextType {
   token: string | null;
   /** Details of the currently logged in admin. */
   admin: Admin | null;
-  /** True if the JWT token is valid, and is not expired. */
+  /** True if the JWT token is not expired, or can be refreshed. */
   isAuthenticated: boolean;
   /** Logs in an admin. Throws an error if logging in was unsuccessful. */
   login: (username: string, password: string) => Promise<void>;
-  /** Logs out the currently signed in admin. */
-  logout: () => void;
+  /** Logs out the currently signed in admin, revoking both of their tokens. */
+  logout: () => Promise<void>;
   /** True if the admin is currently logging in. */
   isLoggingIn: boolean;
   /** True if the token is being fetched from local storage during initialization. */
@@ -37,11 +45,16 @@ interface AuthProviderProps {
 export const AuthProvider = ({ children }: AuthProviderProps) => {
   const navigate = useNavigate();
   const loginMutation = useLoginAdminMutation();
+  const logoutMutation = useLogoutAdminMutation();
 
   const [isFetchingToken, setIsFetchingToken] = useState(true);
   const [token, setToken] = useState<string | null>(null);
+  const [refreshToken, setRefreshToken] = useState<string | null>(null);
   const [admin, setAdmin] = useState<Admin | null>(null);
-  const isAuthenticated = Boolean(token && !isJwtTokenExpired(token));
+  // Expired access tokens are refreshed by the API client on the next request
+  const isAuthenticated = Boolean(
+    token && (!isJwtTokenExpired(token) || refreshToken),
+  );
   const isLoggingIn = loginMutation.isPending;
 
   const adminId = token ? (decodeJwtToken(token)?.userId ?? 0) : 0;
@@ -49,17 +62,39 @@ export const AuthProvider = ({ children }: AuthProviderProps) => {
 
   useEffect(() => {
     const storedToken = localStorage.getItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+    const storedRefreshToken = localStorage.getItem(
+      LOCAL_STORAGE_REFRESH_TOKEN_KEY,
+    );
 
     if (storedToken) {
-      if (isJwtTokenExpired(storedToken)) {
-        localStorage.removeItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+      if (isJwtTokenExpired(storedToken) && !storedRefreshToken) {
+        clearAuthTokens();
       } else {
         setToken(storedToken);
+        setRefreshToken(storedRefreshToken);
       }
     }
     setIsFetchingToken(false);
   }, []);
 
+  // Keeps the tokens in sync with the API client, which refreshes them (or
+  // clears them once the session can't be refreshed anymore).
+  useEffect(() => {
+    const syncTokens = () => {
+      const storedToken = localStorage.getItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+      setToken(storedToken);
+      setRefreshToken(localStorage.getItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY));
+      if (!storedToken) {
+        setAdmin(null);
+      }
+    };
+
+    window.addEventListener(AUTH_TOKENS_CHANGED_EVENT, syncTokens);
+    return () => {
+      window.removeEventListener(AUTH_TOKENS_CHANGED_EVENT, syncTokens);
+    };
+  }, []);
+
   useEffect(() => {
     if (adminQuery.data) {
       setAdmin(adminQuery.data);
@@ -79,13 +114,18 @@ export const AuthProvider = ({ children }: AuthProviderProps) => {
     });
 
     setToken(token);
-    localStorage.setItem(LOCAL_STORAGE_AUTH_TOKEN_KEY, token);
     setAdmin(admin);
   };
 
-  const logout = () => {
+  const logout = async () => {
+    try {
+      // Both tokens are cleared even if they could not be revoked
+      await logoutMutation.mutateAsync();
+    } catch (e: unknown) {
+      console.error(`Unable to revoke the auth tokens -> ${e}`);
+    }
     setToken(null);
-    localStorage.removeItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+    setRefreshToken(null);
     setAdmin(null);
 
     navigate("/admin/login");
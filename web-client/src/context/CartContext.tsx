@@ -24,6 +24,11 @@ interface CartContextType {
   /** True when the cart is being retrieved from local storage, false otherwise. */
   isLoading: boolean;
 }
+/** Quoted item name followed by its variant, if any. */
+function describeItem(item: CartItem): string {
+  return item.variant ? `"${item.name}" (${item.variant})` : `"${item.name}"`;
+}
+
 const CartContext = createContext<CartContextType | undefined>(undefined);
 
 interface CartProviderProps {
@@ -37,7 +42,7 @@ export function CartProvider({ children }: CartProviderProps) {
   const addItem = (newItem: CartItem) => {
     if (newItem.quantity < 1) {
       toast.error(
-        `Unable to add "${newItem.name}" (${newItem.size}) to the cart since the quantity is less than one`,
+        `Unable to add ${describeItem(newItem)} to the cart since the quantity is less than one`,
       );
       return;
     }
@@ -66,7 +71,7 @@ export function CartProvider({ children }: CartProviderProps) {
     });
 
     toast.success(
-      `Added ${newItem.quantity}x "${newItem.name}" (${newItem.size})`,
+      `Added ${newItem.quantity}x ${describeItem(newItem)}`,
     );
   };
 
@@ -87,7 +92,7 @@ export function CartProvider({ children }: CartProviderProps) {
 
       if (idx === -1) {
         console.error(
-          `"${updatedItem.name}" (${updatedItem.size}) is not in the cart`,
+          `${describeItem(updatedItem)} is not in the cart`,
         );
         return currentItems;
       }
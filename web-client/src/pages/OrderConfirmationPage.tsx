@@ -98,7 +98,8 @@ export default function OrderConfirmationPage() {
                   className="mb-2 flex justify-between"
                 >
                   <span>
-                    {item.quantity}x {item.name} ({item.size})
+                    {item.quantity}x {item.name}
+                    {item.variantDescription && ` (${item.variantDescription})`}
                   </span>
                   <span>${(item.quantity * item.price).toFixed(2)}</span>
                 </div>
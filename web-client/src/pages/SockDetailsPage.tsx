@@ -65,7 +65,7 @@ export default function SockDetailsPage() {
       imageUrl: sock.previewImageUrl,
       quantity: selectedQuantity,
       price: selectedVariant.price,
-      size: selectedVariant.size,
+      variant: selectedVariant.description ?? "",
     };
 
     addItem(item);
@@ -128,18 +128,19 @@ export default function SockDetailsPage() {
 
             {!isOutOfStock && (
               <div className="my-4">
-                <label className="mb-1 block font-medium">Size</label>
-                <div className="flex space-x-4">
+                <label className="mb-1 block font-medium">Variant</label>
+                <div className="flex flex-wrap gap-4">
                   {sock!.variants.map((variant) => (
                     <Button
                       key={variant.id!}
-                      variant={`${selectedVariant?.size === variant.size ? "default" : "outline"}`}
-                      size="icon"
+                      variant={`${selectedVariant?.id === variant.id ? "default" : "outline"}`}
+                      title={variant.description}
                       onClick={() => handleSizeChange(variant)}
                       className="min-w-12"
                       disabled={variant.quantity < 1}
                     >
-                      {variant.size}
+                      {Object.values(variant.options).join(" / ") ||
+                        "One size"}
                     </Button>
                   ))}
                 </div>
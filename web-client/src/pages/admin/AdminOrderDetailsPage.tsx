@@ -162,7 +162,11 @@ export default function AdminOrderDetailsPage() {
       state: data.state,
       zipcode: data.zipcode.trim(),
     };
-    const params: UpdateOrderAddressDTO = { orderId: orderIdNumber, address };
+    const params: UpdateOrderAddressDTO = {
+      orderId: orderIdNumber,
+      etag: order?.etag,
+      address,
+    };
 
     await updateAddressMutation.mutateAsync(params);
     setIsUpdateAddressOpen(false);
@@ -176,7 +180,11 @@ export default function AdminOrderDetailsPage() {
       phone: data.phone.trim(),
       email: data.email.trim(),
     };
-    const params: UpdateOrderContactDTO = { orderId: orderIdNumber, contact };
+    const params: UpdateOrderContactDTO = {
+      orderId: orderIdNumber,
+      etag: order?.etag,
+      contact,
+    };
 
     await updateContactMutation.mutateAsync(params);
     setIsUpdateContactOpen(false);
@@ -612,7 +620,8 @@ export default function AdminOrderDetailsPage() {
               {order!.items.map((item) => (
                 <TableRow key={item.sockVariantId}>
                   <TableCell>
-                    {item.name} ({item.size})
+                    {item.name}
+                    {item.variantDescription && ` (${item.variantDescription})`}
                   </TableCell>
                   <TableCell>{item.quantity}</TableCell>
                   <TableCell>${item.price.toFixed(2)}</TableCell>
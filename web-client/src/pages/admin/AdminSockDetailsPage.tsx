@@ -1,10 +1,9 @@
+import { Sock, SockVariant, UpdateSockRequest } from "@/api/inventory/model";
 import {
-  Sock,
-  SockVariant,
-  UpdateSockRequest,
-  sockSizeEnumSchema,
-} from "@/api/inventory/model";
-import { useGetSockById, useUpdateSockMutation } from "@/api/inventory/queries";
+  useGetSockById,
+  useSizeOptions,
+  useUpdateSockMutation,
+} from "@/api/inventory/queries";
 import AddSizeModal from "@/components/AddSizeModal";
 import EditItemModal from "@/components/EditItemModal";
 import EditVariantModal from "@/components/EditVariantModal";
@@ -26,7 +25,6 @@ import toast from "react-hot-toast";
 import { useNavigate, useParams } from "react-router-dom";
 
 const UNKNOWN = "Unknown";
-const availableSizes = sockSizeEnumSchema.options;
 
 export default function AdminSockDetailsPage() {
   const navigate = useNavigate();
@@ -46,6 +44,7 @@ export default function AdminSockDetailsPage() {
   const [currentVariants, setCurrentVariants] = useState<SockVariant[]>([]);
 
   const updateSockMutation = useUpdateSockMutation();
+  const availableSizes = useSizeOptions();
 
   useEffect(() => {
     if (sock?.variants) {
@@ -60,12 +59,15 @@ export default function AdminSockDetailsPage() {
           name: updatedSock.name,
           description: updatedSock.description,
           previewImageUrl: updatedSock.previewImageUrl,
+          categoryId: sock?.categoryId,
+          tags: sock?.tags,
         },
         variants: updatedSock.variants,
       };
 
       await updateSockMutation.mutateAsync({
         id: numericSockId,
+        etag: sock?.etag,
         ...payload,
       });
 
@@ -82,10 +84,7 @@ export default function AdminSockDetailsPage() {
     try {
       const updatedVariants: SockVariant[] = [
         ...currentVariants,
-        {
-          ...newVariant,
-          size: newVariant.size as SockVariant["size"],
-        },
+        newVariant,
       ];
       setCurrentVariants(updatedVariants);
 
@@ -95,6 +94,8 @@ export default function AdminSockDetailsPage() {
           name: sock!.name,
           description: sock!.description,
           previewImageUrl: sock!.previewImageUrl,
+          categoryId: sock!.categoryId,
+          tags: sock!.tags,
         },
         variants: updatedVariants,
       });
@@ -121,6 +122,8 @@ export default function AdminSockDetailsPage() {
           name: sock!.name,
           description: sock!.description,
           previewImageUrl: sock!.previewImageUrl,
+          categoryId: sock!.categoryId,
+          tags: sock!.tags,
         },
         variants: updatedVariants,
       });
@@ -142,7 +145,8 @@ export default function AdminSockDetailsPage() {
   }
 
   const nextSizesToSelect = availableSizes.filter(
-    (size) => !currentVariants.some((variant) => variant.size === size),
+    (size) =>
+      !currentVariants.some((variant) => variant.options.size === size),
   );
   const dateAdded =
     sock.variants.length > 0
@@ -212,7 +216,7 @@ export default function AdminSockDetailsPage() {
           <Table>
             <thead className="text-left text-muted-foreground">
               <tr>
-                <th className="px-4 py-2">Size</th>
+                <th className="px-4 py-2">Variant</th>
                 <th className="px-4 py-2">Quantity</th>
                 <th className="px-4 py-2">Price</th>
                 <th className="px-4 py-2 text-center">Actions</th>
@@ -221,7 +225,7 @@ export default function AdminSockDetailsPage() {
             <tbody>
               {currentVariants.map((variant) => (
                 <tr key={variant.id} className="border-t">
-                  <td className="px-4 py-2">{variant.size}</td>
+                  <td className="px-4 py-2">{variant.description}</td>
                   <td className="px-4 py-2">{variant.quantity}</td>
                   <td className="px-4 py-2">${variant.price.toFixed(2)}</td>
                   <td className="px-4 py-2 text-center">
@@ -275,9 +279,11 @@ export default function AdminSockDetailsPage() {
             handleEditVariant({
               ...data,
               id: editVariant.id,
-              size: data.size as SockVariant["size"],
+              options: editVariant.options,
               price: parseFloat(data.price.toString()),
               quantity: parseInt(data.quantity.toString(), 10),
+              reorderThreshold:
+                parseInt(String(data.reorderThreshold ?? 0), 10) || 0,
             });
           }}
         />
@@ -300,7 +306,7 @@ export default function AdminSockDetailsPage() {
             }
 
             handleAddVariant({
-              size: data.size as SockVariant["size"],
+              options: { size: data.size },
               price: parsedPrice,
               quantity: parsedQuantity,
             });
@@ -1,17 +1,110 @@
-import { LOCAL_STORAGE_AUTH_TOKEN_KEY } from "@/shared/constants";
-import axios from "axios";
+import {
+  LOCAL_STORAGE_AUTH_TOKEN_KEY,
+  LOCAL_STORAGE_REFRESH_TOKEN_KEY,
+} from "@/shared/constants";
+import { decodeJwtToken } from "@/shared/utils/jwt";
+import axios, { AxiosError, InternalAxiosRequestConfig } from "axios";
+import { z } from "zod";
+
+/** Event dispatched on `window` whenever the auth tokens are stored or cleared. */
+export const AUTH_TOKENS_CHANGED_EVENT = "auth-tokens-changed";
+
+/** Access tokens expiring within this many seconds are refreshed before the request is sent. */
+const REFRESH_LEEWAY_IN_SECONDS = 30;
+
+const REFRESH_URL = "/api/v1/admins/refresh";
+
+const refreshResponseSchema = z.object({
+  token: z.string(),
+  refreshToken: z.string(),
+});
+
+/** Stores the access token, and the refresh token when one is given. */
+export function storeAuthTokens(token: string, refreshToken?: string) {
+  localStorage.setItem(LOCAL_STORAGE_AUTH_TOKEN_KEY, token);
+  if (refreshToken) {
+    localStorage.setItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY, refreshToken);
+  }
+  window.dispatchEvent(new Event(AUTH_TOKENS_CHANGED_EVENT));
+}
+
+/** Removes both the access and the refresh token, which signs the admin out. */
+export function clearAuthTokens() {
+  localStorage.removeItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+  localStorage.removeItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY);
+  window.dispatchEvent(new Event(AUTH_TOKENS_CHANGED_EVENT));
+}
+
+let pendingRefresh: Promise<string | null> | null = null;
+
+/**
+ * Exchanges the stored refresh token for a new access and refresh token.
+ * Concurrent calls share the same request, since refresh tokens can only be used once.
+ * @returns the new access token, or `null` if the session could not be refreshed (both tokens are cleared).
+ */
+export function refreshAuthTokens(): Promise<string | null> {
+  if (!pendingRefresh) {
+    pendingRefresh = doRefreshAuthTokens().finally(() => {
+      pendingRefresh = null;
+    });
+  }
+  return pendingRefresh;
+}
+
+async function doRefreshAuthTokens(): Promise<string | null> {
+  const refreshToken = localStorage.getItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY);
+  if (!refreshToken) {
+    return null;
+  }
+
+  try {
+    // Not sent through `axiosInstance`, so a failed refresh is never retried
+    const { data } = await axios.post(REFRESH_URL, { refreshToken });
+    const tokens = refreshResponseSchema.parse(data);
+    storeAuthTokens(tokens.token, tokens.refreshToken);
+    return tokens.token;
+  } catch (e: unknown) {
+    console.error(`Unable to refresh the auth token -> ${e}`);
+    clearAuthTokens();
+    return null;
+  }
+}
+
+function expiresSoon(token: string): boolean {
+  const decodedToken = decodeJwtToken(token);
+  if (!decodedToken) {
+    return true;
+  }
+
+  const currentTimestampInSeconds = Math.floor(Date.now() / 1000);
+  return (
+    decodedToken.expiredAt - REFRESH_LEEWAY_IN_SECONDS <
+    currentTimestampInSeconds
+  );
+}
 
 /**
  * Global axios instance (Singleton) that intercepts requests and adds
  * the current Auth token (from local storage).
+ *
+ * The token is refreshed before it expires, and requests rejected with a 401
+ * are retried once with a refreshed token.
  */
 const axiosInstance = axios.create({
   baseURL: "",
 });
 
 axiosInstance.interceptors.request.use(
-  (config) => {
-    const token = localStorage.getItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+  async (config) => {
+    let token = localStorage.getItem(LOCAL_STORAGE_AUTH_TOKEN_KEY);
+
+    if (
+      token &&
+      expiresSoon(token) &&
+      localStorage.getItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY)
+    ) {
+      token = await refreshAuthTokens();
+    }
 
     if (token) {
       config.headers.Authorization = `Bearer ${token}`;
@@ -24,4 +117,31 @@ axiosInstance.interceptors.request.use(
   },
 );
 
+interface RetriableRequestConfig extends InternalAxiosRequestConfig {
+  isRetry?: boolean;
+}
+
+axiosInstance.interceptors.response.use(
+  (response) => response,
+  async (error: AxiosError) => {
+    const config = error.config as RetriableRequestConfig | undefined;
+
+    if (
+      error.response?.status === 401 &&
+      config &&
+      !config.isRetry &&
+      localStorage.getItem(LOCAL_STORAGE_REFRESH_TOKEN_KEY)
+    ) {
+      const token = await refreshAuthTokens();
+      if (token) {
+        config.isRetry = true;
+        config.headers.Authorization = `Bearer ${token}`;
+        return axiosInstance(config);
+      }
+    }
+
+    return Promise.reject(error);
+  },
+);
+
 export default axiosInstance;
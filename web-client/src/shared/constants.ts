@@ -1,5 +1,6 @@
 // Local storage
 export const LOCAL_STORAGE_AUTH_TOKEN_KEY = "token";
+export const LOCAL_STORAGE_REFRESH_TOKEN_KEY = "refreshToken";
 export const LOCAL_STORAGE_CART_ITEMS_KEY = "cartItems";
 
 // URLs
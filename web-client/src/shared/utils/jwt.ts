@@ -8,6 +8,14 @@ export interface DecodedAuthToken {
   expiredAt: number;
 }
 
+/** The standard JWT claims issued by the API. */
+interface JwtClaims {
+  /** ID of the user, as a string. */
+  sub: string;
+  /** Expiration time in seconds since Unix epoch. */
+  exp: number;
+}
+
 /**
  * Decodes a valid JWT token's public claims.
  * @param token string token representation
@@ -16,7 +24,8 @@ export interface DecodedAuthToken {
 export function decodeJwtToken(token: string): DecodedAuthToken | null {
   let decodedToken: DecodedAuthToken | null = null;
   try {
-    decodedToken = jwtDecode(token);
+    const claims = jwtDecode<JwtClaims>(token);
+    decodedToken = { userId: Number(claims.sub), expiredAt: claims.exp };
   } catch (e: unknown) {
     console.error(`Unable to decode JWT token "${token}" -> ${e}`);
   }